@@ -0,0 +1,303 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	configTagName  = "config"
+	defaultTagName = "default"
+
+	tagOptionRequired   = "required"
+	tagOptionOmitEmpty  = "omitempty"
+	tagOptionBinarySize = "binarysize"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// boundField is a field of a struct that is bound to a single configuration key.
+type boundField struct {
+	key          Key
+	value        reflect.Value
+	required     bool
+	omitEmpty    bool
+	binarySize   bool
+	defaultValue *string
+}
+
+// Unmarshal binds the entries of the config to the struct the target points to.
+//
+// The key of a field is defined by the "config" struct tag, e.g. `config:"memory_limit"`. Fields without tag use their
+// name in lower case, fields tagged with `config:"-"` are ignored. Nested structs map onto the "/"-separated key
+// hierarchy, e.g. the field `config:"memory_limit"` of a struct field tagged with `config:"container_config"` is bound
+// to the key "container_config/memory_limit". Embedded structs without tag are bound to the keys of the embedding
+// struct.
+//
+// Further tag options:
+//   - required: Unmarshal fails if neither the key nor a default exists, e.g. `config:"url,required"`
+//   - binarysize: an integer field is parsed with a binary measurement unit, e.g. "512m"
+//   - omitempty: Marshal skips the field if it has its zero value
+//
+// A default value is defined by the "default" struct tag, e.g. `default:"WARN"`. It is used if the key does not exist.
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration and string slices (comma separated).
+// All errors of all fields are returned at once.
+func Unmarshal(cfg Config, target any) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	fields, err := collectBoundFields("", targetValue.Elem())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, field := range fields {
+		value, ok := cfg.Get(field.key)
+		if !ok && field.defaultValue != nil {
+			value, ok = Value(*field.defaultValue), true
+		}
+
+		if !ok {
+			if field.required {
+				errs = append(errs, fmt.Errorf("required key %q: %w", field.key, ErrKeyNotFound))
+			}
+
+			continue
+		}
+
+		if setErr := setFieldValue(field, value); setErr != nil {
+			errs = append(errs, setErr)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Marshal converts the given struct or pointer to a struct to config entries.
+// The keys of the fields are defined in the same way as for Unmarshal.
+func Marshal(source any) (Entries, error) {
+	sourceValue := reflect.ValueOf(source)
+	if sourceValue.Kind() == reflect.Pointer && !sourceValue.IsNil() {
+		sourceValue = sourceValue.Elem()
+	}
+
+	if sourceValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("source must be a struct or a non-nil pointer to a struct, got %T", source)
+	}
+
+	fields, err := collectBoundFields("", sourceValue)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(Entries, len(fields))
+	var errs []error
+	for _, field := range fields {
+		if field.omitEmpty && field.value.IsZero() {
+			continue
+		}
+
+		value, formatErr := formatFieldValue(field)
+		if formatErr != nil {
+			errs = append(errs, formatErr)
+			continue
+		}
+
+		entries[field.key] = value
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return entries, nil
+}
+
+func collectBoundFields(prefix Key, structValue reflect.Value) ([]boundField, error) {
+	var fields []boundField
+
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		fieldType := structType.Field(i)
+		// like encoding/json, exported fields of embedded structs are bound even if the embedded type is unexported
+		isEmbeddedStruct := fieldType.Anonymous && fieldType.Type.Kind() == reflect.Struct
+		if !fieldType.IsExported() && !isEmbeddedStruct {
+			continue
+		}
+
+		tag, hasTag := fieldType.Tag.Lookup(configTagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = strings.ToLower(fieldType.Name)
+		}
+
+		fieldValue := structValue.Field(i)
+		if fieldType.Type.Kind() == reflect.Struct {
+			nestedPrefix := prefix + Key(name) + keySeparator
+			if isEmbeddedStruct && !hasTag {
+				nestedPrefix = prefix
+			}
+
+			nestedFields, err := collectBoundFields(nestedPrefix, fieldValue)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, nestedFields...)
+			continue
+		}
+
+		if !isSupportedFieldType(fieldType.Type) {
+			return nil, fmt.Errorf("field %s of key %q has unsupported type %s", fieldType.Name, prefix+Key(name), fieldType.Type)
+		}
+
+		field := boundField{key: prefix + Key(name), value: fieldValue}
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case tagOptionRequired:
+				field.required = true
+			case tagOptionOmitEmpty:
+				field.omitEmpty = true
+			case tagOptionBinarySize:
+				field.binarySize = true
+			}
+		}
+
+		if defaultValue, ok := fieldType.Tag.Lookup(defaultTagName); ok {
+			field.defaultValue = &defaultValue
+		}
+
+		if field.binarySize && !isIntKind(fieldType.Type.Kind()) {
+			return nil, fmt.Errorf("field %s of key %q must be an integer to be used as binary size", fieldType.Name, field.key)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func isSupportedFieldType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return isIntKind(t.Kind()) || isUintKind(t.Kind())
+	}
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
+
+func setFieldValue(field boundField, value Value) error {
+	fieldType := field.value.Type()
+	parseErr := func(typeName string, err error) error {
+		return &ParseError{Key: field.key, Value: value, Type: typeName, Err: err}
+	}
+
+	switch kind := fieldType.Kind(); {
+	case kind == reflect.String:
+		field.value.SetString(value.String())
+	case kind == reflect.Bool:
+		b, err := strconv.ParseBool(value.String())
+		if err != nil {
+			return parseErr("bool", err)
+		}
+		field.value.SetBool(b)
+	case fieldType == durationType:
+		d, err := time.ParseDuration(value.String())
+		if err != nil {
+			return parseErr("duration", err)
+		}
+		field.value.SetInt(int64(d))
+	case field.binarySize:
+		size, err := parseBinarySize(value)
+		if err == nil && field.value.OverflowInt(size) {
+			err = strconv.ErrRange
+		}
+		if err != nil {
+			return parseErr("binary size", err)
+		}
+		field.value.SetInt(size)
+	case isIntKind(kind):
+		i, err := strconv.ParseInt(value.String(), 10, fieldType.Bits())
+		if err != nil {
+			return parseErr(fieldType.String(), err)
+		}
+		field.value.SetInt(i)
+	case isUintKind(kind):
+		u, err := strconv.ParseUint(value.String(), 10, fieldType.Bits())
+		if err != nil {
+			return parseErr(fieldType.String(), err)
+		}
+		field.value.SetUint(u)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		f, err := strconv.ParseFloat(value.String(), fieldType.Bits())
+		if err != nil {
+			return parseErr(fieldType.String(), err)
+		}
+		field.value.SetFloat(f)
+	case kind == reflect.Slice:
+		elements := parseStringSlice(value)
+		slice := reflect.MakeSlice(fieldType, len(elements), len(elements))
+		for i, element := range elements {
+			slice.Index(i).SetString(element)
+		}
+		field.value.Set(slice)
+	}
+
+	return nil
+}
+
+func formatFieldValue(field boundField) (Value, error) {
+	fieldType := field.value.Type()
+
+	switch kind := fieldType.Kind(); {
+	case kind == reflect.String:
+		return Value(field.value.String()), nil
+	case kind == reflect.Bool:
+		return Value(strconv.FormatBool(field.value.Bool())), nil
+	case fieldType == durationType:
+		return Value(time.Duration(field.value.Int()).String()), nil
+	case field.binarySize:
+		if field.value.Int() < 0 {
+			return "", fmt.Errorf("binary size of key %q must not be negative", field.key)
+		}
+		return Value(formatBinarySize(field.value.Int())), nil
+	case isIntKind(kind):
+		return Value(strconv.FormatInt(field.value.Int(), 10)), nil
+	case isUintKind(kind):
+		return Value(strconv.FormatUint(field.value.Uint(), 10)), nil
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return Value(strconv.FormatFloat(field.value.Float(), 'f', -1, fieldType.Bits())), nil
+	default:
+		elements := make([]string, field.value.Len())
+		for i := range elements {
+			element := field.value.Index(i).String()
+			if strings.Contains(element, stringSliceSeparator) {
+				return "", fmt.Errorf("element %q of key %q must not contain %q", element, field.key, stringSliceSeparator)
+			}
+			elements[i] = strings.TrimSpace(element)
+		}
+		return Value(strings.Join(elements, stringSliceSeparator)), nil
+	}
+}
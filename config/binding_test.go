@@ -0,0 +1,152 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLogging struct {
+	Root string `config:"root" default:"WARN"`
+}
+
+type testContainerConfig struct {
+	MemoryLimit          int64   `config:"memory_limit,binarysize,omitempty"`
+	JavaMaxRamPercentage float64 `config:"java_max_ram_percentage" default:"25.0"`
+}
+
+type testCommon struct {
+	Replicas uint8
+}
+
+type testDoguSettings struct {
+	testCommon
+	URL             string              `config:"url,required"`
+	Debug           bool                `config:"debug"`
+	Timeout         time.Duration       `config:"timeout" default:"30s"`
+	Hosts           []string            `config:"hosts"`
+	Logging         testLogging         `config:"logging"`
+	ContainerConfig testContainerConfig `config:"container_config"`
+	Ignored         string              `config:"-"`
+	unexported      string
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("should bind entries to struct", func(t *testing.T) {
+		cfg := CreateConfig(Entries{
+			"url":                           "https://example.com",
+			"debug":                         "true",
+			"hosts":                         "a.example.com, b.example.com",
+			"replicas":                      "3",
+			"logging/root":                  "INFO",
+			"container_config/memory_limit": "512m",
+			"-":                             "ignored",
+		})
+
+		var settings testDoguSettings
+		err := Unmarshal(cfg, &settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, testDoguSettings{
+			testCommon:      testCommon{Replicas: 3},
+			URL:             "https://example.com",
+			Debug:           true,
+			Timeout:         30 * time.Second,
+			Hosts:           []string{"a.example.com", "b.example.com"},
+			Logging:         testLogging{Root: "INFO"},
+			ContainerConfig: testContainerConfig{MemoryLimit: 512 * 1024 * 1024, JavaMaxRamPercentage: 25},
+		}, settings)
+	})
+
+	t.Run("should return all errors", func(t *testing.T) {
+		cfg := CreateConfig(Entries{
+			"debug":    "maybe",
+			"replicas": "300",
+		})
+
+		var settings testDoguSettings
+		err := Unmarshal(cfg, &settings)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		assert.ErrorContains(t, err, `required key "url"`)
+		assert.ErrorContains(t, err, `value "maybe" of key "debug" is not a valid bool`)
+		assert.ErrorContains(t, err, `value "300" of key "replicas" is not a valid uint8`)
+	})
+
+	t.Run("should fail for invalid target", func(t *testing.T) {
+		var settings testDoguSettings
+		assert.ErrorContains(t, Unmarshal(CreateConfig(Entries{}), settings), "target must be a non-nil pointer to a struct")
+		assert.ErrorContains(t, Unmarshal(CreateConfig(Entries{}), (*testDoguSettings)(nil)), "target must be a non-nil pointer to a struct")
+	})
+
+	t.Run("should fail for unsupported field type", func(t *testing.T) {
+		var target struct {
+			Values map[string]string `config:"values"`
+		}
+		assert.ErrorContains(t, Unmarshal(CreateConfig(Entries{}), &target), `field Values of key "values" has unsupported type map[string]string`)
+	})
+
+	t.Run("should fail for binary size on non-integer field", func(t *testing.T) {
+		var target struct {
+			Size string `config:"size,binarysize"`
+		}
+		assert.ErrorContains(t, Unmarshal(CreateConfig(Entries{}), &target), `must be an integer to be used as binary size`)
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("should convert struct to entries", func(t *testing.T) {
+		settings := testDoguSettings{
+			testCommon:      testCommon{Replicas: 2},
+			URL:             "https://example.com",
+			Timeout:         time.Minute,
+			Hosts:           []string{"a", "b"},
+			Logging:         testLogging{Root: "ERROR"},
+			ContainerConfig: testContainerConfig{JavaMaxRamPercentage: 50.5},
+			Ignored:         "ignored",
+		}
+
+		entries, err := Marshal(&settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, Entries{
+			"replicas":     "2",
+			"url":          "https://example.com",
+			"debug":        "false",
+			"timeout":      "1m0s",
+			"hosts":        "a,b",
+			"logging/root": "ERROR",
+			"container_config/java_max_ram_percentage": "50.5",
+		}, entries)
+
+		var roundTrip testDoguSettings
+		require.NoError(t, Unmarshal(CreateConfig(entries), &roundTrip))
+		settings.Ignored = ""
+		assert.Equal(t, settings, roundTrip)
+	})
+
+	t.Run("should format binary size", func(t *testing.T) {
+		entries, err := Marshal(testContainerConfig{MemoryLimit: 1024 * 1024})
+
+		require.NoError(t, err)
+		assert.Equal(t, Value("1m"), entries["memory_limit"])
+	})
+
+	t.Run("should return all errors", func(t *testing.T) {
+		_, err := Marshal(testDoguSettings{
+			Hosts:           []string{"a,b"},
+			ContainerConfig: testContainerConfig{MemoryLimit: -1},
+		})
+
+		assert.ErrorContains(t, err, `element "a,b" of key "hosts" must not contain ","`)
+		assert.ErrorContains(t, err, `binary size of key "container_config/memory_limit" must not be negative`)
+	})
+
+	t.Run("should fail for invalid source", func(t *testing.T) {
+		_, err := Marshal("test")
+		assert.ErrorContains(t, err, "source must be a struct or a non-nil pointer to a struct, got string")
+	})
+}
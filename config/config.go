@@ -56,7 +56,9 @@ type DiffResult struct {
 // Config represents a general configuration with entries and change history.
 // PersistenceContext is used by a repository to detect conflicts due to remote changes.
 type Config struct {
-	entries            Entries
+	entries Entries
+	// base contains the entries the config was created with. It is used as common ancestor when merging changes.
+	base               Entries
 	changeHistory      []Change
 	PersistenceContext any
 	// this is needed for the RetryWatcher that operates on ListResourceVersions and needs an initial starting point
@@ -83,6 +85,7 @@ func WithInitialListResourceVersion(resourceVersion string) ConfigOption {
 func CreateConfig(data Entries, options ...ConfigOption) Config {
 	cfg := Config{
 		entries:       data,
+		base:          maps.Clone(data),
 		changeHistory: make([]Change, 0),
 	}
 
@@ -165,6 +168,11 @@ func (c Config) GetAll() Entries {
 	return maps.Clone(c.entries)
 }
 
+// GetBase returns a map of all Key-Value-pairs the configuration was created with, i.e. without any changes.
+func (c Config) GetBase() Entries {
+	return maps.Clone(c.base)
+}
+
 // GetChangeHistory returns a slice of all changes made to the configuration.
 func (c Config) GetChangeHistory() []Change {
 	return slices.Clone(c.changeHistory)
@@ -215,6 +223,7 @@ func (c Config) DeleteAll() Config {
 
 	return Config{
 		entries:            make(Entries),
+		base:               c.base,
 		changeHistory:      slices.Clone(c.changeHistory),
 		PersistenceContext: c.PersistenceContext,
 	}
@@ -264,6 +273,7 @@ func (c Config) Diff(other Config) []DiffResult {
 func (c Config) createCopy() Config {
 	return Config{
 		entries:            maps.Clone(c.entries),
+		base:               c.base,
 		changeHistory:      slices.Clone(c.changeHistory),
 		PersistenceContext: c.PersistenceContext,
 	}
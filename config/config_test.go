@@ -504,3 +504,15 @@ func TestConfig_Diff(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_GetBase(t *testing.T) {
+	cfg := CreateConfig(Entries{"key1": "value1", "key2": "value2"})
+
+	cfg, err := cfg.Set("key1", "newValue")
+	assert.NoError(t, err)
+	cfg = cfg.Delete("key2")
+
+	assert.Equal(t, Entries{"key1": "newValue"}, cfg.GetAll())
+	assert.Equal(t, Entries{"key1": "value1", "key2": "value2"}, cfg.GetBase())
+	assert.Equal(t, Entries{"key1": "value1", "key2": "value2"}, cfg.DeleteAll().GetBase())
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gopkg.in/yaml.v3"
@@ -15,6 +16,12 @@ type Converter interface {
 	Write(writer io.Writer, cfgData Entries) error
 }
 
+// DataKeyProvider is implemented by converters that name the key under which their format is stored in a config map
+// or secret, e.g. "config.json". Converters without a data key are stored under "config.yaml".
+type DataKeyProvider interface {
+	DataKey() string
+}
+
 func mapToConfig(sourceMap map[string]any, targetMapPtr *Entries, parentPath string) error {
 	if *targetMapPtr == nil {
 		*targetMapPtr = make(map[Key]Value)
@@ -69,6 +76,11 @@ func configToMap(sourceMap Entries, prefix string) map[string]any {
 type YamlConverter struct {
 }
 
+// DataKey returns the key under which yaml configs are stored.
+func (yc *YamlConverter) DataKey() string {
+	return "config.yaml"
+}
+
 func (yc *YamlConverter) Read(reader io.Reader) (Entries, error) {
 	if reader == nil {
 		return nil, errors.New("reader is nil")
@@ -104,3 +116,42 @@ func (yc *YamlConverter) Write(writer io.Writer, cfgData Entries) error {
 
 	return nil
 }
+
+// JsonConverter reads and writes the config entries as nested json objects.
+// Every directory of the key becomes an object, every value a string.
+type JsonConverter struct {
+}
+
+// DataKey returns the key under which json configs are stored.
+func (jc *JsonConverter) DataKey() string {
+	return "config.json"
+}
+
+func (jc *JsonConverter) Read(reader io.Reader) (Entries, error) {
+	if reader == nil {
+		return nil, errors.New("reader is nil")
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	var jsonMap map[string]any
+	if err := decoder.Decode(&jsonMap); err != nil {
+		return nil, fmt.Errorf("unable to decode json from reader: %w", err)
+	}
+
+	return MapToEntries(jsonMap)
+}
+
+func (jc *JsonConverter) Write(writer io.Writer, cfgData Entries) error {
+	jsonMap := configToMap(cfgData, "")
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(jsonMap); err != nil {
+		return fmt.Errorf("unable to encode Config Entries as json to writer: %w", err)
+	}
+
+	return nil
+}
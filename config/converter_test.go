@@ -263,3 +263,144 @@ func TestYamlConverter_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonConverter_Read(t *testing.T) {
+	testCases := []struct {
+		name       string
+		jsonInput  string
+		nilReader  bool
+		expected   Entries
+		expectFail bool
+	}{
+		{
+			name:      "Simple JSON",
+			jsonInput: `{"key1": "value1", "key2": "value2"}`,
+			expected: Entries{
+				"key1": "value1",
+				"key2": "value2",
+			},
+		},
+		{
+			name:      "Nested JSON",
+			jsonInput: `{"parent": {"child1": "value1", "child2": "value2"}}`,
+			expected: Entries{
+				"parent/child1": "value1",
+				"parent/child2": "value2",
+			},
+		},
+		{
+			name:       "Empty JSON",
+			jsonInput:  ``,
+			expectFail: true,
+		},
+		{
+			name:       "Nil Reader",
+			nilReader:  true,
+			expectFail: true,
+		},
+		{
+			name:       "invalid json",
+			jsonInput:  `{"parent": `,
+			expectFail: true,
+		},
+		{
+			name:       "non string value",
+			jsonInput:  `{"parent": {"child1": 123}}`,
+			expectFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reader io.Reader
+			if !tc.nilReader {
+				reader = strings.NewReader(tc.jsonInput)
+			}
+
+			jc := &JsonConverter{}
+			result, err := jc.Read(reader)
+			if tc.expectFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestJsonConverter_Write(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     Entries
+		expected string
+	}{
+		{
+			name: "Simple Entries",
+			data: Entries{
+				"key1": "value1",
+				"key2": "<value2>",
+			},
+			expected: "{\n  \"key1\": \"value1\",\n  \"key2\": \"<value2>\"\n}\n",
+		},
+		{
+			name: "Nested Entries",
+			data: Entries{
+				"parent/child1": "value1",
+			},
+			expected: "{\n  \"parent\": {\n    \"child1\": \"value1\"\n  }\n}\n",
+		},
+		{
+			name:     "Empty Entries",
+			data:     Entries{},
+			expected: "{}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			jc := &JsonConverter{}
+			err := jc.Write(&buffer, tc.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buffer.String())
+		})
+	}
+}
+
+func TestConverter_RoundTrip(t *testing.T) {
+	entries := Entries{
+		"logging/root":                  "WARN",
+		"container_config/memory_limit": "512m",
+		"certificate/server.crt":        "-----BEGIN CERTIFICATE-----\nabc=\n-----END CERTIFICATE-----\n",
+		"_internal/Key-With.Special_":   "  'quoted' \"value\" with $dollar, \\backslash and # hash  ",
+		"empty":                         "",
+		"a//b":                          "äöü",
+	}
+
+	converters := map[string]Converter{
+		"yaml":       &YamlConverter{},
+		"json":       &JsonConverter{},
+		"properties": &PropertiesConverter{},
+		"dotenv":     &DotenvConverter{},
+	}
+
+	for name, converter := range converters {
+		t.Run(name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			err := converter.Write(&buffer, entries)
+			assert.NoError(t, err)
+
+			result, err := converter.Read(&buffer)
+			assert.NoError(t, err)
+			assert.Equal(t, entries, result)
+		})
+	}
+}
+
+func TestConverter_DataKey(t *testing.T) {
+	assert.Equal(t, "config.yaml", (&YamlConverter{}).DataKey())
+	assert.Equal(t, "config.json", (&JsonConverter{}).DataKey())
+	assert.Equal(t, "config.properties", (&PropertiesConverter{}).DataKey())
+	assert.Equal(t, "config.env", (&DotenvConverter{}).DataKey())
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const dotenvHexAlphabet = "abcdefghijklmnop"
+
+// DotenvConverter reads and writes the config entries as environment variable assignments, e.g. "LOGGING_ROOT='WARN'".
+// Values are written in single quotes, so the result can be sourced by a posix shell.
+//
+// The names of the variables are derived from the keys as follows:
+//   - lower case letters are converted to upper case
+//   - the key separator "/" becomes "_"
+//   - an underscore within a key becomes "__"
+//   - any other character is encoded as two lower case letters ("a" to "p") representing its hex value
+//
+// For example the key "container_config/memory_limit" becomes "CONTAINER__CONFIG_MEMORY__LIMIT".
+// This way every key can be written and read losslessly.
+type DotenvConverter struct {
+}
+
+// DataKey returns the key under which dotenv configs are stored.
+func (dc *DotenvConverter) DataKey() string {
+	return "config.env"
+}
+
+func (dc *DotenvConverter) Read(reader io.Reader) (Entries, error) {
+	if reader == nil {
+		return nil, errors.New("reader is nil")
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read dotenv from reader: %w", err)
+	}
+
+	entries, err := (&dotenvParser{input: []rune(string(content))}).parse()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse dotenv: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (dc *DotenvConverter) Write(writer io.Writer, cfgData Entries) error {
+	keys := make([]Key, 0, len(cfgData))
+	for key := range cfgData {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, key := range keys {
+		quotedValue := strings.ReplaceAll(cfgData[key].String(), `'`, `'\''`)
+		line := fmt.Sprintf("%s='%s'\n", encodeDotenvName(key), quotedValue)
+		if _, err := bufferedWriter.WriteString(line); err != nil {
+			return fmt.Errorf("unable to write dotenv to writer: %w", err)
+		}
+	}
+
+	if err := bufferedWriter.Flush(); err != nil {
+		return fmt.Errorf("unable to write dotenv to writer: %w", err)
+	}
+
+	return nil
+}
+
+func encodeDotenvName(key Key) string {
+	var sb strings.Builder
+	k := key.String()
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			sb.WriteByte(c - 'a' + 'A')
+		case c >= '0' && c <= '9' && i > 0:
+			sb.WriteByte(c)
+		// separators directly after a separator would be ambiguous, so they are encoded.
+		case c == '/' && (i == 0 || k[i-1] != '/'):
+			sb.WriteByte('_')
+		case c == '_' && (i == 0 || k[i-1] != '/'):
+			sb.WriteString("__")
+		default:
+			sb.WriteByte(dotenvHexAlphabet[c>>4])
+			sb.WriteByte(dotenvHexAlphabet[c&0x0f])
+		}
+	}
+
+	return sb.String()
+}
+
+func decodeDotenvName(name string) (Key, error) {
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			sb.WriteByte(c - 'A' + 'a')
+		case c >= '0' && c <= '9':
+			sb.WriteByte(c)
+		case c == '_' && i+1 < len(name) && name[i+1] == '_':
+			sb.WriteByte('_')
+			i++
+		case c == '_':
+			sb.WriteString(keySeparator)
+		case strings.IndexByte(dotenvHexAlphabet, c) >= 0:
+			if i+1 >= len(name) || strings.IndexByte(dotenvHexAlphabet, name[i+1]) < 0 {
+				return "", fmt.Errorf("invalid encoded character in variable name %q", name)
+			}
+
+			high := strings.IndexByte(dotenvHexAlphabet, c)
+			low := strings.IndexByte(dotenvHexAlphabet, name[i+1])
+			sb.WriteByte(byte(high<<4 | low))
+			i++
+		default:
+			return "", fmt.Errorf("invalid character %q in variable name %q", c, name)
+		}
+	}
+
+	return Key(sb.String()), nil
+}
+
+// dotenvParser parses variable assignments with a subset of the posix shell syntax:
+// single quotes, double quotes, backslash escapes, comments and an optional "export" prefix.
+type dotenvParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *dotenvParser) parse() (Entries, error) {
+	entries := make(Entries)
+	for {
+		p.skipBlankLinesAndComments()
+		if p.eof() {
+			return entries, nil
+		}
+
+		name := p.readName()
+		if name == "export" && p.skipBlanks() > 0 {
+			name = p.readName()
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("missing variable name at position %d", p.pos)
+		}
+
+		p.skipBlanks()
+		if p.eof() || p.input[p.pos] != '=' {
+			return nil, fmt.Errorf("missing '=' after variable name %q", name)
+		}
+		p.pos++
+		hasLeadingBlanks := p.skipBlanks() > 0
+
+		value, err := p.readValue(hasLeadingBlanks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of variable %q: %w", name, err)
+		}
+
+		key, err := decodeDotenvName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[key] = Value(value)
+	}
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *dotenvParser) skipBlanks() int {
+	start := p.pos
+	for !p.eof() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+
+	return p.pos - start
+}
+
+func (p *dotenvParser) skipBlankLinesAndComments() {
+	for !p.eof() {
+		switch p.input[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.input[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) readName() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.input[p.pos]
+		if !(c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			break
+		}
+		p.pos++
+	}
+
+	return string(p.input[start:p.pos])
+}
+
+func (p *dotenvParser) readValue(hasLeadingBlanks bool) (string, error) {
+	var value, pendingBlanks strings.Builder
+	// a comment may start after the blanks between '=' and the value
+	commentAllowed := hasLeadingBlanks
+	for !p.eof() {
+		c := p.input[p.pos]
+		switch c {
+		case '\r', '\n':
+			p.pos++
+			return value.String(), nil
+		case ' ', '\t':
+			pendingBlanks.WriteRune(c)
+			commentAllowed = true
+			p.pos++
+			continue
+		case '#':
+			if commentAllowed {
+				p.skipLine()
+				return value.String(), nil
+			}
+		}
+
+		value.WriteString(pendingBlanks.String())
+		pendingBlanks.Reset()
+		commentAllowed = false
+
+		switch c {
+		case '\'':
+			quoted, err := p.readSingleQuoted()
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(quoted)
+		case '"':
+			quoted, err := p.readDoubleQuoted()
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(quoted)
+		case '\\':
+			p.pos++
+			if !p.eof() && p.input[p.pos] != '\n' {
+				value.WriteRune(p.input[p.pos])
+			}
+			p.pos++
+		default:
+			value.WriteRune(c)
+			p.pos++
+		}
+	}
+
+	return value.String(), nil
+}
+
+func (p *dotenvParser) readSingleQuoted() (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() {
+		if p.input[p.pos] == '\'' {
+			quoted := string(p.input[start:p.pos])
+			p.pos++
+			return quoted, nil
+		}
+		p.pos++
+	}
+
+	return "", errors.New("missing closing single quote")
+}
+
+func (p *dotenvParser) readDoubleQuoted() (string, error) {
+	var sb strings.Builder
+	p.pos++
+	for !p.eof() {
+		c := p.input[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			p.pos++
+			switch escaped := p.input[p.pos]; escaped {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case '\\', '"', '$', '`':
+				sb.WriteRune(escaped)
+			case '\n':
+				// line continuation
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(escaped)
+			}
+		default:
+			sb.WriteRune(c)
+		}
+		p.pos++
+	}
+
+	return "", errors.New("missing closing double quote")
+}
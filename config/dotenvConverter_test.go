@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDotenvConverter_Read(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		nilReader  bool
+		expected   Entries
+		expectFail bool
+	}{
+		{
+			name:     "Simple dotenv",
+			input:    "KEY1=value1\nKEY2='value2'\nKEY3=\"value3\"\n",
+			expected: Entries{"key1": "value1", "key2": "value2", "key3": "value3"},
+		},
+		{
+			name:     "Nested keys",
+			input:    "LOGGING_ROOT=WARN\nCONTAINER__CONFIG_MEMORY__LIMIT=512m\n",
+			expected: Entries{"logging/root": "WARN", "container_config/memory_limit": "512m"},
+		},
+		{
+			name:     "Encoded characters in names",
+			input:    "CERTIFICATE_SERVERcoCRT=cert\nfpINTERNAL_ebBC=value\n",
+			expected: Entries{"certificate/server.crt": "cert", "_internal/Abc": "value"},
+		},
+		{
+			name:     "Comments, blank lines and export",
+			input:    "# comment\n\n  export KEY1=value1 # comment\nKEY2= # comment\nKEY3=a#b\r\n",
+			expected: Entries{"key1": "value1", "key2": "", "key3": "a#b"},
+		},
+		{
+			name:     "Quoting and escaping",
+			input:    "KEY1='it'\\''s'\nKEY2=\"line1\\nline2 \\\"quoted\\\" \\$HOME\"\nKEY3=unquoted\\ value  \nKEY4='multi\nline'\n",
+			expected: Entries{"key1": "it's", "key2": "line1\nline2 \"quoted\" $HOME", "key3": "unquoted value", "key4": "multi\nline"},
+		},
+		{
+			name:     "Empty input",
+			input:    "",
+			expected: Entries{},
+		},
+		{
+			name:       "Nil Reader",
+			nilReader:  true,
+			expectFail: true,
+		},
+		{
+			name:       "Missing equal sign",
+			input:      "KEY value",
+			expectFail: true,
+		},
+		{
+			name:       "Missing name",
+			input:      "=value",
+			expectFail: true,
+		},
+		{
+			name:       "Missing closing quote",
+			input:      "KEY='value",
+			expectFail: true,
+		},
+		{
+			name:       "Missing closing double quote",
+			input:      "KEY=\"value",
+			expectFail: true,
+		},
+		{
+			name:       "Invalid encoded name",
+			input:      "KEYa=value",
+			expectFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reader io.Reader
+			if !tc.nilReader {
+				reader = strings.NewReader(tc.input)
+			}
+
+			dc := &DotenvConverter{}
+			result, err := dc.Read(reader)
+			if tc.expectFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDotenvConverter_Write(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     Entries
+		expected string
+	}{
+		{
+			name:     "Simple Entries",
+			data:     Entries{"key2": "value2", "key1": "value1"},
+			expected: "KEY1='value1'\nKEY2='value2'\n",
+		},
+		{
+			name:     "Nested Entries",
+			data:     Entries{"logging/root": "WARN", "container_config/memory_limit": "512m"},
+			expected: "CONTAINER__CONFIG_MEMORY__LIMIT='512m'\nLOGGING_ROOT='WARN'\n",
+		},
+		{
+			name:     "Encoded Entries",
+			data:     Entries{"certificate/server.crt": "it's", "1st//_x": "value"},
+			expected: "dbST_cpfpX='value'\nCERTIFICATE_SERVERcoCRT='it'\\''s'\n",
+		},
+		{
+			name:     "Empty Entries",
+			data:     Entries{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			dc := &DotenvConverter{}
+			err := dc.Write(&buffer, tc.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buffer.String())
+		})
+	}
+}
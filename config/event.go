@@ -0,0 +1,20 @@
+package config
+
+// EventKind describes what happened to a watched config.
+type EventKind string
+
+const (
+	// EventModified is the kind of events for configs that have been changed.
+	EventModified EventKind = "Modified"
+	// EventCreated is the kind of events for configs that have been created, e.g. after they have been deleted.
+	// The previous state of such events is always empty.
+	EventCreated EventKind = "Created"
+	// EventDeleted is the kind of events for configs that have been deleted. The new state of such events is always
+	// empty, so that filters match on every key of the deleted config.
+	EventDeleted EventKind = "Deleted"
+)
+
+// String returns the string representation of the EventKind.
+func (ek EventKind) String() string {
+	return string(ek)
+}
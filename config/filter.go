@@ -4,6 +4,7 @@ import "strings"
 
 // WatchFilter can be applied to the result of a comparison of two configurations.
 // It is a predicate function that should to return true in case the filter matches.
+// Deleted configs are compared with an empty configuration, so a filter matches the deletion of every key it matches.
 type WatchFilter func([]DiffResult) bool
 
 // KeyFilter is a WatchFilter to watch for changes for a single Key.
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	propertiesKeySeparator = "."
+	// maxPropertiesLineSize is the size limit of a single line. It matches the size limit of kubernetes objects.
+	maxPropertiesLineSize = 1024 * 1024
+)
+
+// PropertiesConverter reads and writes the config entries in the format of java properties files.
+// The directories of a key are joined by dots, e.g. the key "logging/root" becomes "logging.root".
+// Dots within a directory or key name are escaped with a backslash, so every key can be written and read losslessly.
+type PropertiesConverter struct {
+}
+
+// DataKey returns the key under which properties configs are stored.
+func (pc *PropertiesConverter) DataKey() string {
+	return "config.properties"
+}
+
+func (pc *PropertiesConverter) Read(reader io.Reader) (Entries, error) {
+	if reader == nil {
+		return nil, errors.New("reader is nil")
+	}
+
+	lines, err := readLogicalPropertiesLines(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read properties from reader: %w", err)
+	}
+
+	entries := make(Entries)
+	for _, line := range lines {
+		key, value, parseErr := parsePropertiesLine(line)
+		if parseErr != nil {
+			return nil, fmt.Errorf("unable to parse properties line %q: %w", line, parseErr)
+		}
+
+		entries[key] = value
+	}
+
+	return entries, nil
+}
+
+// readLogicalPropertiesLines joins continued lines and removes blank lines and comments.
+func readLogicalPropertiesLines(reader io.Reader) ([]string, error) {
+	var lines []string
+	var current strings.Builder
+	continued := false
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxPropertiesLineSize)
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if !continued && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		continued = hasOddTrailingBackslashes(line)
+		if continued {
+			line = line[:len(line)-1]
+		}
+
+		current.WriteString(line)
+		if !continued {
+			lines = append(lines, current.String())
+			current.Reset()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if current.Len() > 0 {
+		lines = append(lines, current.String())
+	}
+
+	return lines, nil
+}
+
+func hasOddTrailingBackslashes(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+
+	return count%2 == 1
+}
+
+func parsePropertiesLine(line string) (Key, Value, error) {
+	runes := []rune(line)
+
+	var segments []string
+	var segment strings.Builder
+	i := 0
+keyLoop:
+	for i < len(runes) {
+		switch r := runes[i]; r {
+		case '=', ':', ' ', '\t', '\f':
+			break keyLoop
+		case '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+			i++
+		case '\\':
+			unescaped, consumed, err := unescapePropertiesRune(runes[i+1:])
+			if err != nil {
+				return "", "", err
+			}
+
+			segment.WriteRune(unescaped)
+			i += consumed + 1
+		default:
+			segment.WriteRune(r)
+			i++
+		}
+	}
+	segments = append(segments, segment.String())
+
+	// skip whitespace and at most one separator between key and value
+	for i < len(runes) && isPropertiesWhitespace(runes[i]) {
+		i++
+	}
+	if i < len(runes) && (runes[i] == '=' || runes[i] == ':') {
+		i++
+	}
+	for i < len(runes) && isPropertiesWhitespace(runes[i]) {
+		i++
+	}
+
+	var value strings.Builder
+	for i < len(runes) {
+		if runes[i] != '\\' {
+			value.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		unescaped, consumed, err := unescapePropertiesRune(runes[i+1:])
+		if err != nil {
+			return "", "", err
+		}
+
+		value.WriteRune(unescaped)
+		i += consumed + 1
+	}
+
+	return Key(strings.Join(segments, keySeparator)), Value(value.String()), nil
+}
+
+func isPropertiesWhitespace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\f'
+}
+
+// unescapePropertiesRune returns the rune for the escape sequence at the beginning of the given runes
+// (without the leading backslash) and the number of consumed runes.
+func unescapePropertiesRune(runes []rune) (rune, int, error) {
+	if len(runes) == 0 {
+		return 0, 0, errors.New("incomplete escape sequence")
+	}
+
+	switch runes[0] {
+	case 't':
+		return '\t', 1, nil
+	case 'n':
+		return '\n', 1, nil
+	case 'r':
+		return '\r', 1, nil
+	case 'f':
+		return '\f', 1, nil
+	case 'u':
+		if len(runes) < 5 {
+			return 0, 0, errors.New("incomplete unicode escape sequence")
+		}
+
+		code, err := strconv.ParseUint(string(runes[1:5]), 16, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid unicode escape sequence: %w", err)
+		}
+
+		return rune(code), 5, nil
+	default:
+		return runes[0], 1, nil
+	}
+}
+
+func (pc *PropertiesConverter) Write(writer io.Writer, cfgData Entries) error {
+	keys := make([]Key, 0, len(cfgData))
+	for key := range cfgData {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, key := range keys {
+		line := fmt.Sprintf("%s=%s\n", escapePropertiesKey(key), escapePropertiesValue(cfgData[key]))
+		if _, err := bufferedWriter.WriteString(line); err != nil {
+			return fmt.Errorf("unable to write properties to writer: %w", err)
+		}
+	}
+
+	if err := bufferedWriter.Flush(); err != nil {
+		return fmt.Errorf("unable to write properties to writer: %w", err)
+	}
+
+	return nil
+}
+
+func escapePropertiesKey(key Key) string {
+	segments := strings.Split(key.String(), keySeparator)
+	for i, segment := range segments {
+		segments[i] = escapeProperties(segment, true)
+	}
+
+	return strings.Join(segments, propertiesKeySeparator)
+}
+
+func escapePropertiesValue(value Value) string {
+	return escapeProperties(value.String(), false)
+}
+
+func escapeProperties(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case ' ':
+			if isKey || i == 0 {
+				sb.WriteString(`\ `)
+			} else {
+				sb.WriteRune(r)
+			}
+		case '.', '=', ':', '#', '!':
+			if isKey {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertiesConverter_Read(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		nilReader  bool
+		expected   Entries
+		expectFail bool
+	}{
+		{
+			name:     "Simple properties",
+			input:    "key1=value1\nkey2 = value2\nkey3: value3\nkey4 value4\n",
+			expected: Entries{"key1": "value1", "key2": "value2", "key3": "value3", "key4": "value4"},
+		},
+		{
+			name:     "Nested properties",
+			input:    "parent.child1=value1\nparent.child2=value2",
+			expected: Entries{"parent/child1": "value1", "parent/child2": "value2"},
+		},
+		{
+			name:     "Comments and blank lines",
+			input:    "# comment\n! other comment\n\n   \nkey=value\n",
+			expected: Entries{"key": "value"},
+		},
+		{
+			name:     "Escaped characters",
+			input:    "my\\.file\\ name.key\\=1=line1\\nline2\\t\\u00e4 = \\\\",
+			expected: Entries{"my.file name/key=1": "line1\nline2\tä = \\"},
+		},
+		{
+			name:     "Continued lines",
+			input:    "key=first, \\\n    second, \\\n    third\nother=value\\\\\n",
+			expected: Entries{"key": "first, second, third", "other": "value\\"},
+		},
+		{
+			name:     "Empty input",
+			input:    "",
+			expected: Entries{},
+		},
+		{
+			name:       "Nil Reader",
+			nilReader:  true,
+			expectFail: true,
+		},
+		{
+			name:       "Invalid unicode escape",
+			input:      "key=\\uzzzz",
+			expectFail: true,
+		},
+		{
+			name:       "Incomplete unicode escape",
+			input:      "key=\\u00",
+			expectFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reader io.Reader
+			if !tc.nilReader {
+				reader = strings.NewReader(tc.input)
+			}
+
+			pc := &PropertiesConverter{}
+			result, err := pc.Read(reader)
+			if tc.expectFail {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestPropertiesConverter_Write(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     Entries
+		expected string
+	}{
+		{
+			name:     "Simple Entries",
+			data:     Entries{"key2": "value2", "key1": "value1"},
+			expected: "key1=value1\nkey2=value2\n",
+		},
+		{
+			name:     "Nested Entries",
+			data:     Entries{"logging/root": "WARN", "container_config/memory_limit": "512m"},
+			expected: "container_config.memory_limit=512m\nlogging.root=WARN\n",
+		},
+		{
+			name:     "Escaped Entries",
+			data:     Entries{"certificate/server.crt": " line1\nline2 = \\", "a key:#!": "a=b:c"},
+			expected: "a\\ key\\:\\#\\!=a=b:c\ncertificate.server\\.crt=\\ line1\\nline2 = \\\\\n",
+		},
+		{
+			name:     "Empty Entries",
+			data:     Entries{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			pc := &PropertiesConverter{}
+			err := pc.Write(&buffer, tc.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buffer.String())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrKeyNotFound is returned by the typed getters of Config if the requested key does not exist.
+var ErrKeyNotFound = errors.New("key not found")
+
+const stringSliceSeparator = ","
+
+var binarySizeRegex = regexp.MustCompile(`^(\d{1,19})([bkmgBKMG]?)$`)
+
+var binarySizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"g", 1024 * 1024 * 1024},
+	{"m", 1024 * 1024},
+	{"k", 1024},
+	{"b", 1},
+}
+
+// ParseError is returned if a configuration value cannot be converted to the requested type.
+type ParseError struct {
+	Key   Key
+	Value Value
+	Type  string
+	Err   error
+}
+
+// Error returns the key, the value and the requested type of the failed conversion.
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("value %q of key %q is not a valid %s: %v", pe.Value, pe.Key, pe.Type, pe.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// GetString returns the value for the given key as string.
+// Returns an error wrapping ErrKeyNotFound if the key does not exist.
+func (c Config) GetString(k Key) (string, error) {
+	v, err := c.getExisting(k)
+	if err != nil {
+		return "", err
+	}
+
+	return v.String(), nil
+}
+
+// GetInt returns the value for the given key as int.
+// Returns an error wrapping ErrKeyNotFound if the key does not exist and a *ParseError if the value is not an integer.
+func (c Config) GetInt(k Key) (int, error) {
+	return getTyped(c, k, "int", func(v Value) (int, error) {
+		return strconv.Atoi(v.String())
+	})
+}
+
+// GetBool returns the value for the given key as bool. All values accepted by strconv.ParseBool are supported.
+// Returns an error wrapping ErrKeyNotFound if the key does not exist and a *ParseError if the value is not a boolean.
+func (c Config) GetBool(k Key) (bool, error) {
+	return getTyped(c, k, "bool", func(v Value) (bool, error) {
+		return strconv.ParseBool(v.String())
+	})
+}
+
+// GetDuration returns the value for the given key as time.Duration, e.g. "1h30m".
+// Returns an error wrapping ErrKeyNotFound if the key does not exist and a *ParseError if the value is not a duration.
+func (c Config) GetDuration(k Key) (time.Duration, error) {
+	return getTyped(c, k, "duration", func(v Value) (time.Duration, error) {
+		return time.ParseDuration(v.String())
+	})
+}
+
+// GetStringSlice returns the comma separated value for the given key as slice. Surrounding whitespace of the elements
+// is removed and empty elements are skipped.
+// Returns an error wrapping ErrKeyNotFound if the key does not exist.
+func (c Config) GetStringSlice(k Key) ([]string, error) {
+	v, err := c.getExisting(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStringSlice(v), nil
+}
+
+// GetBinarySize returns the value for the given key as number of bytes. The value has to be an integer with an optional
+// binary measurement unit (b, k, m, g), e.g. "512m".
+// Returns an error wrapping ErrKeyNotFound if the key does not exist and a *ParseError if the value is not a binary size.
+func (c Config) GetBinarySize(k Key) (int64, error) {
+	return getTyped(c, k, "binary size", parseBinarySize)
+}
+
+func (c Config) getExisting(k Key) (Value, error) {
+	v, ok := c.Get(k)
+	if !ok {
+		return "", fmt.Errorf("could not get value of key %q: %w", sanitizeKey(k), ErrKeyNotFound)
+	}
+
+	return v, nil
+}
+
+func getTyped[T any](c Config, k Key, typeName string, parse func(Value) (T, error)) (T, error) {
+	var zero T
+
+	v, err := c.getExisting(k)
+	if err != nil {
+		return zero, err
+	}
+
+	result, err := parse(v)
+	if err != nil {
+		return zero, &ParseError{Key: sanitizeKey(k), Value: v, Type: typeName, Err: err}
+	}
+
+	return result, nil
+}
+
+func parseStringSlice(v Value) []string {
+	result := make([]string, 0)
+	for _, element := range strings.Split(v.String(), stringSliceSeparator) {
+		if trimmed := strings.TrimSpace(element); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+
+	return result
+}
+
+func parseBinarySize(v Value) (int64, error) {
+	matches := binarySizeRegex.FindStringSubmatch(strings.TrimSpace(v.String()))
+	if matches == nil {
+		return 0, errors.New("expected an integer with an optional binary measurement unit (b, k, m, g)")
+	}
+
+	size, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	multiplier := int64(1)
+	for _, unit := range binarySizeUnits {
+		if strings.EqualFold(matches[2], unit.suffix) {
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	if size > (1<<63-1)/multiplier {
+		return 0, errors.New("value out of range")
+	}
+
+	return size * multiplier, nil
+}
+
+// formatBinarySize returns the size with the largest binary measurement unit that represents it exactly.
+func formatBinarySize(size int64) string {
+	for _, unit := range binarySizeUnits {
+		if size != 0 && size%unit.multiplier == 0 {
+			return fmt.Sprintf("%d%s", size/unit.multiplier, unit.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%db", size)
+}
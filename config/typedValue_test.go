@@ -0,0 +1,130 @@
+package config
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var typedTestConfig = CreateConfig(Entries{
+	"string":         "value",
+	"int":            "42",
+	"bool":           "true",
+	"duration":       "1h30m",
+	"slice":          " a, b ,,c ",
+	"size/bytes":     "100",
+	"size/mega":      "512m",
+	"size/giga":      "2G",
+	"size/overflow":  "9999999999999999999g",
+	"invalid":        "not-a-number",
+	"invalid/nested": "x",
+})
+
+func TestConfig_GetString(t *testing.T) {
+	value, err := typedTestConfig.GetString("/string")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	_, err = typedTestConfig.GetString("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.ErrorContains(t, err, `could not get value of key "missing"`)
+}
+
+func TestConfig_GetInt(t *testing.T) {
+	value, err := typedTestConfig.GetInt("int")
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	_, err = typedTestConfig.GetInt("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	_, err = typedTestConfig.GetInt("invalid")
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, Key("invalid"), parseErr.Key)
+	assert.Equal(t, Value("not-a-number"), parseErr.Value)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.ErrorContains(t, err, `value "not-a-number" of key "invalid" is not a valid int`)
+}
+
+func TestConfig_GetBool(t *testing.T) {
+	value, err := typedTestConfig.GetBool("bool")
+	require.NoError(t, err)
+	assert.True(t, value)
+
+	_, err = typedTestConfig.GetBool("invalid")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+}
+
+func TestConfig_GetDuration(t *testing.T) {
+	value, err := typedTestConfig.GetDuration("duration")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, value)
+
+	_, err = typedTestConfig.GetDuration("invalid")
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+}
+
+func TestConfig_GetStringSlice(t *testing.T) {
+	value, err := typedTestConfig.GetStringSlice("slice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, value)
+
+	value, err = CreateConfig(Entries{"empty": ""}).GetStringSlice("empty")
+	require.NoError(t, err)
+	assert.Empty(t, value)
+
+	_, err = typedTestConfig.GetStringSlice("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestConfig_GetBinarySize(t *testing.T) {
+	tests := []struct {
+		key     Key
+		xResult int64
+		xErr    bool
+	}{
+		{"size/bytes", 100, false},
+		{"size/mega", 512 * 1024 * 1024, false},
+		{"size/giga", 2 * 1024 * 1024 * 1024, false},
+		{"size/overflow", 0, true},
+		{"invalid", 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.key.String(), func(t *testing.T) {
+			value, err := typedTestConfig.GetBinarySize(tc.key)
+			assert.Equal(t, tc.xErr, err != nil)
+			assert.Equal(t, tc.xResult, value)
+		})
+	}
+}
+
+func Test_formatBinarySize(t *testing.T) {
+	tests := []struct {
+		input     int64
+		expOutput string
+	}{
+		{0, "0b"},
+		{100, "100b"},
+		{2048, "2k"},
+		{1536 * 1024, "1536k"},
+		{512 * 1024 * 1024, "512m"},
+		{3 * 1024 * 1024 * 1024, "3g"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expOutput, func(t *testing.T) {
+			assert.Equal(t, tc.expOutput, formatBinarySize(tc.input))
+
+			parsed, err := parseBinarySize(Value(tc.expOutput))
+			require.NoError(t, err)
+			assert.Equal(t, tc.input, parsed)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/cloudogu/cesapp-lib/doguConf"
+)
+
+const (
+	// ValidationTypeOneOf only allows values that are contained in the validation values.
+	ValidationTypeOneOf = doguConf.OneOfKey
+	// ValidationTypeBinaryMeasurement only allows integers with a binary unit (b, k, m, g), e.g. "512m".
+	ValidationTypeBinaryMeasurement = doguConf.BinaryMeasurementKey
+	// ValidationTypeFloatPercentageHundred only allows float values between 0.0 and 100.0, e.g. "55.5".
+	ValidationTypeFloatPercentageHundred = doguConf.FloatPercentageHundredKey
+)
+
+// floatPercentageSeparatorRegex requires a dot as decimal separator, which the validator of the cesapp-lib does not
+// check, so that values like "100" or "5e1" are rejected.
+var floatPercentageSeparatorRegex = regexp.MustCompile(`^\d{1,3}\.\d{1,2}$`)
+
+// ViolationType describes why a configuration key violates the configuration fields of a dogu descriptor.
+type ViolationType int
+
+const (
+	// UnknownKeyViolation marks a key that is not declared as configuration field.
+	UnknownKeyViolation ViolationType = iota + 1
+	// MissingRequiredKeyViolation marks a non-optional configuration field without value and default.
+	MissingRequiredKeyViolation
+	// InvalidValueViolation marks a value that does not pass the validation of its configuration field.
+	InvalidValueViolation
+)
+
+// String returns the string representation of the ViolationType.
+func (vt ViolationType) String() string {
+	switch vt {
+	case UnknownKeyViolation:
+		return "unknown key"
+	case MissingRequiredKeyViolation:
+		return "missing required key"
+	case InvalidValueViolation:
+		return "invalid value"
+	default:
+		return "unknown violation"
+	}
+}
+
+// Violation describes a single key of a configuration that does not match the dogu descriptor.
+type Violation struct {
+	Key     Key
+	Type    ViolationType
+	Message string
+}
+
+// String returns a human-readable representation of the Violation.
+func (v Violation) String() string {
+	if v.Message == "" {
+		return fmt.Sprintf("%s %q", v.Type, v.Key)
+	}
+
+	return fmt.Sprintf("%s %q: %s", v.Type, v.Key, v.Message)
+}
+
+// ValidationError is returned if a DoguConfig violates the configuration fields of its dogu descriptor.
+type ValidationError struct {
+	DoguName   SimpleDoguName
+	Violations []Violation
+}
+
+// Error returns all violations as a single string.
+func (ve *ValidationError) Error() string {
+	messages := make([]string, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		messages = append(messages, v.String())
+	}
+
+	return fmt.Sprintf("config of dogu %q is invalid: %s", ve.DoguName, strings.Join(messages, "; "))
+}
+
+// DoguConfigValidator validates a DoguConfig against the configuration fields of a dogu descriptor.
+// Global configuration fields are never part of a dogu config. Encrypted configuration fields are only part of the
+// sensitive dogu config.
+type DoguConfigValidator struct {
+	sensitive bool
+}
+
+// NewDoguConfigValidator creates a validator for the non-sensitive dogu config.
+func NewDoguConfigValidator() DoguConfigValidator {
+	return DoguConfigValidator{sensitive: false}
+}
+
+// NewSensitiveDoguConfigValidator creates a validator for the sensitive dogu config.
+func NewSensitiveDoguConfigValidator() DoguConfigValidator {
+	return DoguConfigValidator{sensitive: true}
+}
+
+// Validate returns all violations of the given config against the configuration fields of the descriptor.
+// The violations are sorted by key. An empty result means the config is valid.
+func (dcv DoguConfigValidator) Validate(doguConfig DoguConfig, descriptor *core.Dogu) []Violation {
+	fields := make(map[Key]core.ConfigurationField)
+	for _, field := range descriptor.Configuration {
+		if field.Global || field.Encrypted != dcv.sensitive {
+			continue
+		}
+
+		fields[sanitizeKey(Key(field.Name))] = field
+	}
+
+	var violations []Violation
+	entries := doguConfig.GetAll()
+	for key, value := range entries {
+		field, ok := fields[key]
+		if !ok {
+			violations = append(violations, Violation{Key: key, Type: UnknownKeyViolation})
+			continue
+		}
+
+		if err := validateValue(field.Validation, value.String()); err != nil {
+			violations = append(violations, Violation{Key: key, Type: InvalidValueViolation, Message: err.Error()})
+		}
+	}
+
+	for key, field := range fields {
+		if _, ok := entries[key]; ok || field.Optional || field.Default != "" {
+			continue
+		}
+
+		violations = append(violations, Violation{Key: key, Type: MissingRequiredKeyViolation})
+	}
+
+	slices.SortFunc(violations, func(a, b Violation) int {
+		return strings.Compare(a.Key.String(), b.Key.String())
+	})
+
+	return violations
+}
+
+// ValidateOrError validates the given config and returns a *ValidationError containing all violations if it is invalid.
+func (dcv DoguConfigValidator) ValidateOrError(doguConfig DoguConfig, descriptor *core.Dogu) error {
+	violations := dcv.Validate(doguConfig, descriptor)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{DoguName: doguConfig.DoguName, Violations: violations}
+}
+
+// validateValue checks the value with the entry validator of the cesapp-lib for the validation type, so that values
+// are validated the same way as by the dogus.
+func validateValue(validation core.ValidationDescriptor, value string) error {
+	if validation.Type == "" {
+		return nil
+	}
+
+	if validation.Type == ValidationTypeFloatPercentageHundred && !floatPercentageSeparatorRegex.MatchString(value) {
+		return fmt.Errorf("value %q should be a float number with a dot and at least one decimal place", value)
+	}
+
+	validator, err := doguConf.CreateEntryValidator(validation)
+	if err != nil {
+		return fmt.Errorf("unsupported validation type %q: %w", validation.Type, err)
+	}
+
+	return validator.Check(value)
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDescriptor = &core.Dogu{
+	Name: "official/test",
+	Configuration: []core.ConfigurationField{
+		{Name: "logging/root", Validation: core.ValidationDescriptor{Type: ValidationTypeOneOf, Values: []string{"ERROR", "WARN", "INFO"}}, Default: "WARN"},
+		{Name: "container_config/memory_limit", Optional: true, Validation: core.ValidationDescriptor{Type: ValidationTypeBinaryMeasurement}},
+		{Name: "container_config/java_max_ram_percentage", Optional: true, Validation: core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}},
+		{Name: "url"},
+		{Name: "password", Encrypted: true},
+		{Name: "fqdn", Global: true},
+	},
+}
+
+func TestViolationType_String(t *testing.T) {
+	tests := []struct {
+		input     ViolationType
+		expOutput string
+	}{
+		{UnknownKeyViolation, "unknown key"},
+		{MissingRequiredKeyViolation, "missing required key"},
+		{InvalidValueViolation, "invalid value"},
+		{0, "unknown violation"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expOutput, func(t *testing.T) {
+			assert.Equal(t, tc.expOutput, tc.input.String())
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{
+		DoguName: "test",
+		Violations: []Violation{
+			{Key: "foo", Type: UnknownKeyViolation},
+			{Key: "url", Type: InvalidValueViolation, Message: "not valid"},
+		},
+	}
+
+	assert.Equal(t, `config of dogu "test" is invalid: unknown key "foo"; invalid value "url": not valid`, err.Error())
+}
+
+func TestDoguConfigValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator DoguConfigValidator
+		entries   Entries
+		xResult   []Violation
+	}{
+		{
+			name:      "valid config",
+			validator: NewDoguConfigValidator(),
+			entries: Entries{
+				"logging/root":                             "INFO",
+				"container_config/memory_limit":            "512m",
+				"container_config/java_max_ram_percentage": "25.0",
+				"url": "https://example.com",
+			},
+			xResult: nil,
+		},
+		{
+			name:      "valid config with defaults and optional values",
+			validator: NewDoguConfigValidator(),
+			entries:   Entries{"url": "https://example.com"},
+			xResult:   nil,
+		},
+		{
+			name:      "unknown, global and encrypted keys",
+			validator: NewDoguConfigValidator(),
+			entries:   Entries{"url": "https://example.com", "foo": "bar", "fqdn": "example.com", "password": "secret"},
+			xResult: []Violation{
+				{Key: "foo", Type: UnknownKeyViolation},
+				{Key: "fqdn", Type: UnknownKeyViolation},
+				{Key: "password", Type: UnknownKeyViolation},
+			},
+		},
+		{
+			name:      "missing required key",
+			validator: NewDoguConfigValidator(),
+			entries:   Entries{},
+			xResult:   []Violation{{Key: "url", Type: MissingRequiredKeyViolation}},
+		},
+		{
+			name:      "invalid values",
+			validator: NewDoguConfigValidator(),
+			entries: Entries{
+				"logging/root":                             "TRACE",
+				"container_config/memory_limit":            "512mb",
+				"container_config/java_max_ram_percentage": "100.5",
+				"url": "https://example.com",
+			},
+			xResult: []Violation{
+				{Key: "container_config/java_max_ram_percentage", Type: InvalidValueViolation, Message: "input '100.5' should be between 0 and 100; invalid percentage"},
+				{Key: "container_config/memory_limit", Type: InvalidValueViolation, Message: "input '512mb' should be an integer with a binary measurement (f. ex. 2k for 2048 bytes); valid units are: b,k,m,g"},
+				{Key: "logging/root", Type: InvalidValueViolation, Message: `input should be one of ["ERROR" "WARN" "INFO"]`},
+			},
+		},
+		{
+			name:      "sensitive config",
+			validator: NewSensitiveDoguConfigValidator(),
+			entries:   Entries{"url": "https://example.com"},
+			xResult: []Violation{
+				{Key: "password", Type: MissingRequiredKeyViolation},
+				{Key: "url", Type: UnknownKeyViolation},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := tc.validator.Validate(CreateDoguConfig("test", tc.entries), testDescriptor)
+			assert.Equal(t, tc.xResult, violations)
+		})
+	}
+}
+
+func TestDoguConfigValidator_ValidateOrError(t *testing.T) {
+	t.Run("should return nil for valid config", func(t *testing.T) {
+		err := NewDoguConfigValidator().ValidateOrError(CreateDoguConfig("test", Entries{"url": "https://example.com"}), testDescriptor)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return validation error for invalid config", func(t *testing.T) {
+		err := NewDoguConfigValidator().ValidateOrError(CreateDoguConfig("test", Entries{}), testDescriptor)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, SimpleDoguName("test"), validationErr.DoguName)
+		assert.Equal(t, []Violation{{Key: "url", Type: MissingRequiredKeyViolation}}, validationErr.Violations)
+	})
+}
+
+func Test_validateValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation core.ValidationDescriptor
+		value      string
+		xErr       bool
+	}{
+		{"no validation", core.ValidationDescriptor{}, "anything", false},
+		{"one of", core.ValidationDescriptor{Type: ValidationTypeOneOf, Values: []string{"a", "b"}}, "b", false},
+		{"not one of", core.ValidationDescriptor{Type: ValidationTypeOneOf, Values: []string{"a", "b"}}, "c", true},
+		{"binary measurement", core.ValidationDescriptor{Type: ValidationTypeBinaryMeasurement}, "1024k", false},
+		{"binary measurement without unit", core.ValidationDescriptor{Type: ValidationTypeBinaryMeasurement}, "1024", true},
+		{"float percentage", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "99.99", false},
+		{"float percentage without decimal place", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "50", true},
+		{"float percentage out of range", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "101.0", true},
+		{"float percentage with three digits without decimal place", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "100", true},
+		{"float percentage in exponent notation", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "5e1", true},
+		{"float percentage with other separator", core.ValidationDescriptor{Type: ValidationTypeFloatPercentageHundred}, "1x5", true},
+		{"unsupported validation", core.ValidationDescriptor{Type: "REGEX"}, "value", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateValue(tc.validation, tc.value)
+			assert.Equal(t, tc.xErr, err != nil)
+		})
+	}
+}
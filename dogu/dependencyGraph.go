@@ -0,0 +1,359 @@
+package dogu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+// DoguDependency describes the dependency of a dogu to another dogu.
+type DoguDependency struct {
+	// Dependent is the dogu that declares the dependency.
+	Dependent SimpleDoguName
+	// Dependency is the dogu that is required by the dependent.
+	Dependency SimpleDoguName
+	// VersionConstraint restricts the versions of the dependency, e.g. ">=1.2.0". An empty constraint allows every version.
+	VersionConstraint string
+	// Optional is true if the dependency is declared as optional dependency.
+	Optional bool
+}
+
+// UnsatisfiedDependency describes a dependency that is not fulfilled by the installed dogus.
+type UnsatisfiedDependency struct {
+	DoguDependency
+	// InstalledVersion is the version of the installed dependency or nil if the dependency is not installed.
+	InstalledVersion *core.Version
+}
+
+// IsMissing returns true if the dependency is not installed at all.
+func (ud UnsatisfiedDependency) IsMissing() bool {
+	return ud.InstalledVersion == nil
+}
+
+// DependencyGraph describes the dependencies between a set of dogus, usually the currently enabled versions of all
+// installed dogus. Only dependencies of the type dogu are considered. Optional dependencies are only considered if the
+// dependency is part of the graph.
+type DependencyGraph struct {
+	dogus        map[SimpleDoguName]*core.Dogu
+	dependencies map[SimpleDoguName][]DoguDependency
+}
+
+// NewDependencyGraph creates the dependency graph of the given dogus.
+func NewDependencyGraph(dogus []*core.Dogu) *DependencyGraph {
+	graph := &DependencyGraph{
+		dogus:        map[SimpleDoguName]*core.Dogu{},
+		dependencies: map[SimpleDoguName][]DoguDependency{},
+	}
+
+	for _, dogu := range dogus {
+		if dogu == nil {
+			continue
+		}
+
+		name := SimpleDoguName(dogu.GetSimpleName())
+		graph.dogus[name] = dogu
+		graph.dependencies[name] = getDoguDependencies(name, dogu)
+	}
+
+	return graph
+}
+
+// LoadDependencyGraph creates the dependency graph of the currently enabled versions of all installed dogus.
+func LoadDependencyGraph(ctx context.Context, registry LocalRegistry) (*DependencyGraph, error) {
+	dogus, err := registry.GetCurrentOfAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+
+	return NewDependencyGraph(dogus), nil
+}
+
+func getDoguDependencies(name SimpleDoguName, dogu *core.Dogu) []DoguDependency {
+	var dependencies []DoguDependency
+	for _, dependency := range dogu.Dependencies {
+		if isDoguDependency(dependency) {
+			dependencies = append(dependencies, newDoguDependency(name, dependency, false))
+		}
+	}
+
+	for _, dependency := range dogu.OptionalDependencies {
+		if isDoguDependency(dependency) {
+			dependencies = append(dependencies, newDoguDependency(name, dependency, true))
+		}
+	}
+
+	return dependencies
+}
+
+// isDoguDependency checks the type of the dependency. Dependencies without a type are dogu dependencies.
+func isDoguDependency(dependency core.Dependency) bool {
+	return dependency.Type == core.DependencyTypeDogu || dependency.Type == ""
+}
+
+func newDoguDependency(dependent SimpleDoguName, dependency core.Dependency, optional bool) DoguDependency {
+	return DoguDependency{
+		Dependent:         dependent,
+		Dependency:        SimpleDoguName(core.GetSimpleDoguName(dependency.Name)),
+		VersionConstraint: dependency.Version,
+		Optional:          optional,
+	}
+}
+
+// Contains checks if the dogu is part of the graph.
+func (dg *DependencyGraph) Contains(name SimpleDoguName) bool {
+	_, ok := dg.dogus[name]
+	return ok
+}
+
+// GetDependencies returns the mandatory and optional dependencies of the dogu, including dependencies to dogus that
+// are not part of the graph.
+func (dg *DependencyGraph) GetDependencies(name SimpleDoguName) []DoguDependency {
+	return slices.Clone(dg.dependencies[name])
+}
+
+// GetDependents returns the direct mandatory and optional dependencies of all dogus to the given dogu, sorted by the
+// name of the dependent dogu.
+func (dg *DependencyGraph) GetDependents(name SimpleDoguName) []DoguDependency {
+	var dependents []DoguDependency
+	for _, dependent := range dg.getSortedNames() {
+		for _, dependency := range dg.dependencies[dependent] {
+			if dependency.Dependency == name {
+				dependents = append(dependents, dependency)
+			}
+		}
+	}
+
+	return dependents
+}
+
+// GetAllDependents returns the names of all dogus that depend directly or transitively on the given dogu, sorted by
+// name. Mandatory and optional dependencies are followed.
+func (dg *DependencyGraph) GetAllDependents(name SimpleDoguName) []SimpleDoguName {
+	visited := map[SimpleDoguName]bool{name: true}
+	queue := []SimpleDoguName{name}
+
+	var dependents []SimpleDoguName
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependency := range dg.GetDependents(current) {
+			if visited[dependency.Dependent] {
+				continue
+			}
+
+			visited[dependency.Dependent] = true
+			dependents = append(dependents, dependency.Dependent)
+			queue = append(queue, dependency.Dependent)
+		}
+	}
+
+	slices.Sort(dependents)
+
+	return dependents
+}
+
+// GetStartOrder returns the names of all dogus of the graph, ordered so that every dogu comes after its dependencies.
+// Dogus without an order between them are sorted by name.
+// GenericError if the dogus contain a dependency cycle
+func (dg *DependencyGraph) GetStartOrder() ([]SimpleDoguName, error) {
+	// every declaration is counted, so that it matches the number of dependents returned by GetDependents
+	unresolvedDependencies := map[SimpleDoguName]int{}
+	for name, dependencies := range dg.dependencies {
+		for _, dependency := range dependencies {
+			if dg.Contains(dependency.Dependency) {
+				unresolvedDependencies[name]++
+			}
+		}
+	}
+
+	var ready []SimpleDoguName
+	for _, name := range dg.getSortedNames() {
+		if unresolvedDependencies[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]SimpleDoguName, 0, len(dg.dogus))
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		for _, dependent := range dg.GetDependents(current) {
+			unresolvedDependencies[dependent.Dependent]--
+			if unresolvedDependencies[dependent.Dependent] == 0 {
+				ready = append(ready, dependent.Dependent)
+			}
+		}
+
+		slices.Sort(ready)
+	}
+
+	if len(order) < len(dg.dogus) {
+		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to get start order of dogus: %w", newDependencyCycleError(dg.FindCycles())))
+	}
+
+	return order, nil
+}
+
+func newDependencyCycleError(cycles [][]SimpleDoguName) error {
+	cycleStrs := make([]string, 0, len(cycles))
+	for _, cycle := range cycles {
+		names := make([]string, 0, len(cycle))
+		for _, name := range cycle {
+			names = append(names, string(name))
+		}
+
+		cycleStrs = append(cycleStrs, fmt.Sprintf("[%s]", strings.Join(names, ", ")))
+	}
+
+	return fmt.Errorf("found dependency cycles between dogus: %s", strings.Join(cycleStrs, ", "))
+}
+
+// FindCycles returns the groups of dogus that depend on each other in a cycle, including dogus that depend on
+// themselves. The dogus of each group and the groups are sorted by name. Nil is returned if there are no cycles.
+func (dg *DependencyGraph) FindCycles() [][]SimpleDoguName {
+	finder := &cycleFinder{
+		graph:   dg,
+		indices: map[SimpleDoguName]int{},
+		lowLink: map[SimpleDoguName]int{},
+		onStack: map[SimpleDoguName]bool{},
+	}
+
+	for _, name := range dg.getSortedNames() {
+		if _, visited := finder.indices[name]; !visited {
+			finder.visit(name)
+		}
+	}
+
+	slices.SortFunc(finder.cycles, func(a, b []SimpleDoguName) int {
+		return strings.Compare(string(a[0]), string(b[0]))
+	})
+
+	return finder.cycles
+}
+
+// cycleFinder finds the strongly connected components of the graph with Tarjan's algorithm.
+type cycleFinder struct {
+	graph     *DependencyGraph
+	nextIndex int
+	indices   map[SimpleDoguName]int
+	lowLink   map[SimpleDoguName]int
+	stack     []SimpleDoguName
+	onStack   map[SimpleDoguName]bool
+	cycles    [][]SimpleDoguName
+}
+
+func (cf *cycleFinder) visit(name SimpleDoguName) {
+	cf.indices[name] = cf.nextIndex
+	cf.lowLink[name] = cf.nextIndex
+	cf.nextIndex++
+	cf.stack = append(cf.stack, name)
+	cf.onStack[name] = true
+
+	dependsOnItself := false
+	for _, dependency := range cf.graph.dependencies[name] {
+		next := dependency.Dependency
+		if !cf.graph.Contains(next) {
+			continue
+		}
+		if next == name {
+			dependsOnItself = true
+		}
+
+		if _, visited := cf.indices[next]; !visited {
+			cf.visit(next)
+			cf.lowLink[name] = min(cf.lowLink[name], cf.lowLink[next])
+		} else if cf.onStack[next] {
+			cf.lowLink[name] = min(cf.lowLink[name], cf.indices[next])
+		}
+	}
+
+	if cf.lowLink[name] != cf.indices[name] {
+		return
+	}
+
+	var component []SimpleDoguName
+	for {
+		last := cf.stack[len(cf.stack)-1]
+		cf.stack = cf.stack[:len(cf.stack)-1]
+		cf.onStack[last] = false
+		component = append(component, last)
+
+		if last == name {
+			break
+		}
+	}
+
+	if len(component) > 1 || dependsOnItself {
+		slices.Sort(component)
+		cf.cycles = append(cf.cycles, component)
+	}
+}
+
+// GetUnsatisfiedDependencies returns all dependencies of the dogus that are not fulfilled by the dogus of the graph,
+// sorted by the name of the dependent dogu. Mandatory dependencies are unsatisfied if the dependency is missing or its
+// version does not match the version constraint. Optional dependencies are only unsatisfied if the version of the
+// installed dependency does not match.
+// GenericError if a version or version constraint cannot be parsed
+func (dg *DependencyGraph) GetUnsatisfiedDependencies() ([]UnsatisfiedDependency, error) {
+	var unsatisfied []UnsatisfiedDependency
+	for _, name := range dg.getSortedNames() {
+		for _, dependency := range dg.dependencies[name] {
+			installed, ok := dg.dogus[dependency.Dependency]
+			if !ok {
+				if !dependency.Optional {
+					unsatisfied = append(unsatisfied, UnsatisfiedDependency{DoguDependency: dependency})
+				}
+
+				continue
+			}
+
+			installedVersion, allowed, err := checkVersionConstraint(dependency, installed)
+			if err != nil {
+				return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to check dependency of dogu %q to dogu %q: %w", dependency.Dependent, dependency.Dependency, err))
+			}
+
+			if !allowed {
+				unsatisfied = append(unsatisfied, UnsatisfiedDependency{DoguDependency: dependency, InstalledVersion: &installedVersion})
+			}
+		}
+	}
+
+	return unsatisfied, nil
+}
+
+func checkVersionConstraint(dependency DoguDependency, installed *core.Dogu) (core.Version, bool, error) {
+	installedVersion, err := parseDoguVersion(installed.Version, dependency.Dependency)
+	if err != nil {
+		return core.Version{}, false, err
+	}
+
+	comparator, err := core.ParseVersionComparator(dependency.VersionConstraint)
+	if err != nil {
+		return core.Version{}, false, fmt.Errorf("failed to parse version constraint %q: %w", dependency.VersionConstraint, err)
+	}
+
+	allowed, err := comparator.Allows(installedVersion)
+	if err != nil {
+		return core.Version{}, false, fmt.Errorf("failed to compare version %q with version constraint %q: %w", installedVersion.Raw, dependency.VersionConstraint, err)
+	}
+
+	return installedVersion, allowed, nil
+}
+
+func (dg *DependencyGraph) getSortedNames() []SimpleDoguName {
+	names := make([]SimpleDoguName, 0, len(dg.dogus))
+	for name := range dg.dogus {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
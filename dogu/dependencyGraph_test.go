@@ -0,0 +1,280 @@
+package dogu
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDogu(name string, version string, dependencies []core.Dependency, optionalDependencies ...core.Dependency) *core.Dogu {
+	return &core.Dogu{
+		Name:                 "official/" + name,
+		Version:              version,
+		Dependencies:         dependencies,
+		OptionalDependencies: optionalDependencies,
+	}
+}
+
+func newTestDependencies(names ...string) []core.Dependency {
+	dependencies := make([]core.Dependency, 0, len(names))
+	for _, name := range names {
+		dependencies = append(dependencies, core.Dependency{Type: core.DependencyTypeDogu, Name: name})
+	}
+
+	return dependencies
+}
+
+func newTestDependencyGraph() *DependencyGraph {
+	return NewDependencyGraph([]*core.Dogu{
+		newTestDogu("redmine", "5.1.3-1", []core.Dependency{
+			{Type: core.DependencyTypeDogu, Name: "postgresql", Version: ">=14.0.0-1"},
+			{Type: core.DependencyTypeDogu, Name: "cas"},
+			{Type: core.DependencyTypeClient, Name: "k8s-dogu-operator"},
+		}, core.Dependency{Type: core.DependencyTypeDogu, Name: "mail"}),
+		newTestDogu("cas", "7.0.5.1-1", newTestDependencies("ldap", "nginx")),
+		newTestDogu("nginx", "1.26.1-1", nil),
+		newTestDogu("ldap", "2.6.7-3", nil),
+		newTestDogu("postgresql", "14.12-1", nil),
+		newTestDogu("scm", "3.2.1-1", []core.Dependency{{Name: "official/cas"}}, core.Dependency{Name: "jenkins"}),
+		nil,
+	})
+}
+
+func TestLoadDependencyGraph(t *testing.T) {
+	t.Run("should load graph of current dogus", func(t *testing.T) {
+		// given
+		registryMock := NewMockLocalRegistry(t)
+		registryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]*core.Dogu{readCasDogu(t), readLdapDogu(t)}, nil)
+
+		// when
+		graph, err := LoadDependencyGraph(testCtx, registryMock)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, graph.Contains("cas"))
+		assert.True(t, graph.Contains("ldap"))
+		assert.False(t, graph.Contains("postgresql"))
+	})
+
+	t.Run("should fail to get current dogus", func(t *testing.T) {
+		// given
+		registryMock := NewMockLocalRegistry(t)
+		registryMock.EXPECT().GetCurrentOfAll(testCtx).Return(nil, assert.AnError)
+
+		// when
+		_, err := LoadDependencyGraph(testCtx, registryMock)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to load dependency graph")
+	})
+}
+
+func TestDependencyGraph_GetDependencies(t *testing.T) {
+	// given
+	sut := newTestDependencyGraph()
+
+	// when
+	dependencies := sut.GetDependencies("redmine")
+
+	// then
+	assert.Equal(t, []DoguDependency{
+		{Dependent: "redmine", Dependency: "postgresql", VersionConstraint: ">=14.0.0-1"},
+		{Dependent: "redmine", Dependency: "cas"},
+		{Dependent: "redmine", Dependency: "mail", Optional: true},
+	}, dependencies)
+	assert.Empty(t, sut.GetDependencies("nginx"))
+	assert.Empty(t, sut.GetDependencies("unknown"))
+}
+
+func TestDependencyGraph_GetDependents(t *testing.T) {
+	// given
+	sut := newTestDependencyGraph()
+
+	// when
+	dependents := sut.GetDependents("cas")
+
+	// then
+	assert.Equal(t, []DoguDependency{
+		{Dependent: "redmine", Dependency: "cas"},
+		{Dependent: "scm", Dependency: "cas"},
+	}, dependents)
+	assert.Equal(t, []DoguDependency{{Dependent: "scm", Dependency: "jenkins", Optional: true}}, sut.GetDependents("jenkins"))
+	assert.Empty(t, sut.GetDependents("redmine"))
+}
+
+func TestDependencyGraph_GetAllDependents(t *testing.T) {
+	// given
+	sut := newTestDependencyGraph()
+
+	// then
+	assert.Equal(t, []SimpleDoguName{"cas", "redmine", "scm"}, sut.GetAllDependents("ldap"))
+	assert.Equal(t, []SimpleDoguName{"redmine"}, sut.GetAllDependents("postgresql"))
+	assert.Empty(t, sut.GetAllDependents("scm"))
+}
+
+func TestDependencyGraph_GetStartOrder(t *testing.T) {
+	t.Run("should order dogus after their dependencies", func(t *testing.T) {
+		// given
+		sut := newTestDependencyGraph()
+
+		// when
+		order, err := sut.GetStartOrder()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []SimpleDoguName{"ldap", "nginx", "cas", "postgresql", "redmine", "scm"}, order)
+	})
+
+	t.Run("should consider installed optional dependencies", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("a", "1.0.0-1", nil, core.Dependency{Name: "b"}),
+			newTestDogu("b", "1.0.0-1", nil),
+		})
+
+		// when
+		order, err := sut.GetStartOrder()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []SimpleDoguName{"b", "a"}, order)
+	})
+
+	t.Run("should return empty order for empty graph", func(t *testing.T) {
+		// when
+		order, err := NewDependencyGraph(nil).GetStartOrder()
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, order)
+	})
+
+	t.Run("should fail on dependency cycle", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("a", "1.0.0-1", newTestDependencies("b")),
+			newTestDogu("b", "1.0.0-1", newTestDependencies("a")),
+			newTestDogu("c", "1.0.0-1", newTestDependencies("c")),
+			newTestDogu("d", "1.0.0-1", nil),
+		})
+
+		// when
+		_, err := sut.GetStartOrder()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get start order of dogus: found dependency cycles between dogus: [a, b], [c]")
+	})
+}
+
+func TestDependencyGraph_FindCycles(t *testing.T) {
+	t.Run("should find cycles", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("a", "1.0.0-1", newTestDependencies("b")),
+			newTestDogu("b", "1.0.0-1", newTestDependencies("c", "d")),
+			newTestDogu("c", "1.0.0-1", newTestDependencies("a")),
+			newTestDogu("d", "1.0.0-1", newTestDependencies("missing")),
+			newTestDogu("e", "1.0.0-1", newTestDependencies("e", "a")),
+			newTestDogu("f", "1.0.0-1", nil, core.Dependency{Name: "g"}),
+			newTestDogu("g", "1.0.0-1", nil, core.Dependency{Name: "f"}),
+		})
+
+		// when
+		cycles := sut.FindCycles()
+
+		// then
+		assert.Equal(t, [][]SimpleDoguName{{"a", "b", "c"}, {"e"}, {"f", "g"}}, cycles)
+	})
+
+	t.Run("should return nil without cycles", func(t *testing.T) {
+		// when
+		cycles := newTestDependencyGraph().FindCycles()
+
+		// then
+		assert.Nil(t, cycles)
+	})
+}
+
+func TestDependencyGraph_GetUnsatisfiedDependencies(t *testing.T) {
+	t.Run("should return missing dependencies and mismatching versions", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("redmine", "5.1.3-1", []core.Dependency{
+				{Name: "postgresql", Version: ">=15.0.0-1"},
+				{Name: "cas"},
+				{Name: "nginx", Version: "<=1.26.1-1"},
+			}, core.Dependency{Name: "mail"}, core.Dependency{Name: "ldap", Version: "2.6.7-3"}),
+			newTestDogu("postgresql", "14.12-1", nil),
+			newTestDogu("nginx", "1.26.1-1", nil),
+			newTestDogu("ldap", "2.6.8-1", nil),
+		})
+
+		// when
+		unsatisfied, err := sut.GetUnsatisfiedDependencies()
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, unsatisfied, 3)
+		assert.Equal(t, DoguDependency{Dependent: "redmine", Dependency: "postgresql", VersionConstraint: ">=15.0.0-1"}, unsatisfied[0].DoguDependency)
+		assert.Equal(t, "14.12-1", unsatisfied[0].InstalledVersion.Raw)
+		assert.False(t, unsatisfied[0].IsMissing())
+		assert.Equal(t, DoguDependency{Dependent: "redmine", Dependency: "cas"}, unsatisfied[1].DoguDependency)
+		assert.True(t, unsatisfied[1].IsMissing())
+		assert.Equal(t, DoguDependency{Dependent: "redmine", Dependency: "ldap", VersionConstraint: "2.6.7-3", Optional: true}, unsatisfied[2].DoguDependency)
+		assert.Equal(t, "2.6.8-1", unsatisfied[2].InstalledVersion.Raw)
+	})
+
+	t.Run("should return nil if all dependencies are satisfied", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("cas", "7.0.5.1-1", newTestDependencies("ldap")),
+			newTestDogu("ldap", "2.6.7-3", nil),
+		})
+
+		// when
+		unsatisfied, err := sut.GetUnsatisfiedDependencies()
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, unsatisfied)
+	})
+
+	t.Run("should fail on invalid version constraint", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("cas", "7.0.5.1-1", []core.Dependency{{Name: "ldap", Version: ">>>2.6.7-3"}}),
+			newTestDogu("ldap", "2.6.7-3", nil),
+		})
+
+		// when
+		_, err := sut.GetUnsatisfiedDependencies()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to check dependency of dogu \"cas\" to dogu \"ldap\": failed to parse version constraint \">>>2.6.7-3\"")
+	})
+
+	t.Run("should fail on invalid installed version", func(t *testing.T) {
+		// given
+		sut := NewDependencyGraph([]*core.Dogu{
+			newTestDogu("cas", "7.0.5.1-1", []core.Dependency{{Name: "ldap", Version: ">=2.6.7-3"}}),
+			newTestDogu("ldap", "invalid", nil),
+		})
+
+		// when
+		_, err := sut.GetUnsatisfiedDependencies()
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to parse version \"invalid\" for dogu \"ldap\"")
+	})
+}
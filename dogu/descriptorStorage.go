@@ -0,0 +1,159 @@
+package dogu
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Dogu descriptors are stored gzip-compressed in the binary data of the descriptor config map, with the version as key.
+// If the config map would exceed maxDescriptorConfigMapSize, descriptors are stored in overflow config maps instead.
+// The overflow index in the descriptor config map maps these versions to the names of their overflow config maps.
+// Descriptors written by older versions are stored uncompressed in the data of the descriptor config map. They are
+// still readable and are compressed with the next Add.
+const (
+	// overflowIndexKey contains the JSON object that maps versions to the names of their overflow config maps.
+	overflowIndexKey = "overflow"
+
+	typeLabelValueLocalDoguRegistryOverflow = "local-dogu-registry-overflow"
+
+	// maxDescriptorConfigMapSize leaves room for the metadata below the object size limit of 1 MiB.
+	maxDescriptorConfigMapSize = 900 * 1024
+)
+
+func isReservedDescriptorKey(key string) bool {
+	return key == currentVersionKey || key == previousVersionKey || key == overflowIndexKey
+}
+
+func compressDescriptor(doguJson []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write(doguJson)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress dogu descriptor: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress dogu descriptor: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func decompressDescriptor(compressed []byte) (string, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress dogu descriptor: %w", err)
+	}
+	defer reader.Close()
+
+	doguJson, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress dogu descriptor: %w", err)
+	}
+
+	return string(doguJson), nil
+}
+
+// getOverflowIndex returns the versions that are stored in overflow config maps, mapped to the names of these config maps.
+func getOverflowIndex(descriptorConfigMap corev1.ConfigMap) (map[string]string, error) {
+	index := map[string]string{}
+
+	indexStr, ok := descriptorConfigMap.Data[overflowIndexKey]
+	if !ok {
+		return index, nil
+	}
+
+	err := json.Unmarshal([]byte(indexStr), &index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse overflow index of dogu descriptor config map %q: %w", descriptorConfigMap.Name, err)
+	}
+
+	return index, nil
+}
+
+func setOverflowIndex(descriptorConfigMap *corev1.ConfigMap, index map[string]string) error {
+	if len(index) == 0 {
+		delete(descriptorConfigMap.Data, overflowIndexKey)
+		return nil
+	}
+
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to serialize overflow index of dogu descriptor config map %q: %w", descriptorConfigMap.Name, err)
+	}
+
+	if descriptorConfigMap.Data == nil {
+		descriptorConfigMap.Data = map[string]string{}
+	}
+	descriptorConfigMap.Data[overflowIndexKey] = string(indexBytes)
+
+	return nil
+}
+
+// getStoredVersionKeys returns the versions of all descriptors of the descriptor config map, regardless of their format
+// and location.
+func getStoredVersionKeys(descriptorConfigMap corev1.ConfigMap) ([]string, error) {
+	var keys []string
+	for key := range descriptorConfigMap.Data {
+		if !isReservedDescriptorKey(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range descriptorConfigMap.BinaryData {
+		keys = append(keys, key)
+	}
+
+	index, err := getOverflowIndex(descriptorConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range index {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return slices.Compact(keys), nil
+}
+
+// isDescriptorStored checks if the descriptor of the version is stored in any format or location.
+func isDescriptorStored(descriptorConfigMap corev1.ConfigMap, versionStr string) bool {
+	keys, err := getStoredVersionKeys(descriptorConfigMap)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(keys, versionStr)
+}
+
+// getConfigMapDataSize returns the size of all keys and values of the config map.
+func getConfigMapDataSize(configMap corev1.ConfigMap) int {
+	size := 0
+	for key, value := range configMap.Data {
+		size += len(key) + len(value)
+	}
+
+	for key, value := range configMap.BinaryData {
+		size += len(key) + len(value)
+	}
+
+	return size
+}
+
+func getOverflowConfigMapName(simpleDoguName SimpleDoguName, number int) string {
+	return fmt.Sprintf("%s-overflow-%d", getDescriptorConfigMapName(simpleDoguName), number)
+}
+
+func getOverflowConfigMapsSelector(simpleDoguName SimpleDoguName) string {
+	return fmt.Sprintf("%s=%s,%s=%s,%s=%s", appLabelKey, appLabelValueCes, doguNameLabelKey, simpleDoguName, typeLabelKey, typeLabelValueLocalDoguRegistryOverflow)
+}
@@ -0,0 +1,109 @@
+package dogu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func compressDoguStr(t *testing.T, doguStr string) []byte {
+	t.Helper()
+
+	compressed, err := compressDescriptor([]byte(doguStr))
+	require.NoError(t, err)
+
+	return compressed
+}
+
+func decompressDoguStr(t *testing.T, compressed []byte) string {
+	t.Helper()
+
+	doguStr, err := decompressDescriptor(compressed)
+	require.NoError(t, err)
+
+	return doguStr
+}
+
+func Test_compressDescriptor(t *testing.T) {
+	// given
+	doguStr := readCasDoguStr(t)
+
+	// when
+	compressed, err := compressDescriptor([]byte(doguStr))
+
+	// then
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(doguStr))
+	assert.Equal(t, doguStr, decompressDoguStr(t, compressed))
+}
+
+func Test_decompressDescriptor(t *testing.T) {
+	_, err := decompressDescriptor([]byte("not compressed"))
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to decompress dogu descriptor")
+}
+
+func Test_getStoredVersionKeys(t *testing.T) {
+	t.Run("should return versions of all formats and locations", func(t *testing.T) {
+		// given
+		cm := corev1.ConfigMap{
+			Data: map[string]string{
+				"1.0.0-1":  "{}",
+				"current":  "1.0.0-1",
+				"previous": "0.9.0-1",
+				"overflow": `{"0.8.0-1":"dogu-spec-cas-overflow-1","2.0.0-1":"dogu-spec-cas-overflow-1"}`,
+			},
+			BinaryData: map[string][]byte{"2.0.0-1": {}, "0.9.0-1": {}},
+		}
+
+		// when
+		keys, err := getStoredVersionKeys(cm)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0.8.0-1", "0.9.0-1", "1.0.0-1", "2.0.0-1"}, keys)
+		assert.True(t, isDescriptorStored(cm, "0.8.0-1"))
+		assert.False(t, isDescriptorStored(cm, "current"))
+	})
+
+	t.Run("should fail on invalid overflow index", func(t *testing.T) {
+		cm := corev1.ConfigMap{Data: map[string]string{"overflow": "{"}}
+
+		_, err := getStoredVersionKeys(cm)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse overflow index of dogu descriptor config map")
+		assert.False(t, isDescriptorStored(cm, "1.0.0-1"))
+	})
+}
+
+func Test_setOverflowIndex(t *testing.T) {
+	// given
+	cm := &corev1.ConfigMap{}
+
+	// when
+	err := setOverflowIndex(cm, map[string]string{"1.0.0-1": "dogu-spec-cas-overflow-1"})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `{"1.0.0-1":"dogu-spec-cas-overflow-1"}`, cm.Data[overflowIndexKey])
+
+	// when
+	err = setOverflowIndex(cm, map[string]string{})
+
+	// then
+	require.NoError(t, err)
+	assert.NotContains(t, cm.Data, overflowIndexKey)
+}
+
+func Test_getConfigMapDataSize(t *testing.T) {
+	cm := corev1.ConfigMap{
+		Data:       map[string]string{"current": "1.0.0-1"},
+		BinaryData: map[string][]byte{"1.0.0-1": {1, 2, 3}},
+	}
+
+	assert.Equal(t, 7+7+7+3, getConfigMapDataSize(cm))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -28,15 +29,20 @@ const (
 	typeLabelKey                    = "k8s.cloudogu.com/type"
 	typeLabelValueLocalDoguRegistry = "local-dogu-registry"
 	currentVersionKey               = "current"
+	// previousVersionKey contains the version that was current before the last change of the current version.
+	previousVersionKey = "previous"
 )
 
 type doguVersionRegistry struct {
 	configMapClient configMapClient
+	// descriptorRepository is used to get the dogu descriptors for pre-flight checks.
+	descriptorRepository LocalDoguDescriptorRepository
 }
 
 func NewDoguVersionRegistry(configMapClient configMapClient) *doguVersionRegistry {
 	return &doguVersionRegistry{
-		configMapClient: configMapClient,
+		configMapClient:      configMapClient,
+		descriptorRepository: NewLocalDoguDescriptorRepository(configMapClient),
 	}
 }
 
@@ -143,7 +149,43 @@ func (vr *doguVersionRegistry) IsEnabled(ctx context.Context, doguVersion DoguVe
 	return true, nil
 }
 
+// Enable sets the given version as current version of the dogu. The dogu descriptor of the version has to be added before.
 func (vr *doguVersionRegistry) Enable(ctx context.Context, doguVersion DoguVersion) error {
+	return vr.EnableWithOptions(ctx, doguVersion)
+}
+
+// EnableWithOptions works like Enable, but the given options are applied.
+// With WithPreflightCheck the dependencies are checked before, see EnableOption.
+// *PreflightError if the pre-flight check finds violations
+func (vr *doguVersionRegistry) EnableWithOptions(ctx context.Context, doguVersion DoguVersion, opts ...EnableOption) error {
+	options := applyEnableOptions(opts)
+	if options.preflightCheck {
+		preflightErrs, err := checkPreflight(ctx, vr, vr.descriptorRepository, []DoguVersion{doguVersion}, options)
+		if err != nil {
+			return fmt.Errorf("failed to enable dogu %q with version %q: %w", doguVersion.Name, doguVersion.Version.Raw, err)
+		}
+		if preflightErr, ok := preflightErrs[doguVersion.Name]; ok {
+			return fmt.Errorf("failed to enable dogu %q with version %q: %w", doguVersion.Name, doguVersion.Version.Raw, preflightErr)
+		}
+	}
+
+	_, err := vr.switchCurrentVersion(ctx, doguVersion)
+	if err != nil {
+		return cloudoguerrors.NewGenericError(fmt.Errorf("failed to enable dogu %q with version %q: %w", doguVersion.Name, doguVersion.Version.Raw, err))
+	}
+
+	return nil
+}
+
+// versionKeys contains the current and the previous version of a dogu. Empty values are not set.
+type versionKeys struct {
+	current  string
+	previous string
+}
+
+// switchCurrentVersion sets the current version of the dogu and returns the version keys before the switch.
+func (vr *doguVersionRegistry) switchCurrentVersion(ctx context.Context, doguVersion DoguVersion) (versionKeys, error) {
+	var before versionKeys
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// do not create the registry here if not existent because it would be an invalid state without the dogu descriptor.
 		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, doguVersion.Name)
@@ -151,27 +193,187 @@ func (vr *doguVersionRegistry) Enable(ctx context.Context, doguVersion DoguVersi
 			return err
 		}
 		if !isDoguVersionInstalled(*descriptorConfigMap, doguVersion.Version) {
-			return fmt.Errorf("dogu descriptor is not available")
+			return cloudoguerrors.NewNotFoundError(fmt.Errorf("dogu descriptor of dogu %q with version %q is not available", doguVersion.Name, doguVersion.Version.Raw))
+		}
+
+		before = versionKeys{current: descriptorConfigMap.Data[currentVersionKey], previous: descriptorConfigMap.Data[previousVersionKey]}
+		// the data is empty if all descriptors are stored in the binary data
+		if descriptorConfigMap.Data == nil {
+			descriptorConfigMap.Data = map[string]string{}
+		}
+		if before.current != "" && before.current != doguVersion.Version.Raw {
+			descriptorConfigMap.Data[previousVersionKey] = before.current
 		}
 		descriptorConfigMap.Data[currentVersionKey] = doguVersion.Version.Raw
 		_, err = vr.configMapClient.Update(ctx, descriptorConfigMap, metav1.UpdateOptions{})
 		return err
 	})
+
+	return before, err
+}
+
+// restoreVersionKeys resets the current and the previous version of the dogu, e.g. to roll back a switch.
+// The keys are only reset if the current version is still the given enabled version. Otherwise, a ConflictError is
+// returned, so that a version enabled in the meantime is not overwritten.
+func (vr *doguVersionRegistry) restoreVersionKeys(ctx context.Context, name SimpleDoguName, enabledVersion string, keys versionKeys) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, name)
+		if err != nil {
+			return err
+		}
+
+		currentVersion := descriptorConfigMap.Data[currentVersionKey]
+		if currentVersion != enabledVersion {
+			return cloudoguerrors.NewConflictError(fmt.Errorf("current version of dogu %q was changed from %q to %q in the meantime", name, enabledVersion, currentVersion))
+		}
+
+		setOrDeleteVersionKey(descriptorConfigMap, currentVersionKey, keys.current)
+		setOrDeleteVersionKey(descriptorConfigMap, previousVersionKey, keys.previous)
+		_, err = vr.configMapClient.Update(ctx, descriptorConfigMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func setOrDeleteVersionKey(descriptorConfigMap *corev1.ConfigMap, key string, version string) {
+	if version == "" {
+		delete(descriptorConfigMap.Data, key)
+		return
+	}
+
+	if descriptorConfigMap.Data == nil {
+		descriptorConfigMap.Data = map[string]string{}
+	}
+	descriptorConfigMap.Data[key] = version
+}
+
+// Disable makes the dogu unreachable by removing its current version. The dogu descriptors are kept, so that the
+// dogu can be enabled again. The removed version is remembered as previous version.
+// Disabling a dogu that is not enabled does nothing.
+func (vr *doguVersionRegistry) Disable(ctx context.Context, name SimpleDoguName) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, name)
+		if err != nil {
+			return err
+		}
+
+		currentVersion, ok := descriptorConfigMap.Data[currentVersionKey]
+		if !ok {
+			return nil
+		}
+
+		descriptorConfigMap.Data[previousVersionKey] = currentVersion
+		delete(descriptorConfigMap.Data, currentVersionKey)
+		_, err = vr.configMapClient.Update(ctx, descriptorConfigMap, metav1.UpdateOptions{})
+		return err
+	})
+	if cloudoguerrors.IsNotFoundError(err) {
+		return err
+	}
 	if err != nil {
-		return cloudoguerrors.NewGenericError(fmt.Errorf("failed to enable dogu %q with version %q: %w", doguVersion.Name, doguVersion.Version.Raw, err))
+		return cloudoguerrors.NewGenericError(fmt.Errorf("failed to disable dogu %q: %w", name, err))
 	}
 
 	return nil
 }
 
-func isDoguVersionInstalled(descriptorConfigMap corev1.ConfigMap, version core.Version) bool {
-	for key := range descriptorConfigMap.Data {
-		if key == version.Raw {
-			return true
+// GetAllVersions returns all versions of the dogu whose descriptors are stored in the registry, sorted from the
+// oldest to the newest version.
+func (vr *doguVersionRegistry) GetAllVersions(ctx context.Context, name SimpleDoguName) ([]core.Version, error) {
+	descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, name)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := getStoredVersions(*descriptorConfigMap, name)
+	if err != nil {
+		return versions, cloudoguerrors.NewGenericError(fmt.Errorf("failed to get some versions of dogu %q: %w", name, err))
+	}
+
+	return versions, nil
+}
+
+func getStoredVersions(descriptorConfigMap corev1.ConfigMap, name SimpleDoguName) ([]core.Version, error) {
+	keys, err := getStoredVersionKeys(descriptorConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSortedVersions(keys, name)
+}
+
+// parseSortedVersions parses the versions of the dogu and sorts them from the oldest to the newest version.
+// Versions that cannot be parsed are skipped and returned as error.
+func parseSortedVersions(keys []string, name SimpleDoguName) ([]core.Version, error) {
+	var errs []error
+	versions := make([]core.Version, 0, len(keys))
+	for _, key := range keys {
+		version, parseErr := parseDoguVersion(key, name)
+		if parseErr != nil {
+			errs = append(errs, parseErr)
+			continue
 		}
+
+		versions = append(versions, version)
 	}
 
-	return false
+	slices.SortFunc(versions, func(a, b core.Version) int {
+		if a.IsOlderThan(b) {
+			return -1
+		}
+		if a.IsNewerThan(b) {
+			return 1
+		}
+		return 0
+	})
+
+	return versions, errors.Join(errs...)
+}
+
+// GetPrevious returns the version that was current before the current version, e.g. to roll back a failed upgrade.
+// If no previous version was recorded or its descriptor is not stored anymore, the newest stored version that is older
+// than the current version is returned.
+func (vr *doguVersionRegistry) GetPrevious(ctx context.Context, name SimpleDoguName) (DoguVersion, error) {
+	descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, name)
+	if err != nil {
+		return DoguVersion{}, err
+	}
+
+	previousVersion, ok := descriptorConfigMap.Data[previousVersionKey]
+	if ok && isDescriptorStored(*descriptorConfigMap, previousVersion) {
+		version, parseErr := parseDoguVersion(previousVersion, name)
+		if parseErr != nil {
+			return DoguVersion{}, cloudoguerrors.NewGenericError(parseErr)
+		}
+
+		return DoguVersion{Name: name, Version: version}, nil
+	}
+
+	currentVersionStr, ok := descriptorConfigMap.Data[currentVersionKey]
+	if !ok {
+		return DoguVersion{}, getDoguRegistryKeyNotFoundError(previousVersionKey, name)
+	}
+
+	currentVersion, err := parseDoguVersion(currentVersionStr, name)
+	if err != nil {
+		return DoguVersion{}, cloudoguerrors.NewGenericError(err)
+	}
+
+	versions, err := getStoredVersions(*descriptorConfigMap, name)
+	if err != nil {
+		return DoguVersion{}, cloudoguerrors.NewGenericError(fmt.Errorf("failed to get some versions of dogu %q: %w", name, err))
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].IsOlderThan(currentVersion) {
+			return DoguVersion{Name: name, Version: versions[i]}, nil
+		}
+	}
+
+	return DoguVersion{}, getDoguRegistryKeyNotFoundError(previousVersionKey, name)
+}
+
+func isDoguVersionInstalled(descriptorConfigMap corev1.ConfigMap, version core.Version) bool {
+	return isDescriptorStored(descriptorConfigMap, version.Raw)
 }
 
 func (vr *doguVersionRegistry) WatchAllCurrent(ctx context.Context) (<-chan CurrentVersionsWatchResult, error) {
@@ -186,22 +388,134 @@ func (vr *doguVersionRegistry) WatchAllCurrent(ctx context.Context) (<-chan Curr
 		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to create persistence context for current dogu versions: %w", err))
 	}
 
-	retryWatcher, err := createRetryWatcher(ctx, vr, list.ResourceVersion)
+	retryWatcher, err := createRetryWatcher(ctx, vr.configMapClient, list.ResourceVersion, getAllLocalDoguRegistriesSelector())
 	if err != nil {
-		return nil, err
+		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to create watch for current dogu versions: %w", err))
 	}
 
 	return startWatchInBackground(ctx, retryWatcher, persistenceContext), nil
 }
 
-func getWatchFunc(ctx context.Context, vr *doguVersionRegistry) func(options metav1.ListOptions) (watch.Interface, error) {
+// WatchCurrent watches the current version of a single dogu. The watch is filtered server-side by the labels of the
+// descriptor config map of the dogu. A result is sent whenever the dogu is enabled or disabled or its current version
+// changes.
+func (vr *doguVersionRegistry) WatchCurrent(ctx context.Context, name SimpleDoguName) (<-chan CurrentVersionWatchResult, error) {
+	selector := getLocalDoguRegistrySelector(name)
+	list, err := vr.configMapClient.List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list initial descriptor configmap of dogu %q: %w", name, handleK8sError(err))
+	}
+
+	var currentVersion core.Version
+	for _, descriptorConfigMap := range list.Items {
+		currentVersion, err = getCurrentVersionOrEmpty(descriptorConfigMap, name)
+		if err != nil {
+			return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to get initial current version of dogu %q: %w", name, err))
+		}
+	}
+
+	retryWatcher, err := createRetryWatcher(ctx, vr.configMapClient, list.ResourceVersion, selector)
+	if err != nil {
+		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to create watch for current version of dogu %q: %w", name, err))
+	}
+
+	return startCurrentWatchInBackground(ctx, retryWatcher, name, currentVersion), nil
+}
+
+func getLocalDoguRegistrySelector(name SimpleDoguName) string {
+	return fmt.Sprintf("%s=%s,%s=%s,%s=%s", appLabelKey, appLabelValueCes, doguNameLabelKey, name, typeLabelKey, typeLabelValueLocalDoguRegistry)
+}
+
+// getCurrentVersionOrEmpty returns the current version of the dogu or an empty version if the dogu is not enabled.
+func getCurrentVersionOrEmpty(descriptorConfigMap corev1.ConfigMap, name SimpleDoguName) (core.Version, error) {
+	versionStr, ok := descriptorConfigMap.Data[currentVersionKey]
+	if !ok {
+		return core.Version{}, nil
+	}
+
+	return parseDoguVersion(versionStr, name)
+}
+
+func startCurrentWatchInBackground(ctx context.Context, watchInterface watch.Interface, name SimpleDoguName, currentVersion core.Version) <-chan CurrentVersionWatchResult {
+	logger := log.FromContext(ctx).WithName("DoguVersionRegistry.startCurrentWatchInBackground")
+	currentVersionWatchResult := make(chan CurrentVersionWatchResult)
+
+	go func() {
+		defer close(currentVersionWatchResult)
+		for {
+			select {
+			case <-ctx.Done():
+				watchInterface.Stop()
+				logger.Info("context canceled. Stop watch channel.")
+				return
+			case event, open := <-watchInterface.ResultChan():
+				if !open {
+					logger.Info("watch channel canceled. Stop watch.")
+					return
+				}
+
+				currentVersion = handleCurrentWatchEvent(ctx, event, name, currentVersion, currentVersionWatchResult)
+			}
+		}
+	}()
+
+	return currentVersionWatchResult
+}
+
+// handleCurrentWatchEvent sends a result if the current version of the dogu changed and returns the new current version.
+func handleCurrentWatchEvent(ctx context.Context, event watch.Event, name SimpleDoguName, prevVersion core.Version, currentVersionWatchResult chan CurrentVersionWatchResult) core.Version {
+	if event.Type == watch.Error {
+		currentVersionWatchResult <- CurrentVersionWatchResult{Name: name, Version: prevVersion, PrevVersion: prevVersion, Err: logWatchError(ctx, getErrorFromWatchEvent(event))}
+		return prevVersion
+	}
+
+	descriptorConfigMap, err := getDescriptorConfigMapFromEvent(event)
+	if err != nil {
+		currentVersionWatchResult <- CurrentVersionWatchResult{Name: name, Version: prevVersion, PrevVersion: prevVersion, Err: logWatchError(ctx, err)}
+		return prevVersion
+	}
+
+	var version core.Version
+	if event.Type != watch.Deleted {
+		version, err = getCurrentVersionOrEmpty(*descriptorConfigMap, name)
+		if err != nil {
+			currentVersionWatchResult <- CurrentVersionWatchResult{Name: name, Version: prevVersion, PrevVersion: prevVersion, Err: logWatchError(ctx, err)}
+			return prevVersion
+		}
+	}
+
+	if version.Raw == prevVersion.Raw {
+		return prevVersion
+	}
+
+	currentVersionWatchResult <- CurrentVersionWatchResult{Name: name, Version: version, PrevVersion: prevVersion}
+	return version
+}
+
+func getErrorFromWatchEvent(event watch.Event) error {
+	status, ok := event.Object.(*metav1.Status)
+	if !ok {
+		return fmt.Errorf("failed to cast event object to %T", metav1.Status{})
+	}
+
+	return fmt.Errorf("watch event type is error: %q", status.String())
+}
+
+// logWatchError logs the error of a watch and returns it as GenericError for the watch result.
+func logWatchError(ctx context.Context, err error) error {
+	logger := log.FromContext(ctx).WithName("logWatchError")
+	logger.Error(err, errMsgWatch)
+
+	return cloudoguerrors.NewGenericError(err)
+}
+
+func getWatchFunc(ctx context.Context, configMapClient configMapClient, selector string) func(options metav1.ListOptions) (watch.Interface, error) {
 	watchFunc := func(options metav1.ListOptions) (watch.Interface, error) {
-		selector := getAllLocalDoguRegistriesSelector()
 		options.LabelSelector = selector
-		watchInterface, err := vr.configMapClient.Watch(ctx, options)
+		watchInterface, err := configMapClient.Watch(ctx, options)
 		if k8serrors.IsGone(err) {
 			options.ResourceVersion = ""
-			watchInterface, err = vr.configMapClient.Watch(ctx, options)
+			watchInterface, err = configMapClient.Watch(ctx, options)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create watch after IsGone: %w", err)
 			}
@@ -215,13 +529,9 @@ func getWatchFunc(ctx context.Context, vr *doguVersionRegistry) func(options met
 	return watchFunc
 }
 
-func createRetryWatcher(ctx context.Context, vr *doguVersionRegistry, resourceVersion string) (*toolsWatch.RetryWatcher, error) {
-	watchFunc := getWatchFunc(ctx, vr)
-	retryWatcher, err := toolsWatch.NewRetryWatcher(resourceVersion, &cache.ListWatch{WatchFunc: watchFunc})
-	if err != nil {
-		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to create watch for current dogu versions: %w", err))
-	}
-	return retryWatcher, nil
+func createRetryWatcher(ctx context.Context, configMapClient configMapClient, resourceVersion string, selector string) (*toolsWatch.RetryWatcher, error) {
+	watchFunc := getWatchFunc(ctx, configMapClient, selector)
+	return toolsWatch.NewRetryWatcher(resourceVersion, &cache.ListWatch{WatchFunc: watchFunc})
 }
 
 func throwAndLogWatchError(ctx context.Context, err error, resultChannel chan CurrentVersionsWatchResult) {
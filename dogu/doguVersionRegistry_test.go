@@ -36,6 +36,7 @@ func TestNewDoguVersionRegistry(t *testing.T) {
 	// then
 	require.NotNil(t, sut)
 	assert.NotNil(t, sut.configMapClient)
+	assert.NotNil(t, sut.descriptorRepository)
 }
 
 func Test_versionRegistry_GetCurrent(t *testing.T) {
@@ -362,6 +363,35 @@ func Test_versionRegistry_Enable(t *testing.T) {
 			args:    casArgs,
 			wantErr: assert.NoError,
 		},
+		{
+			name: "success with descriptors only in binary data",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				binaryData := map[string][]byte{casVersionStr: compressDoguStr(t, readCasDoguStr(t))}
+				casRegistryCm := &corev1.ConfigMap{BinaryData: binaryData, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+				expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{"current": casVersionStr}, BinaryData: binaryData, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+				configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+
+				return configMapClientMock
+			},
+			args:    casArgs,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should record previous version on upgrade",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				casRegistryCm := &corev1.ConfigMap{Data: map[string]string{"7.0.4.1-1": "{}", casVersionStr: readCasDoguStr(t), "current": "7.0.4.1-1"}}
+				expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{"7.0.4.1-1": "{}", casVersionStr: readCasDoguStr(t), "current": casVersionStr, "previous": "7.0.4.1-1"}}
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+				configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+
+				return configMapClientMock
+			},
+			args:    casArgs,
+			wantErr: assert.NoError,
+		},
 		{
 			name: "should success with conflict error on retry",
 			configMapClientFn: func(t *testing.T) configMapClient {
@@ -401,7 +431,7 @@ func Test_versionRegistry_Enable(t *testing.T) {
 			args: casArgs,
 			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
 				return assert.True(t, cloudoguerrors.IsGenericError(err), i) &&
-					assert.ErrorContains(t, err, "failed to enable dogu \"cas\" with version \"7.0.5.1-1\": dogu descriptor is not available")
+					assert.ErrorContains(t, err, "failed to enable dogu \"cas\" with version \"7.0.5.1-1\": dogu descriptor of dogu \"cas\" with version \"7.0.5.1-1\" is not available")
 			},
 		},
 	}
@@ -415,6 +445,210 @@ func Test_versionRegistry_Enable(t *testing.T) {
 	}
 }
 
+func Test_versionRegistry_Disable(t *testing.T) {
+	tests := []struct {
+		name              string
+		configMapClientFn func(t *testing.T) configMapClient
+		wantErr           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "should remove current version and record it as previous version",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				casRegistryCm := &corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "current": casVersionStr}}
+				expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "previous": casVersionStr}}
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+				configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+
+				return configMapClientMock
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should do nothing if dogu is not enabled",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}"}}, nil)
+
+				return configMapClientMock
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should retry on conflict",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "current": casVersionStr}}, nil).Times(1)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "current": casVersionStr}}, nil).Times(1)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "previous": casVersionStr}}, metav1.UpdateOptions{}).Return(nil, testConflictErr).Times(1)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "previous": casVersionStr}}, metav1.UpdateOptions{}).Return(&corev1.ConfigMap{}, nil).Times(1)
+
+				return configMapClientMock
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should return not found error if registry does not exist",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "dogu-spec-cas"))
+
+				return configMapClientMock
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, cloudoguerrors.IsNotFoundError(err), i)
+			},
+		},
+		{
+			name: "should return error on update error",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{"current": casVersionStr}}, nil)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{"previous": casVersionStr}}, metav1.UpdateOptions{}).Return(nil, assert.AnError)
+
+				return configMapClientMock
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, cloudoguerrors.IsGenericError(err), i) &&
+					assert.ErrorContains(t, err, "failed to disable dogu \"cas\": assert.AnError general error for testing")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &doguVersionRegistry{
+				configMapClient: tt.configMapClientFn(t),
+			}
+			tt.wantErr(t, vr.Disable(testCtx, "cas"), fmt.Sprintf("Disable(%v, %v)", testCtx, "cas"))
+		})
+	}
+}
+
+func Test_versionRegistry_GetAllVersions(t *testing.T) {
+	t.Run("should return stored versions sorted semantically", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{
+			"7.0.10-1": "{}", "7.0.5.1-1": "{}", "7.0.9-2": "{}", "7.0.9-10": "{}", "current": "7.0.9-10", "previous": "7.0.9-2",
+		}}, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		versions, err := sut.GetAllVersions(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []core.Version{
+			parseVersionStr(t, "7.0.5.1-1"),
+			parseVersionStr(t, "7.0.9-2"),
+			parseVersionStr(t, "7.0.9-10"),
+			parseVersionStr(t, "7.0.10-1"),
+		}, versions)
+	})
+
+	t.Run("should return valid versions and error for invalid versions", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{
+			"7.0.5.1-1": "{}", "invalid": "{}",
+		}}, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		versions, err := sut.GetAllVersions(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get some versions of dogu \"cas\"")
+		assert.Equal(t, []core.Version{parseVersionStr(t, "7.0.5.1-1")}, versions)
+	})
+
+	t.Run("should return error on error getting registry", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "dogu-spec-cas"))
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.GetAllVersions(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+	})
+}
+
+func Test_versionRegistry_GetPrevious(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    DoguVersion
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "should return recorded previous version",
+			data:    map[string]string{"7.0.4-1": "{}", "7.0.5-1": "{}", "7.0.6-1": "{}", "current": "7.0.6-1", "previous": "7.0.4-1"},
+			want:    DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.4-1")},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "should return recorded previous version of disabled dogu",
+			data:    map[string]string{"7.0.4-1": "{}", "7.0.5-1": "{}", "previous": "7.0.5-1"},
+			want:    DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.5-1")},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "should return newest older version without recorded previous version",
+			data:    map[string]string{"7.0.4-1": "{}", "7.0.5-1": "{}", "7.0.6-1": "{}", "current": "7.0.6-1"},
+			want:    DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.5-1")},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "should return newest older version if recorded previous version was removed",
+			data:    map[string]string{"7.0.5-1": "{}", "7.0.6-1": "{}", "current": "7.0.6-1", "previous": "7.0.4-1"},
+			want:    DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.5-1")},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should return not found error without older version",
+			data: map[string]string{"7.0.6-1": "{}", "7.0.7-1": "{}", "current": "7.0.6-1"},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, cloudoguerrors.IsNotFoundError(err), i) &&
+					assert.ErrorContains(t, err, "failed to get value for key \"previous\" for dogu registry \"cas\"")
+			},
+		},
+		{
+			name: "should return not found error for disabled dogu without recorded previous version",
+			data: map[string]string{"7.0.6-1": "{}"},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, cloudoguerrors.IsNotFoundError(err), i)
+			},
+		},
+		{
+			name: "should return error on invalid current version",
+			data: map[string]string{"7.0.6-1": "{}", "current": "invalid"},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, cloudoguerrors.IsGenericError(err), i) &&
+					assert.ErrorContains(t, err, "failed to parse version \"invalid\" for dogu \"cas\"")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMapClientMock := newMockConfigMapClient(t)
+			configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: tt.data}, nil)
+			vr := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+			got, err := vr.GetPrevious(testCtx, "cas")
+
+			if tt.wantErr(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
 func Test_versionRegistry_WatchAllCurrent(t *testing.T) {
 	addCancelCtx, addCancelFunc := context.WithCancel(context.Background())
 	emptyAddCancelCtx, emptyAddCancelFunc := context.WithCancel(context.Background())
@@ -737,7 +971,7 @@ func Test_getWatchFunc(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			watchFunc := getWatchFunc(tt.args.ctx, tt.mockFn(t))
+			watchFunc := getWatchFunc(tt.args.ctx, tt.mockFn(t).configMapClient, getAllLocalDoguRegistriesSelector())
 			w, err := watchFunc(metav1.ListOptions{ResourceVersion: "5"})
 
 			if !tt.wantErr(t, err, fmt.Sprintf("getWatchFunc()")) {
@@ -855,3 +1089,283 @@ func Test_handleModifiedWatchEvent(t *testing.T) {
 		assert.Len(t, persistentContext, 0)
 	})
 }
+
+func Test_versionRegistry_Enable_withPreflightCheck(t *testing.T) {
+	setUp := func(t *testing.T) *doguVersionRegistry {
+		client := newFakeConfigMapClient()
+		descriptorRepository := NewLocalDoguDescriptorRepository(client)
+		sut := NewDoguVersionRegistry(client)
+		for _, dogu := range []*core.Dogu{
+			newTestDogu("postgresql", "14.12-1", nil),
+			newTestDogu("redmine", "5.1.3-1", []core.Dependency{{Name: "postgresql", Version: ">=14.0.0-1"}}),
+			newTestDogu("redmine", "5.1.4-1", []core.Dependency{{Name: "postgresql", Version: ">=15.0.0-1"}}),
+		} {
+			require.NoError(t, descriptorRepository.Add(testCtx, SimpleDoguName(dogu.GetSimpleName()), dogu))
+		}
+		require.NoError(t, sut.Enable(testCtx, DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "14.12-1")}))
+
+		return sut
+	}
+
+	t.Run("should enable version without violations", func(t *testing.T) {
+		// given
+		sut := setUp(t)
+		doguVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.3-1")}
+
+		// when
+		err := sut.EnableWithOptions(testCtx, doguVersion, WithPreflightCheck())
+
+		// then
+		require.NoError(t, err)
+		enabled, err := sut.IsEnabled(testCtx, doguVersion)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("should not enable version with violations", func(t *testing.T) {
+		// given
+		sut := setUp(t)
+		doguVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.4-1")}
+
+		// when
+		err := sut.EnableWithOptions(testCtx, doguVersion, WithPreflightCheck())
+
+		// then
+		require.Error(t, err)
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		require.Len(t, preflightErr.Violations, 1)
+		assert.Equal(t, UnsatisfiedDependencyViolation, preflightErr.Violations[0].Type)
+		assert.ErrorContains(t, err, "failed to enable dogu \"redmine\" with version \"5.1.4-1\": pre-flight check")
+		_, err = sut.GetCurrent(testCtx, "redmine")
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+	})
+
+	t.Run("should refuse downgrade unless forced", func(t *testing.T) {
+		// given
+		sut := setUp(t)
+		require.NoError(t, sut.Enable(testCtx, DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "14.12-1")}))
+		require.NoError(t, sut.Enable(testCtx, DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.4-1")}))
+		doguVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.3-1")}
+
+		// when
+		err := sut.EnableWithOptions(testCtx, doguVersion, WithPreflightCheck())
+
+		// then
+		var preflightErr *PreflightError
+		require.ErrorAs(t, err, &preflightErr)
+		require.Len(t, preflightErr.Violations, 1)
+		assert.Equal(t, DowngradeViolation, preflightErr.Violations[0].Type)
+
+		// when
+		err = sut.EnableWithOptions(testCtx, doguVersion, WithPreflightCheck(), WithForcedDowngrade())
+
+		// then
+		require.NoError(t, err)
+		enabled, err := sut.IsEnabled(testCtx, doguVersion)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+}
+
+func Test_versionRegistry_WatchCurrent(t *testing.T) {
+	ldapLabelSelector := "app=ces,dogu.name=ldap,k8s.cloudogu.com/type=local-dogu-registry"
+	newLdapRegistryCm := func(data map[string]string, resourceVersion string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{Data: data, ObjectMeta: metav1.ObjectMeta{Name: "dogu-spec-ldap", Labels: ldapVersionRegistryLabelMap, ResourceVersion: resourceVersion}}
+	}
+
+	t.Run("should send changes of the current version", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		watcher := watch.NewFake()
+		registryCmList := &corev1.ConfigMapList{
+			Items:    []corev1.ConfigMap{*newLdapRegistryCm(map[string]string{"current": "2.6.7-3"}, "1")},
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: ldapLabelSelector}).Return(registryCmList, nil)
+		configMapClientMock.EXPECT().Watch(ctx, metav1.ListOptions{LabelSelector: ldapLabelSelector, ResourceVersion: "1", AllowWatchBookmarks: true}).Return(watcher, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		watchCh, err := sut.WatchCurrent(ctx, "ldap")
+
+		// then
+		require.NoError(t, err)
+		go func() {
+			watcher.Modify(newLdapRegistryCm(map[string]string{"current": "2.6.7-3", "2.6.8-1": "{}"}, "2"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{"current": "2.6.8-1", "previous": "2.6.7-3"}, "3"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{"previous": "2.6.8-1"}, "4"))
+			watcher.Add(newLdapRegistryCm(map[string]string{"current": "2.6.8-1"}, "5"))
+			watcher.Delete(newLdapRegistryCm(map[string]string{"current": "2.6.8-1"}, "6"))
+		}()
+
+		result := <-watchCh
+		require.NoError(t, result.Err)
+		assert.Equal(t, CurrentVersionWatchResult{Name: "ldap", Version: parseVersionStr(t, "2.6.8-1"), PrevVersion: parseVersionStr(t, "2.6.7-3")}, result)
+		result = <-watchCh
+		assert.Equal(t, CurrentVersionWatchResult{Name: "ldap", PrevVersion: parseVersionStr(t, "2.6.8-1")}, result)
+		result = <-watchCh
+		assert.Equal(t, CurrentVersionWatchResult{Name: "ldap", Version: parseVersionStr(t, "2.6.8-1")}, result)
+		result = <-watchCh
+		assert.Equal(t, CurrentVersionWatchResult{Name: "ldap", PrevVersion: parseVersionStr(t, "2.6.8-1")}, result)
+
+		cancel()
+		_, open := <-watchCh
+		assert.False(t, open)
+	})
+
+	t.Run("should send error on invalid current version", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		watcher := watch.NewFake()
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: ldapLabelSelector}).Return(&corev1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil)
+		configMapClientMock.EXPECT().Watch(ctx, metav1.ListOptions{LabelSelector: ldapLabelSelector, ResourceVersion: "1", AllowWatchBookmarks: true}).Return(watcher, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		watchCh, err := sut.WatchCurrent(ctx, "ldap")
+
+		// then
+		require.NoError(t, err)
+		go watcher.Add(newLdapRegistryCm(map[string]string{"current": "abc"}, "2"))
+
+		result := <-watchCh
+		require.Error(t, result.Err)
+		assert.True(t, cloudoguerrors.IsGenericError(result.Err))
+		assert.ErrorContains(t, result.Err, "failed to parse version \"abc\" for dogu \"ldap\"")
+		assert.Empty(t, result.Version.Raw)
+	})
+
+	t.Run("should fail to list initial descriptor config map", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: ldapLabelSelector}).Return(nil, assert.AnError)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.WatchCurrent(testCtx, "ldap")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to list initial descriptor configmap of dogu \"ldap\"")
+	})
+
+	t.Run("should fail on invalid initial current version", func(t *testing.T) {
+		// given
+		registryCmList := &corev1.ConfigMapList{Items: []corev1.ConfigMap{*newLdapRegistryCm(map[string]string{"current": "abc"}, "1")}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: ldapLabelSelector}).Return(registryCmList, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.WatchCurrent(testCtx, "ldap")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get initial current version of dogu \"ldap\"")
+	})
+
+	t.Run("should fail to create watch without resource version", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: ldapLabelSelector}).Return(&corev1.ConfigMapList{}, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.WatchCurrent(testCtx, "ldap")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to create watch for current version of dogu \"ldap\"")
+	})
+}
+
+func Test_handleCurrentWatchEvent(t *testing.T) {
+	t.Run("should send error from event", func(t *testing.T) {
+		// given
+		prevVersion := parseVersionStr(t, "2.6.7-3")
+		resultChannel := make(chan CurrentVersionWatchResult)
+
+		// when
+		go func() {
+			version := handleCurrentWatchEvent(testCtx, watch.Event{Type: watch.Error, Object: &metav1.Status{}}, "ldap", prevVersion, resultChannel)
+			assert.Equal(t, prevVersion, version)
+		}()
+
+		// then
+		result := <-resultChannel
+		assert.Equal(t, prevVersion, result.Version)
+		assert.ErrorContains(t, result.Err, "watch event type is error")
+	})
+
+	t.Run("should send error on wrong object type", func(t *testing.T) {
+		// given
+		resultChannel := make(chan CurrentVersionWatchResult)
+
+		// when
+		go handleCurrentWatchEvent(testCtx, watch.Event{Type: watch.Modified, Object: &corev1.Secret{}}, "ldap", core.Version{}, resultChannel)
+
+		// then
+		result := <-resultChannel
+		assert.ErrorContains(t, result.Err, "failed to cast event object")
+	})
+}
+
+func Test_doguVersionRegistry_switchCurrentVersion(t *testing.T) {
+	t.Run("should return not found error if the descriptor of the version is not available", func(t *testing.T) {
+		// given
+		casRegistryCm := &corev1.ConfigMap{Data: map[string]string{}, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.switchCurrentVersion(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, casVersionStr)})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "dogu descriptor of dogu \"cas\" with version \"7.0.5.1-1\" is not available")
+	})
+}
+
+func Test_doguVersionRegistry_restoreVersionKeys(t *testing.T) {
+	t.Run("should restore version keys", func(t *testing.T) {
+		// given
+		casRegistryCm := &corev1.ConfigMap{Data: map[string]string{"current": "7.1.0-1", "previous": casVersionStr}, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+		expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{"current": casVersionStr}, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+		configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		err := sut.restoreVersionKeys(testCtx, "cas", "7.1.0-1", versionKeys{current: casVersionStr})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return conflict error if the current version was changed in the meantime", func(t *testing.T) {
+		// given
+		casRegistryCm := &corev1.ConfigMap{Data: map[string]string{"current": "7.2.0-1", "previous": "7.1.0-1"}, ObjectMeta: metav1.ObjectMeta{Labels: casVersionRegistryLabelMap}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+		sut := &doguVersionRegistry{configMapClient: configMapClientMock}
+
+		// when
+		err := sut.restoreVersionKeys(testCtx, "cas", "7.1.0-1", versionKeys{current: casVersionStr})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsConflictError(err))
+		assert.ErrorContains(t, err, "current version of dogu \"cas\" was changed from \"7.1.0-1\" to \"7.2.0-1\" in the meantime")
+	})
+}
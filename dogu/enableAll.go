@@ -0,0 +1,146 @@
+package dogu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+// EnableResult describes the outcome of a single dogu version of EnableAll.
+type EnableResult struct {
+	DoguVersion DoguVersion
+	// Enabled is true if the version is the current version of the dogu after EnableAll.
+	Enabled bool
+	// RolledBack is true if the version was enabled and then reset to the former current version, because another
+	// version could not be enabled.
+	RolledBack bool
+	// Skipped is true if the version was not enabled, because another version could not be validated or enabled.
+	Skipped bool
+	// Err describes why the version could not be validated, enabled or rolled back. Violations of the pre-flight
+	// check are returned as *PreflightError.
+	Err error
+}
+
+// EnableAll sets the given versions as current versions of their dogus, e.g. to upgrade a group of interdependent
+// dogus. All versions are validated before any dogu is changed. If a version cannot be enabled, the dogus that were
+// already switched are rolled back to their former current versions. The outcome of every version is returned in the
+// order of the given versions.
+// With WithPreflightCheck the dependencies of all versions are checked together, see EnableOption.
+// GenericError if any version could not be validated, enabled or rolled back
+func (vr *doguVersionRegistry) EnableAll(ctx context.Context, doguVersions []DoguVersion, opts ...EnableOption) ([]EnableResult, error) {
+	results := make([]EnableResult, len(doguVersions))
+	for i, doguVersion := range doguVersions {
+		results[i].DoguVersion = doguVersion
+	}
+
+	err := vr.validateAll(ctx, results, applyEnableOptions(opts))
+	if err != nil {
+		markSkipped(results)
+		return results, cloudoguerrors.NewGenericError(fmt.Errorf("failed to validate dogu versions: %w", err))
+	}
+
+	keysBefore := make([]versionKeys, len(doguVersions))
+	for i, doguVersion := range doguVersions {
+		keysBefore[i], err = vr.switchCurrentVersion(ctx, doguVersion)
+		if err != nil {
+			results[i].Err = err
+			markSkipped(results[i+1:])
+			err = fmt.Errorf("failed to enable dogu %q with version %q: %w", doguVersion.Name, doguVersion.Version.Raw, err)
+			rollbackErr := vr.rollBack(ctx, results[:i], keysBefore[:i])
+
+			return results, cloudoguerrors.NewGenericError(errors.Join(err, rollbackErr))
+		}
+
+		results[i].Enabled = true
+	}
+
+	return results, nil
+}
+
+// validateAll checks that every dogu is contained once and that the descriptors of all versions are stored. The
+// violations of each version are set in its result.
+func (vr *doguVersionRegistry) validateAll(ctx context.Context, results []EnableResult, options enableOptions) error {
+	doguVersions := make([]DoguVersion, 0, len(results))
+	contained := map[SimpleDoguName]bool{}
+	for i := range results {
+		result := &results[i]
+		doguVersions = append(doguVersions, result.DoguVersion)
+
+		if contained[result.DoguVersion.Name] {
+			result.Err = fmt.Errorf("dogu %q is contained more than once", result.DoguVersion.Name)
+			continue
+		}
+		contained[result.DoguVersion.Name] = true
+
+		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, vr.configMapClient, result.DoguVersion.Name)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+
+		if !isDoguVersionInstalled(*descriptorConfigMap, result.DoguVersion.Version) {
+			result.Err = cloudoguerrors.NewNotFoundError(fmt.Errorf("dogu descriptor of dogu %q with version %q is not available", result.DoguVersion.Name, result.DoguVersion.Version.Raw))
+		}
+	}
+
+	err := joinResultErrors(results)
+	if err != nil || !options.preflightCheck {
+		return err
+	}
+
+	preflightErrs, err := checkPreflight(ctx, vr, vr.descriptorRepository, doguVersions, options)
+	if err != nil {
+		return err
+	}
+
+	for i := range results {
+		if preflightErr, ok := preflightErrs[results[i].DoguVersion.Name]; ok {
+			results[i].Err = preflightErr
+		}
+	}
+
+	return joinResultErrors(results)
+}
+
+// rollBack resets the switched dogus to their version keys before the switch, in reverse order. A dogu is not reset if
+// its current version was changed in the meantime; a ConflictError is set in its result instead.
+// The rollback is not aborted if the context is canceled, so that the registry is not left in a mixed state.
+func (vr *doguVersionRegistry) rollBack(ctx context.Context, results []EnableResult, keysBefore []versionKeys) error {
+	ctx = context.WithoutCancel(ctx)
+
+	for i := len(results) - 1; i >= 0; i-- {
+		name := results[i].DoguVersion.Name
+		err := vr.restoreVersionKeys(ctx, name, results[i].DoguVersion.Version.Raw, keysBefore[i])
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to roll back dogu %q: %w", name, err)
+			continue
+		}
+
+		results[i].Enabled = false
+		results[i].RolledBack = true
+	}
+
+	return joinResultErrors(results)
+}
+
+// markSkipped marks all versions without error as skipped.
+func markSkipped(results []EnableResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Skipped = true
+		}
+	}
+}
+
+func joinResultErrors(results []EnableResult) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,240 @@
+package dogu
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newEnableAllClientSet creates a client set with the descriptors of redmine 5.1.3-1 and 5.1.4-1 and postgresql
+// 14.12-1 and 15.8-1, where redmine 5.1.3-1 and postgresql 14.12-1 are enabled.
+func newEnableAllClientSet(t *testing.T) *fake.Clientset {
+	clientSet := fake.NewSimpleClientset()
+	client := clientSet.CoreV1().ConfigMaps(testNamespace)
+	descriptorRepository := NewLocalDoguDescriptorRepository(client)
+	for _, dogu := range []*core.Dogu{
+		newTestDogu("postgresql", "14.12-1", nil),
+		newTestDogu("postgresql", "15.8-1", nil),
+		newTestDogu("redmine", "5.1.3-1", []core.Dependency{{Name: "postgresql", Version: ">=14.0.0-1"}}),
+		newTestDogu("redmine", "5.1.4-1", []core.Dependency{{Name: "postgresql", Version: ">=15.0.0-1"}}),
+	} {
+		require.NoError(t, descriptorRepository.Add(testCtx, SimpleDoguName(dogu.GetSimpleName()), dogu))
+	}
+
+	versionRegistry := NewDoguVersionRegistry(client)
+	require.NoError(t, versionRegistry.Enable(testCtx, DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "14.12-1")}))
+	require.NoError(t, versionRegistry.Enable(testCtx, DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.3-1")}))
+
+	return clientSet
+}
+
+// failUpdates lets every update of the config map fail after the given number of successful updates.
+func failUpdates(clientSet *fake.Clientset, name string, successfulUpdates int) {
+	clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		configMap := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+		if configMap.Name != name {
+			return false, nil, nil
+		}
+
+		if successfulUpdates > 0 {
+			successfulUpdates--
+			return false, nil, nil
+		}
+
+		return true, nil, assert.AnError
+	})
+}
+
+func assertVersionKeys(t *testing.T, clientSet *fake.Clientset, name string, expected versionKeys) {
+	t.Helper()
+
+	configMap, err := clientSet.CoreV1().ConfigMaps(testNamespace).Get(testCtx, getDescriptorConfigMapName(SimpleDoguName(name)), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, versionKeys{current: configMap.Data[currentVersionKey], previous: configMap.Data[previousVersionKey]})
+}
+
+func Test_doguVersionRegistry_EnableAll(t *testing.T) {
+	postgresqlVersion := DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "15.8-1")}
+	redmineVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.4-1")}
+
+	t.Run("should enable all versions", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{postgresqlVersion, redmineVersion}, WithPreflightCheck())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []EnableResult{
+			{DoguVersion: postgresqlVersion, Enabled: true},
+			{DoguVersion: redmineVersion, Enabled: true},
+		}, results)
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "15.8-1", previous: "14.12-1"})
+		assertVersionKeys(t, clientSet, "redmine", versionKeys{current: "5.1.4-1", previous: "5.1.3-1"})
+	})
+
+	t.Run("should do nothing without versions", func(t *testing.T) {
+		// given
+		sut := NewDoguVersionRegistry(newMockConfigMapClient(t))
+
+		// when
+		results, err := sut.EnableAll(testCtx, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("should not change any dogu if a version is invalid", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+		missingVersion := DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "16.4-1")}
+		unknownDogu := DoguVersion{Name: "scm", Version: parseVersionStr(t, "3.2.1-1")}
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{redmineVersion, missingVersion, unknownDogu, postgresqlVersion})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to validate dogu versions")
+		require.Len(t, results, 4)
+		assert.NoError(t, results[0].Err)
+		assert.True(t, results[0].Skipped)
+		assert.True(t, cloudoguerrors.IsNotFoundError(results[1].Err))
+		assert.ErrorContains(t, results[1].Err, "dogu descriptor of dogu \"postgresql\" with version \"16.4-1\" is not available")
+		assert.True(t, cloudoguerrors.IsNotFoundError(results[2].Err))
+		assert.False(t, results[2].Skipped)
+		assert.ErrorContains(t, results[3].Err, "dogu \"postgresql\" is contained more than once")
+		for _, result := range results {
+			assert.False(t, result.Enabled)
+		}
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "14.12-1"})
+		assertVersionKeys(t, clientSet, "redmine", versionKeys{current: "5.1.3-1"})
+	})
+
+	t.Run("should not change any dogu on pre-flight violations", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{redmineVersion}, WithPreflightCheck())
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		require.Len(t, results, 1)
+		var preflightErr *PreflightError
+		require.ErrorAs(t, results[0].Err, &preflightErr)
+		assert.Equal(t, UnsatisfiedDependencyViolation, preflightErr.Violations[0].Type)
+		assertVersionKeys(t, clientSet, "redmine", versionKeys{current: "5.1.3-1"})
+	})
+
+	t.Run("should roll back switched dogus if a version cannot be enabled", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		failUpdates(clientSet, "dogu-spec-redmine", 0)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{postgresqlVersion, redmineVersion})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to enable dogu \"redmine\" with version \"5.1.4-1\"")
+		require.Len(t, results, 2)
+		assert.Equal(t, EnableResult{DoguVersion: postgresqlVersion, RolledBack: true}, results[0])
+		assert.False(t, results[1].Enabled)
+		assert.ErrorIs(t, results[1].Err, assert.AnError)
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "14.12-1"})
+		assertVersionKeys(t, clientSet, "redmine", versionKeys{current: "5.1.3-1"})
+	})
+
+	t.Run("should mark versions after a failed version as skipped", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		failUpdates(clientSet, "dogu-spec-postgresql", 0)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{postgresqlVersion, redmineVersion})
+
+		// then
+		require.Error(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, assert.AnError)
+		assert.False(t, results[0].Skipped)
+		assert.Equal(t, EnableResult{DoguVersion: redmineVersion, Skipped: true}, results[1])
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "14.12-1"})
+		assertVersionKeys(t, clientSet, "redmine", versionKeys{current: "5.1.3-1"})
+	})
+
+	t.Run("should not roll back a dogu whose current version was changed in the meantime", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			configMap := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+			if configMap.Name != "dogu-spec-redmine" {
+				return false, nil, nil
+			}
+
+			// another client enables a different postgresql version before redmine fails
+			configMapsResource := corev1.SchemeGroupVersion.WithResource("configmaps")
+			postgresqlObj, err := clientSet.Tracker().Get(configMapsResource, testNamespace, "dogu-spec-postgresql")
+			require.NoError(t, err)
+			postgresqlConfigMap := postgresqlObj.(*corev1.ConfigMap).DeepCopy()
+			postgresqlConfigMap.Data[currentVersionKey] = "14.12-1"
+			postgresqlConfigMap.Data[previousVersionKey] = "15.8-1"
+			require.NoError(t, clientSet.Tracker().Update(configMapsResource, postgresqlConfigMap, testNamespace))
+
+			return true, nil, assert.AnError
+		})
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{postgresqlVersion, redmineVersion})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to roll back dogu \"postgresql\"")
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Enabled)
+		assert.False(t, results[0].RolledBack)
+		assert.True(t, cloudoguerrors.IsConflictError(results[0].Err))
+		assert.ErrorContains(t, results[0].Err, "current version of dogu \"postgresql\" was changed from \"15.8-1\" to \"14.12-1\" in the meantime")
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "14.12-1", previous: "15.8-1"})
+	})
+
+	t.Run("should report failed rollback", func(t *testing.T) {
+		// given
+		clientSet := newEnableAllClientSet(t)
+		failUpdates(clientSet, "dogu-spec-postgresql", 1)
+		failUpdates(clientSet, "dogu-spec-redmine", 0)
+		sut := NewDoguVersionRegistry(clientSet.CoreV1().ConfigMaps(testNamespace))
+
+		// when
+		results, err := sut.EnableAll(testCtx, []DoguVersion{postgresqlVersion, redmineVersion})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to roll back dogu \"postgresql\"")
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Enabled)
+		assert.False(t, results[0].RolledBack)
+		assert.ErrorIs(t, results[0].Err, assert.AnError)
+		assertVersionKeys(t, clientSet, "postgresql", versionKeys{current: "15.8-1", previous: "14.12-1"})
+	})
+}
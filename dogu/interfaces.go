@@ -11,6 +11,8 @@ import (
 type LocalRegistry interface {
 	// Enable makes the dogu spec reachable.
 	Enable(ctx context.Context, dogu *core.Dogu) error
+	// EnableWithOptions works like Enable, but options like WithPreflightCheck can be added.
+	EnableWithOptions(ctx context.Context, dogu *core.Dogu, opts ...EnableOption) error
 	// Register adds the given dogu spec to the local registry.
 	Register(ctx context.Context, dogu *core.Dogu) error
 	// UnregisterAllVersions deletes all versions of the dogu spec from the local registry and makes the spec unreachable.
@@ -39,8 +41,22 @@ type DoguVersionRegistry interface {
 	GetCurrent(context.Context, SimpleDoguName) (DoguVersion, error)
 	GetCurrentOfAll(context.Context) ([]DoguVersion, error)
 	IsEnabled(context.Context, DoguVersion) (bool, error)
+	// Enable sets the given version as current version of the dogu.
 	Enable(context.Context, DoguVersion) error
+	// EnableWithOptions works like Enable, but options like WithPreflightCheck can be added.
+	EnableWithOptions(context.Context, DoguVersion, ...EnableOption) error
+	// EnableAll sets the given versions as current versions of their dogus and rolls all of them back if any version
+	// cannot be enabled. The outcome of every version is returned in the order of the given versions.
+	EnableAll(context.Context, []DoguVersion, ...EnableOption) ([]EnableResult, error)
+	// Disable removes the current version of the dogu, so that it is not reachable anymore.
+	Disable(context.Context, SimpleDoguName) error
+	// GetAllVersions returns all stored versions of the dogu, sorted from the oldest to the newest version.
+	GetAllVersions(context.Context, SimpleDoguName) ([]core.Version, error)
+	// GetPrevious returns the version that was current before the current version.
+	GetPrevious(context.Context, SimpleDoguName) (DoguVersion, error)
 	WatchAllCurrent(context.Context) (<-chan CurrentVersionsWatchResult, error)
+	// WatchCurrent watches the current version of a single dogu.
+	WatchCurrent(context.Context, SimpleDoguName) (<-chan CurrentVersionWatchResult, error)
 }
 
 type CurrentVersionsWatchResult struct {
@@ -50,10 +66,36 @@ type CurrentVersionsWatchResult struct {
 	Err          error
 }
 
-// LocalDoguDescriptorRepository is an append-only Repository, no updates will happen
+// CurrentVersionWatchResult describes a change of the current version of a single dogu.
+type CurrentVersionWatchResult struct {
+	Name SimpleDoguName
+	// Version is the current version after the change. It is empty if the dogu is not enabled.
+	Version core.Version
+	// PrevVersion is the current version before the change. It is empty if the dogu was not enabled.
+	PrevVersion core.Version
+	Err         error
+}
+
+// LocalDoguDescriptorRepository is an append-only Repository, no updates will happen. Only old versions can be deleted.
 type LocalDoguDescriptorRepository interface {
 	Get(context.Context, DoguVersion) (*core.Dogu, error)
 	GetAll(context.Context, []DoguVersion) (map[DoguVersion]*core.Dogu, error)
 	Add(context.Context, SimpleDoguName, *core.Dogu) error
+	// Delete removes the descriptor of a single version, which must not be the current version.
+	Delete(context.Context, DoguVersion) error
+	// Prune removes all descriptors except the given number of newest versions and the current version.
+	Prune(ctx context.Context, name SimpleDoguName, keepN int) ([]core.Version, error)
 	DeleteAll(context.Context, SimpleDoguName) error
+	// WatchDescriptors watches the descriptors of all dogus for added and deleted versions.
+	WatchDescriptors(context.Context) (<-chan DescriptorWatchResult, error)
+}
+
+// DescriptorWatchResult describes the versions of a dogu whose descriptors were added or deleted.
+type DescriptorWatchResult struct {
+	Name SimpleDoguName
+	// Added contains the added versions, sorted from the oldest to the newest version.
+	Added []core.Version
+	// Deleted contains the deleted versions, sorted from the oldest to the newest version.
+	Deleted []core.Version
+	Err     error
 }
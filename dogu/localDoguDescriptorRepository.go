@@ -9,11 +9,17 @@ import (
 	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"slices"
 )
 
 type localDoguDescriptorRepository struct {
 	configMapClient configMapClient
+	// maxConfigMapSize is the size from which descriptors are stored in overflow config maps.
+	// maxDescriptorConfigMapSize is used if it is not set.
+	maxConfigMapSize int
 }
 
 func NewLocalDoguDescriptorRepository(configMapClient configMapClient) *localDoguDescriptorRepository {
@@ -22,6 +28,14 @@ func NewLocalDoguDescriptorRepository(configMapClient configMapClient) *localDog
 	}
 }
 
+func (lddr *localDoguDescriptorRepository) sizeLimit() int {
+	if lddr.maxConfigMapSize <= 0 {
+		return maxDescriptorConfigMapSize
+	}
+
+	return lddr.maxConfigMapSize
+}
+
 func (lddr *localDoguDescriptorRepository) Get(ctx context.Context, doguVersion DoguVersion) (*core.Dogu, error) {
 	doguName := doguVersion.Name
 	descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, lddr.configMapClient, doguName)
@@ -30,9 +44,9 @@ func (lddr *localDoguDescriptorRepository) Get(ctx context.Context, doguVersion
 	}
 
 	versionStr := doguVersion.Version.Raw
-	doguStr, ok := descriptorConfigMap.Data[versionStr]
-	if !ok {
-		return nil, getDoguRegistryKeyNotFoundError(versionStr, doguName)
+	doguStr, err := lddr.readDescriptor(ctx, *descriptorConfigMap, doguName, versionStr, map[string]*corev1.ConfigMap{})
+	if err != nil {
+		return nil, err
 	}
 
 	result, err := unmarshalDoguJsonStr(doguStr, doguName, versionStr)
@@ -42,6 +56,53 @@ func (lddr *localDoguDescriptorRepository) Get(ctx context.Context, doguVersion
 	return result, err
 }
 
+// readDescriptor returns the JSON of the descriptor of the version, regardless of its format and location.
+// Overflow config maps are read only once and kept in the given map.
+func (lddr *localDoguDescriptorRepository) readDescriptor(ctx context.Context, descriptorConfigMap corev1.ConfigMap, doguName SimpleDoguName, versionStr string, overflowConfigMaps map[string]*corev1.ConfigMap) (string, error) {
+	if compressed, ok := descriptorConfigMap.BinaryData[versionStr]; ok {
+		return decompressDogu(compressed, doguName, versionStr)
+	}
+
+	if doguStr, ok := descriptorConfigMap.Data[versionStr]; ok && !isReservedDescriptorKey(versionStr) {
+		return doguStr, nil
+	}
+
+	index, err := getOverflowIndex(descriptorConfigMap)
+	if err != nil {
+		return "", cloudoguerrors.NewGenericError(err)
+	}
+
+	overflowName, ok := index[versionStr]
+	if !ok {
+		return "", getDoguRegistryKeyNotFoundError(versionStr, doguName)
+	}
+
+	overflowConfigMap, ok := overflowConfigMaps[overflowName]
+	if !ok {
+		overflowConfigMap, err = lddr.configMapClient.Get(ctx, overflowName, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get overflow config map %q for dogu %q: %w", overflowName, doguName, handleK8sError(err))
+		}
+		overflowConfigMaps[overflowName] = overflowConfigMap
+	}
+
+	compressed, ok := overflowConfigMap.BinaryData[versionStr]
+	if !ok {
+		return "", getDoguRegistryKeyNotFoundError(versionStr, doguName)
+	}
+
+	return decompressDogu(compressed, doguName, versionStr)
+}
+
+func decompressDogu(compressed []byte, doguName SimpleDoguName, versionStr string) (string, error) {
+	doguStr, err := decompressDescriptor(compressed)
+	if err != nil {
+		return "", cloudoguerrors.NewGenericError(fmt.Errorf("failed to read descriptor for dogu %q with version %q: %w", doguName, versionStr, err))
+	}
+
+	return doguStr, nil
+}
+
 func unmarshalDoguJsonStr(doguStr string, doguName SimpleDoguName, doguVersion string) (*core.Dogu, error) {
 	dogu := &core.Dogu{}
 	err := json.Unmarshal([]byte(doguStr), dogu)
@@ -69,12 +130,18 @@ func (lddr *localDoguDescriptorRepository) GetAll(ctx context.Context, doguVersi
 			multiErr = append(multiErr, err)
 			continue
 		}
+
+		overflowConfigMaps := map[string]*corev1.ConfigMap{}
 		for _, doguVersion := range versions {
-			doguStr, ok := doguDescriptorConfigMap.Data[doguVersion.Version.Raw]
-			if !ok {
+			doguStr, readErr := lddr.readDescriptor(ctx, *doguDescriptorConfigMap, doguName, doguVersion.Version.Raw, overflowConfigMaps)
+			if cloudoguerrors.IsNotFoundError(readErr) {
 				multiErr = append(multiErr, fmt.Errorf("did not find expected version %q for dogu %q in dogu descriptor configmap", doguVersion.Version.Raw, doguName))
 				continue
 			}
+			if readErr != nil {
+				multiErr = append(multiErr, readErr)
+				continue
+			}
 
 			dogu, unmarshalErr := unmarshalDoguJsonStr(doguStr, doguName, doguVersion.Version.Raw)
 			if unmarshalErr != nil {
@@ -94,6 +161,9 @@ func (lddr *localDoguDescriptorRepository) GetAll(ctx context.Context, doguVersi
 	return allDogus, nil
 }
 
+// Add stores the gzip-compressed descriptor in the descriptor config map of the dogu. If the config map would get too
+// large, the descriptor is stored in an overflow config map instead. Uncompressed descriptors of older versions are
+// left in place, so that readers without support for the compressed format can still find them.
 func (lddr *localDoguDescriptorRepository) Add(ctx context.Context, name SimpleDoguName, dogu *core.Dogu) error {
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		doguDescriptorConfigMap, err := getOrCreateDescriptorConfigMapForDogu(ctx, lddr.configMapClient, name)
@@ -105,8 +175,7 @@ func (lddr *localDoguDescriptorRepository) Add(ctx context.Context, name SimpleD
 			doguDescriptorConfigMap.Data = map[string]string{}
 		}
 
-		_, alreadyExists := doguDescriptorConfigMap.Data[dogu.Version]
-		if alreadyExists {
+		if isDescriptorStored(*doguDescriptorConfigMap, dogu.Version) {
 			return cloudoguerrors.NewAlreadyExistsError(fmt.Errorf("%q dogu descriptor already exists for version %q", name, dogu.Version))
 		}
 
@@ -115,7 +184,22 @@ func (lddr *localDoguDescriptorRepository) Add(ctx context.Context, name SimpleD
 			return cloudoguerrors.NewGenericError(fmt.Errorf("failed to marshal dogu %v: %w", dogu, err))
 		}
 
-		doguDescriptorConfigMap.Data[dogu.Version] = string(doguBytes)
+		compressed, err := compressDescriptor(doguBytes)
+		if err != nil {
+			return cloudoguerrors.NewGenericError(fmt.Errorf("failed to store dogu %q with version %q: %w", name, dogu.Version, err))
+		}
+
+		if getConfigMapDataSize(*doguDescriptorConfigMap)+len(dogu.Version)+len(compressed) <= lddr.sizeLimit() {
+			if doguDescriptorConfigMap.BinaryData == nil {
+				doguDescriptorConfigMap.BinaryData = map[string][]byte{}
+			}
+			doguDescriptorConfigMap.BinaryData[dogu.Version] = compressed
+		} else {
+			err = lddr.addToOverflow(ctx, doguDescriptorConfigMap, name, dogu.Version, compressed)
+			if err != nil {
+				return err
+			}
+		}
 
 		_, err = lddr.configMapClient.Update(ctx, doguDescriptorConfigMap, metav1.UpdateOptions{})
 		if err != nil {
@@ -132,6 +216,100 @@ func (lddr *localDoguDescriptorRepository) Add(ctx context.Context, name SimpleD
 	return nil
 }
 
+// addToOverflow stores the compressed descriptor in the first overflow config map with enough space and adds it to the
+// overflow index of the descriptor config map. A new overflow config map is created if none has enough space.
+func (lddr *localDoguDescriptorRepository) addToOverflow(ctx context.Context, descriptorConfigMap *corev1.ConfigMap, name SimpleDoguName, versionStr string, compressed []byte) error {
+	index, err := getOverflowIndex(*descriptorConfigMap)
+	if err != nil {
+		return cloudoguerrors.NewGenericError(err)
+	}
+
+	overflowList, err := lddr.configMapClient.List(ctx, metav1.ListOptions{LabelSelector: getOverflowConfigMapsSelector(name)})
+	if err != nil {
+		return fmt.Errorf("failed to list overflow config maps for dogu %q: %w", name, handleK8sError(err))
+	}
+
+	overflowConfigMaps := make(map[string]*corev1.ConfigMap, len(overflowList.Items))
+	for i := range overflowList.Items {
+		overflowConfigMaps[overflowList.Items[i].Name] = &overflowList.Items[i]
+	}
+
+	overflowName, err := lddr.storeInOverflow(ctx, overflowConfigMaps, name, versionStr, compressed)
+	if err != nil {
+		return err
+	}
+
+	index[versionStr] = overflowName
+	err = setOverflowIndex(descriptorConfigMap, index)
+	if err != nil {
+		return cloudoguerrors.NewGenericError(err)
+	}
+
+	return nil
+}
+
+// storeInOverflow writes the compressed descriptor into an overflow config map and returns the name of the config map.
+// A descriptor that is contained in an overflow config map without being indexed was written by a previous attempt
+// whose update of the descriptor config map failed, e.g. because of a conflict. This copy is replaced, so that the
+// descriptor is not stored a second time in another overflow config map.
+func (lddr *localDoguDescriptorRepository) storeInOverflow(ctx context.Context, overflowConfigMaps map[string]*corev1.ConfigMap, name SimpleDoguName, versionStr string, compressed []byte) (string, error) {
+	for overflowName, overflowConfigMap := range overflowConfigMaps {
+		if _, ok := overflowConfigMap.BinaryData[versionStr]; ok {
+			return overflowName, lddr.updateOverflowConfigMap(ctx, overflowConfigMap, name, versionStr, compressed)
+		}
+	}
+
+	for number := 1; ; number++ {
+		overflowName := getOverflowConfigMapName(name, number)
+		overflowConfigMap, exists := overflowConfigMaps[overflowName]
+		if !exists {
+			overflowConfigMap = newOverflowConfigMap(name, overflowName)
+			overflowConfigMap.BinaryData[versionStr] = compressed
+
+			_, err := lddr.configMapClient.Create(ctx, overflowConfigMap, metav1.CreateOptions{})
+			if err != nil {
+				return "", fmt.Errorf("failed to create overflow config map %q for dogu %q: %w", overflowName, name, err)
+			}
+
+			return overflowName, nil
+		}
+
+		if getConfigMapDataSize(*overflowConfigMap)+len(versionStr)+len(compressed) > lddr.sizeLimit() {
+			continue
+		}
+
+		return overflowName, lddr.updateOverflowConfigMap(ctx, overflowConfigMap, name, versionStr, compressed)
+	}
+}
+
+func (lddr *localDoguDescriptorRepository) updateOverflowConfigMap(ctx context.Context, overflowConfigMap *corev1.ConfigMap, name SimpleDoguName, versionStr string, compressed []byte) error {
+	if overflowConfigMap.BinaryData == nil {
+		overflowConfigMap.BinaryData = map[string][]byte{}
+	}
+	overflowConfigMap.BinaryData[versionStr] = compressed
+
+	_, err := lddr.configMapClient.Update(ctx, overflowConfigMap, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update overflow config map %q for dogu %q: %w", overflowConfigMap.Name, name, err)
+	}
+
+	return nil
+}
+
+func newOverflowConfigMap(name SimpleDoguName, overflowName string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: overflowName,
+			Labels: map[string]string{
+				appLabelKey:      appLabelValueCes,
+				doguNameLabelKey: string(name),
+				typeLabelKey:     typeLabelValueLocalDoguRegistryOverflow,
+			},
+		},
+		BinaryData: map[string][]byte{},
+	}
+}
+
 func getOrCreateDescriptorConfigMapForDogu(ctx context.Context, configMapClient configMapClient, simpleDoguName SimpleDoguName) (*corev1.ConfigMap, error) {
 	descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, configMapClient, simpleDoguName)
 	if err != nil {
@@ -173,11 +351,300 @@ func getDescriptorConfigMapName(simpleDoguName SimpleDoguName) string {
 	return fmt.Sprintf("dogu-spec-%s", simpleDoguName)
 }
 
+// DeleteAll deletes all overflow config maps and the descriptor config map of the dogu.
+// The overflow config maps are deleted first, so that they are not orphaned if the descriptor config map is missing.
 func (lddr *localDoguDescriptorRepository) DeleteAll(ctx context.Context, name SimpleDoguName) error {
-	err := lddr.configMapClient.Delete(ctx, getDescriptorConfigMapName(name), metav1.DeleteOptions{})
+	err := lddr.configMapClient.DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: getOverflowConfigMapsSelector(name)})
+	if err != nil {
+		return fmt.Errorf("failed to delete overflow configmaps for dogu %q: %w", name, handleK8sError(err))
+	}
+
+	err = lddr.configMapClient.Delete(ctx, getDescriptorConfigMapName(name), metav1.DeleteOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to delete dogu descriptor configmap for dogu %q: %w", name, handleK8sError(err))
 	}
 
 	return nil
 }
+
+// Delete removes the descriptor of the given dogu version. The currently enabled version cannot be deleted.
+// NotFoundError if the version is not stored
+// ConflictError if the version is the currently enabled version
+func (lddr *localDoguDescriptorRepository) Delete(ctx context.Context, doguVersion DoguVersion) error {
+	var overflowVersions map[string][]string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, lddr.configMapClient, doguVersion.Name)
+		if err != nil {
+			return err
+		}
+
+		versionStr := doguVersion.Version.Raw
+		if !isDescriptorStored(*descriptorConfigMap, versionStr) {
+			return getDoguRegistryKeyNotFoundError(versionStr, doguVersion.Name)
+		}
+
+		if descriptorConfigMap.Data[currentVersionKey] == versionStr {
+			return cloudoguerrors.NewConflictError(fmt.Errorf("cannot delete descriptor of dogu %q with version %q because it is the current version", doguVersion.Name, versionStr))
+		}
+
+		overflowVersions, err = deleteDescriptorVersions(descriptorConfigMap, versionStr)
+		if err != nil {
+			return err
+		}
+
+		_, err = lddr.configMapClient.Update(ctx, descriptorConfigMap, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update dogu descriptor configmap for dogu %q: %w", doguVersion.Name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return lddr.deleteFromOverflow(ctx, doguVersion.Name, overflowVersions)
+}
+
+// Prune deletes old descriptors of the dogu. It keeps the keepN newest versions and the currently enabled version,
+// even if it is not one of the newest versions. The deleted versions are returned.
+// NotFoundError if no descriptors are stored for the dogu
+func (lddr *localDoguDescriptorRepository) Prune(ctx context.Context, name SimpleDoguName, keepN int) ([]core.Version, error) {
+	if keepN < 0 {
+		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("number of dogu descriptors to keep must not be negative, got %d", keepN))
+	}
+
+	var pruned []core.Version
+	var overflowVersions map[string][]string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pruned = nil
+
+		descriptorConfigMap, err := getDescriptorConfigMapForDogu(ctx, lddr.configMapClient, name)
+		if err != nil {
+			return err
+		}
+
+		versions, err := getStoredVersions(*descriptorConfigMap, name)
+		if err != nil {
+			return cloudoguerrors.NewGenericError(fmt.Errorf("failed to prune descriptors of dogu %q: %w", name, err))
+		}
+
+		currentVersion := descriptorConfigMap.Data[currentVersionKey]
+		var prunedKeys []string
+		for _, version := range versions[:max(len(versions)-keepN, 0)] {
+			if version.Raw == currentVersion {
+				continue
+			}
+
+			prunedKeys = append(prunedKeys, version.Raw)
+			pruned = append(pruned, version)
+		}
+
+		if len(pruned) == 0 {
+			return nil
+		}
+
+		overflowVersions, err = deleteDescriptorVersions(descriptorConfigMap, prunedKeys...)
+		if err != nil {
+			return err
+		}
+
+		_, err = lddr.configMapClient.Update(ctx, descriptorConfigMap, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update dogu descriptor configmap for dogu %q: %w", name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = lddr.deleteFromOverflow(ctx, name, overflowVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
+
+// deleteDescriptorVersions removes the descriptors of the versions and the reference to them as previous version.
+// Versions stored in overflow config maps are removed from the overflow index and returned by the name of their
+// overflow config map.
+func deleteDescriptorVersions(descriptorConfigMap *corev1.ConfigMap, versionStrs ...string) (map[string][]string, error) {
+	index, err := getOverflowIndex(*descriptorConfigMap)
+	if err != nil {
+		return nil, cloudoguerrors.NewGenericError(err)
+	}
+
+	overflowVersions := map[string][]string{}
+	for _, versionStr := range versionStrs {
+		delete(descriptorConfigMap.Data, versionStr)
+		delete(descriptorConfigMap.BinaryData, versionStr)
+		if descriptorConfigMap.Data[previousVersionKey] == versionStr {
+			delete(descriptorConfigMap.Data, previousVersionKey)
+		}
+
+		if overflowName, ok := index[versionStr]; ok {
+			overflowVersions[overflowName] = append(overflowVersions[overflowName], versionStr)
+			delete(index, versionStr)
+		}
+	}
+
+	err = setOverflowIndex(descriptorConfigMap, index)
+	if err != nil {
+		return nil, cloudoguerrors.NewGenericError(err)
+	}
+
+	return overflowVersions, nil
+}
+
+// deleteFromOverflow removes the descriptors from their overflow config maps. Empty overflow config maps are deleted.
+func (lddr *localDoguDescriptorRepository) deleteFromOverflow(ctx context.Context, name SimpleDoguName, overflowVersions map[string][]string) error {
+	overflowNames := make([]string, 0, len(overflowVersions))
+	for overflowName := range overflowVersions {
+		overflowNames = append(overflowNames, overflowName)
+	}
+	slices.Sort(overflowNames)
+
+	for _, overflowName := range overflowNames {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			overflowConfigMap, err := lddr.configMapClient.Get(ctx, overflowName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			for _, versionStr := range overflowVersions[overflowName] {
+				delete(overflowConfigMap.BinaryData, versionStr)
+			}
+
+			// the precondition makes the deletion fail with a conflict if a descriptor was added in the meantime
+			if len(overflowConfigMap.BinaryData) == 0 {
+				return lddr.configMapClient.Delete(ctx, overflowName, metav1.DeleteOptions{
+					Preconditions: &metav1.Preconditions{ResourceVersion: &overflowConfigMap.ResourceVersion},
+				})
+			}
+
+			_, err = lddr.configMapClient.Update(ctx, overflowConfigMap, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil && !cloudoguerrors.IsNotFoundError(handleK8sError(err)) {
+			return fmt.Errorf("failed to delete descriptors from overflow config map %q for dogu %q: %w", overflowName, name, handleK8sError(err))
+		}
+	}
+
+	return nil
+}
+
+// WatchDescriptors watches the descriptors of all dogus. A result is sent whenever descriptors of a dogu are added or
+// deleted, including the deletion of all descriptors with DeleteAll.
+func (lddr *localDoguDescriptorRepository) WatchDescriptors(ctx context.Context) (<-chan DescriptorWatchResult, error) {
+	list, err := getAllDescriptorConfigMaps(ctx, lddr.configMapClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list initial descriptor configmaps: %w", err)
+	}
+
+	storedVersions := make(map[SimpleDoguName][]string, len(list.Items))
+	for _, descriptorConfigMap := range list.Items {
+		keys, keysErr := getStoredVersionKeys(descriptorConfigMap)
+		if keysErr != nil {
+			return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to get initial dogu descriptor versions: %w", keysErr))
+		}
+
+		storedVersions[SimpleDoguName(descriptorConfigMap.Labels[doguNameLabelKey])] = keys
+	}
+
+	retryWatcher, err := createRetryWatcher(ctx, lddr.configMapClient, list.ResourceVersion, getAllLocalDoguRegistriesSelector())
+	if err != nil {
+		return nil, cloudoguerrors.NewGenericError(fmt.Errorf("failed to create watch for dogu descriptors: %w", err))
+	}
+
+	return startDescriptorWatchInBackground(ctx, retryWatcher, storedVersions), nil
+}
+
+func startDescriptorWatchInBackground(ctx context.Context, watchInterface watch.Interface, storedVersions map[SimpleDoguName][]string) <-chan DescriptorWatchResult {
+	logger := log.FromContext(ctx).WithName("LocalDoguDescriptorRepository.startDescriptorWatchInBackground")
+	descriptorWatchResult := make(chan DescriptorWatchResult)
+
+	go func() {
+		defer close(descriptorWatchResult)
+		for {
+			select {
+			case <-ctx.Done():
+				watchInterface.Stop()
+				logger.Info("context canceled. Stop watch channel.")
+				return
+			case event, open := <-watchInterface.ResultChan():
+				if !open {
+					logger.Info("watch channel canceled. Stop watch.")
+					return
+				}
+
+				handleDescriptorWatchEvent(ctx, event, storedVersions, descriptorWatchResult)
+			}
+		}
+	}()
+
+	return descriptorWatchResult
+}
+
+// handleDescriptorWatchEvent compares the stored versions of the dogu with the versions before the event and sends a
+// result if versions were added or deleted.
+func handleDescriptorWatchEvent(ctx context.Context, event watch.Event, storedVersions map[SimpleDoguName][]string, descriptorWatchResult chan DescriptorWatchResult) {
+	if event.Type == watch.Error {
+		descriptorWatchResult <- DescriptorWatchResult{Err: logWatchError(ctx, getErrorFromWatchEvent(event))}
+		return
+	}
+
+	descriptorConfigMap, err := getDescriptorConfigMapFromEvent(event)
+	if err != nil {
+		descriptorWatchResult <- DescriptorWatchResult{Err: logWatchError(ctx, err)}
+		return
+	}
+
+	name := SimpleDoguName(descriptorConfigMap.Labels[doguNameLabelKey])
+	var keys []string
+	if event.Type != watch.Deleted {
+		keys, err = getStoredVersionKeys(*descriptorConfigMap)
+		if err != nil {
+			descriptorWatchResult <- DescriptorWatchResult{Name: name, Err: logWatchError(ctx, err)}
+			return
+		}
+	}
+
+	prevKeys := storedVersions[name]
+	if len(keys) == 0 {
+		delete(storedVersions, name)
+	} else {
+		storedVersions[name] = keys
+	}
+
+	result, err := newDescriptorWatchResult(name, prevKeys, keys)
+	if err != nil {
+		result.Err = logWatchError(ctx, err)
+	}
+
+	if len(result.Added) > 0 || len(result.Deleted) > 0 || result.Err != nil {
+		descriptorWatchResult <- result
+	}
+}
+
+func newDescriptorWatchResult(name SimpleDoguName, prevKeys []string, keys []string) (DescriptorWatchResult, error) {
+	var addedKeys, deletedKeys []string
+	for _, key := range keys {
+		if !slices.Contains(prevKeys, key) {
+			addedKeys = append(addedKeys, key)
+		}
+	}
+
+	for _, key := range prevKeys {
+		if !slices.Contains(keys, key) {
+			deletedKeys = append(deletedKeys, key)
+		}
+	}
+
+	added, addedErr := parseSortedVersions(addedKeys, name)
+	deleted, deletedErr := parseSortedVersions(deletedKeys, name)
+
+	return DescriptorWatchResult{Name: name, Added: added, Deleted: deleted}, errors.Join(addedErr, deletedErr)
+}
@@ -11,7 +11,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"testing"
 )
 
@@ -37,7 +41,7 @@ func TestNewLocalDoguDescriptorRepository(t *testing.T) {
 
 func Test_localDoguDescriptorRepository_Add(t *testing.T) {
 	casDogu := readCasDogu(t)
-	expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{casVersionStr: readCasDoguStr(t)}}
+	expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{}, BinaryData: map[string][]byte{casVersionStr: compressDoguStr(t, readCasDoguStr(t))}}
 
 	type args struct {
 		ctx  context.Context
@@ -77,7 +81,8 @@ func Test_localDoguDescriptorRepository_Add(t *testing.T) {
 				}
 
 				expectedUpdateCm := cmToCreate.DeepCopy()
-				expectedUpdateCm.Data = map[string]string{casVersionStr: readCasDoguStr(t)}
+				expectedUpdateCm.Data = map[string]string{}
+				expectedUpdateCm.BinaryData = map[string][]byte{casVersionStr: compressDoguStr(t, readCasDoguStr(t))}
 
 				configMapClientMock := newMockConfigMapClient(t)
 				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, ""))
@@ -172,7 +177,8 @@ func Test_localDoguDescriptorRepository_DeleteAll(t *testing.T) {
 			name: "success",
 			configMapClientFn: func(t *testing.T) configMapClient {
 				configMapClientMock := newMockConfigMapClient(t)
-				configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas", metav1.DeleteOptions{}).Return(nil)
+				deleteOverflowCall := configMapClientMock.EXPECT().DeleteCollection(testCtx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: "app=ces,dogu.name=cas,k8s.cloudogu.com/type=local-dogu-registry-overflow"}).Return(nil).Call
+				configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas", metav1.DeleteOptions{}).Return(nil).NotBefore(deleteOverflowCall)
 
 				return configMapClientMock
 			},
@@ -182,10 +188,46 @@ func Test_localDoguDescriptorRepository_DeleteAll(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "should return error on error deleting overflow config maps",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().DeleteCollection(testCtx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: "app=ces,dogu.name=cas,k8s.cloudogu.com/type=local-dogu-registry-overflow"}).Return(assert.AnError)
+
+				return configMapClientMock
+			},
+			args: args{
+				ctx:  testCtx,
+				name: "cas",
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, errors.IsGenericError(err)) &&
+					assert.ErrorContains(t, err, "failed to delete overflow configmaps for dogu \"cas\"")
+			},
+		},
+		{
+			name: "should delete overflow config maps if descriptor config map does not exist",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().DeleteCollection(testCtx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: "app=ces,dogu.name=cas,k8s.cloudogu.com/type=local-dogu-registry-overflow"}).Return(nil)
+				configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas", metav1.DeleteOptions{}).Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "dogu-spec-cas"))
+
+				return configMapClientMock
+			},
+			args: args{
+				ctx:  testCtx,
+				name: "cas",
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, errors.IsNotFoundError(err)) &&
+					assert.ErrorContains(t, err, "failed to delete dogu descriptor configmap for dogu \"cas\"")
+			},
+		},
 		{
 			name: "should return error on delete error",
 			configMapClientFn: func(t *testing.T) configMapClient {
 				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().DeleteCollection(testCtx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: "app=ces,dogu.name=cas,k8s.cloudogu.com/type=local-dogu-registry-overflow"}).Return(nil)
 				configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas", metav1.DeleteOptions{}).Return(assert.AnError)
 
 				return configMapClientMock
@@ -210,6 +252,187 @@ func Test_localDoguDescriptorRepository_DeleteAll(t *testing.T) {
 	}
 }
 
+func Test_localDoguDescriptorRepository_Delete(t *testing.T) {
+	oldCasVersion := DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.4-1")}
+
+	tests := []struct {
+		name              string
+		configMapClientFn func(t *testing.T) configMapClient
+		wantErr           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "should delete descriptor and previous version reference",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				casRegistryCm := &corev1.ConfigMap{Data: map[string]string{"7.0.4-1": "{}", casVersionStr: "{}", "current": casVersionStr, "previous": "7.0.4-1"}}
+				expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}", "current": casVersionStr}}
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+				configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+
+				return configMapClientMock
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should retry on conflict",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{"7.0.4-1": "{}"}}, nil).Times(1)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{"7.0.4-1": "{}"}}, nil).Times(1)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{}}, metav1.UpdateOptions{}).Return(nil, apierrors.NewConflict(schema.GroupResource{}, "dogu-spec-cas", assert.AnError)).Times(1)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{}}, metav1.UpdateOptions{}).Return(&corev1.ConfigMap{}, nil).Times(1)
+
+				return configMapClientMock
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "should refuse to delete current version",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{"7.0.4-1": "{}", "current": "7.0.4-1"}}, nil)
+
+				return configMapClientMock
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, errors.IsConflictError(err)) &&
+					assert.ErrorContains(t, err, "cannot delete descriptor of dogu \"cas\" with version \"7.0.4-1\" because it is the current version")
+			},
+		},
+		{
+			name: "should return not found error for missing version",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{casVersionStr: "{}"}}, nil)
+
+				return configMapClientMock
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.True(t, errors.IsNotFoundError(err))
+			},
+		},
+		{
+			name: "should return error on update error",
+			configMapClientFn: func(t *testing.T) configMapClient {
+				configMapClientMock := newMockConfigMapClient(t)
+				configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{"7.0.4-1": "{}"}}, nil)
+				configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{}}, metav1.UpdateOptions{}).Return(nil, assert.AnError)
+
+				return configMapClientMock
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, assert.AnError) &&
+					assert.ErrorContains(t, err, "failed to update dogu descriptor configmap for dogu \"cas\"")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &localDoguDescriptorRepository{
+				configMapClient: tt.configMapClientFn(t),
+			}
+			tt.wantErr(t, vr.Delete(testCtx, oldCasVersion), fmt.Sprintf("Delete(%v, %v)", testCtx, oldCasVersion))
+		})
+	}
+}
+
+func Test_localDoguDescriptorRepository_Prune(t *testing.T) {
+	t.Run("should keep newest versions and current version", func(t *testing.T) {
+		// given
+		casRegistryCm := &corev1.ConfigMap{Data: map[string]string{
+			"7.0.1-1": "{}", "7.0.2-1": "{}", "7.0.3-1": "{}", "7.0.10-1": "{}", "7.0.9-1": "{}", "current": "7.0.2-1", "previous": "7.0.1-1",
+		}}
+		expectedCasRegistryCm := &corev1.ConfigMap{Data: map[string]string{
+			"7.0.2-1": "{}", "7.0.10-1": "{}", "7.0.9-1": "{}", "current": "7.0.2-1",
+		}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(casRegistryCm, nil)
+		configMapClientMock.EXPECT().Update(testCtx, expectedCasRegistryCm, metav1.UpdateOptions{}).Return(expectedCasRegistryCm, nil)
+		sut := &localDoguDescriptorRepository{configMapClient: configMapClientMock}
+
+		// when
+		pruned, err := sut.Prune(testCtx, "cas", 2)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []core.Version{parseVersionStr(t, "7.0.1-1"), parseVersionStr(t, "7.0.3-1")}, pruned)
+	})
+
+	t.Run("should not update if nothing is pruned", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{
+			"7.0.1-1": "{}", "7.0.2-1": "{}", "current": "7.0.1-1",
+		}}, nil)
+		sut := &localDoguDescriptorRepository{configMapClient: configMapClientMock}
+
+		// when
+		pruned, err := sut.Prune(testCtx, "cas", 1)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, pruned)
+	})
+
+	t.Run("should keep only current version", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{
+			"7.0.1-1": "{}", "7.0.2-1": "{}", "current": "7.0.1-1",
+		}}, nil)
+		configMapClientMock.EXPECT().Update(testCtx, &corev1.ConfigMap{Data: map[string]string{"7.0.1-1": "{}", "current": "7.0.1-1"}}, metav1.UpdateOptions{}).Return(&corev1.ConfigMap{}, nil)
+		sut := &localDoguDescriptorRepository{configMapClient: configMapClientMock}
+
+		// when
+		pruned, err := sut.Prune(testCtx, "cas", 0)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []core.Version{parseVersionStr(t, "7.0.2-1")}, pruned)
+	})
+
+	t.Run("should fail for negative number of versions to keep", func(t *testing.T) {
+		sut := &localDoguDescriptorRepository{configMapClient: newMockConfigMapClient(t)}
+
+		_, err := sut.Prune(testCtx, "cas", -1)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "number of dogu descriptors to keep must not be negative, got -1")
+	})
+
+	t.Run("should fail without pruning on invalid version", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{Data: map[string]string{
+			"7.0.1-1": "{}", "invalid": "{}",
+		}}, nil)
+		sut := &localDoguDescriptorRepository{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.Prune(testCtx, "cas", 0)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to prune descriptors of dogu \"cas\"")
+	})
+
+	t.Run("should fail on error getting registry", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(nil, apierrors.NewNotFound(schema.GroupResource{}, "dogu-spec-cas"))
+		sut := &localDoguDescriptorRepository{configMapClient: configMapClientMock}
+
+		// when
+		_, err := sut.Prune(testCtx, "cas", 1)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsNotFoundError(err))
+	})
+}
+
 func Test_localDoguDescriptorRepository_Get(t *testing.T) {
 	casVersion := parseVersionStr(t, casVersionStr)
 	doguVersion := DoguVersion{
@@ -417,3 +640,373 @@ func Test_localDoguDescriptorRepository_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func Test_localDoguDescriptorRepository_Overflow(t *testing.T) {
+	newCasDogu := func(t *testing.T, version string) *core.Dogu {
+		dogu := readCasDogu(t)
+		dogu.Version = version
+
+		return dogu
+	}
+	casVersions := []string{"7.0.4-1", "7.0.5-1", "7.0.6-1"}
+
+	// every config map can hold a single descriptor only
+	maxConfigMapSize := len(compressDoguStr(t, readCasDoguStr(t))) + 100
+
+	t.Run("should store descriptors in overflow config maps", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient()
+		sut := &localDoguDescriptorRepository{configMapClient: client, maxConfigMapSize: maxConfigMapSize}
+
+		// when
+		for _, version := range casVersions {
+			require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, version)))
+		}
+
+		// then
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, cm.BinaryData, "7.0.4-1")
+		assert.Equal(t, `{"7.0.5-1":"dogu-spec-cas-overflow-1","7.0.6-1":"dogu-spec-cas-overflow-2"}`, cm.Data[overflowIndexKey])
+
+		overflowCm, err := client.Get(testCtx, "dogu-spec-cas-overflow-2", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, typeLabelValueLocalDoguRegistryOverflow, overflowCm.Labels[typeLabelKey])
+		assert.Equal(t, "cas", overflowCm.Labels[doguNameLabelKey])
+
+		for _, version := range casVersions {
+			dogu, getErr := sut.Get(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, version)})
+			require.NoError(t, getErr)
+			assert.Equal(t, newCasDogu(t, version), dogu)
+		}
+
+		doguVersions := []DoguVersion{
+			{Name: "cas", Version: parseVersionStr(t, "7.0.5-1")},
+			{Name: "cas", Version: parseVersionStr(t, "7.0.6-1")},
+		}
+		dogus, err := sut.GetAll(testCtx, doguVersions)
+		require.NoError(t, err)
+		assert.Len(t, dogus, 2)
+
+		versionRegistry := NewDoguVersionRegistry(client)
+		versions, err := versionRegistry.GetAllVersions(testCtx, "cas")
+		require.NoError(t, err)
+		assert.Len(t, versions, 3)
+		require.NoError(t, versionRegistry.Enable(testCtx, doguVersions[1]))
+
+		err = sut.Add(testCtx, "cas", newCasDogu(t, "7.0.6-1"))
+		assert.True(t, errors.IsAlreadyExistsError(err))
+	})
+
+	t.Run("should delete descriptors from overflow config maps", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient()
+		sut := &localDoguDescriptorRepository{configMapClient: client, maxConfigMapSize: maxConfigMapSize}
+		for _, version := range casVersions {
+			require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, version)))
+		}
+
+		// when
+		err := sut.Delete(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.5-1")})
+
+		// then
+		require.NoError(t, err)
+		_, err = client.Get(testCtx, "dogu-spec-cas-overflow-1", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		// when
+		pruned, err := sut.Prune(testCtx, "cas", 0)
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, pruned, 2)
+		_, err = client.Get(testCtx, "dogu-spec-cas-overflow-2", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, cm.BinaryData)
+		assert.NotContains(t, cm.Data, overflowIndexKey)
+
+		// when
+		require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, "7.0.7-1")))
+		require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, "7.0.8-1")))
+		err = sut.DeleteAll(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		_, err = client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("should not store descriptor twice on retry after conflict", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		client := clientSet.CoreV1().ConfigMaps(testNamespace)
+		sut := &localDoguDescriptorRepository{configMapClient: client, maxConfigMapSize: maxConfigMapSize}
+		require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, "7.0.4-1")))
+		require.NoError(t, sut.Add(testCtx, "cas", newCasDogu(t, "7.0.5-1")))
+
+		conflicted := false
+		clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			configMap := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+			if configMap.Name != "dogu-spec-cas" || conflicted {
+				return false, nil, nil
+			}
+
+			conflicted = true
+			return true, nil, testConflictErr
+		})
+
+		// when
+		err := sut.Add(testCtx, "cas", newCasDogu(t, "7.0.6-1"))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, conflicted)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, `{"7.0.5-1":"dogu-spec-cas-overflow-1","7.0.6-1":"dogu-spec-cas-overflow-2"}`, cm.Data[overflowIndexKey])
+		_, err = client.Get(testCtx, "dogu-spec-cas-overflow-3", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		dogu, err := sut.Get(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.6-1")})
+		require.NoError(t, err)
+		assert.Equal(t, newCasDogu(t, "7.0.6-1"), dogu)
+	})
+
+	t.Run("should keep overflow config map if a descriptor was added concurrently", func(t *testing.T) {
+		// given
+		newOverflowCm := func(resourceVersion string, versions ...string) *corev1.ConfigMap {
+			cm := newOverflowConfigMap("cas", "dogu-spec-cas-overflow-1")
+			cm.ResourceVersion = resourceVersion
+			for _, version := range versions {
+				cm.BinaryData[version] = []byte(version)
+			}
+
+			return cm
+		}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas-overflow-1", metav1.GetOptions{}).Return(newOverflowCm("1", "7.0.5-1"), nil).Once()
+		staleResourceVersion := "1"
+		configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas-overflow-1", metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &staleResourceVersion},
+		}).Return(testConflictErr)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas-overflow-1", metav1.GetOptions{}).Return(newOverflowCm("2", "7.0.5-1", "7.0.6-1"), nil).Once()
+		configMapClientMock.EXPECT().Update(testCtx, newOverflowCm("2", "7.0.6-1"), metav1.UpdateOptions{}).Return(nil, nil)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		err := sut.deleteFromOverflow(testCtx, "cas", map[string][]string{"dogu-spec-cas-overflow-1": {"7.0.5-1"}})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep uncompressed descriptors of older versions on add", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{
+			"7.0.4-1": readCasDoguStr(t), currentVersionKey: "7.0.4-1",
+		}))
+		sut := NewLocalDoguDescriptorRepository(client)
+
+		// when
+		err := sut.Add(testCtx, "cas", newCasDogu(t, "7.0.5-1"))
+
+		// then
+		require.NoError(t, err)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"7.0.4-1": readCasDoguStr(t), currentVersionKey: "7.0.4-1"}, cm.Data)
+		assert.NotContains(t, cm.BinaryData, "7.0.4-1")
+		assert.Contains(t, cm.BinaryData, "7.0.5-1")
+
+		dogu, err := sut.Get(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.4-1")})
+		require.NoError(t, err)
+		assert.Equal(t, readCasDogu(t), dogu)
+	})
+
+	t.Run("should fail to read invalid compressed descriptor", func(t *testing.T) {
+		// given
+		cm := newDescriptorConfigMap("cas", map[string]string{})
+		cm.BinaryData = map[string][]byte{casVersionStr: []byte("invalid")}
+		sut := NewLocalDoguDescriptorRepository(newFakeConfigMapClient(cm))
+
+		// when
+		_, err := sut.Get(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, casVersionStr)})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to read descriptor for dogu \"cas\" with version \"7.0.5.1-1\"")
+	})
+
+	t.Run("should fail to read descriptor from missing overflow config map", func(t *testing.T) {
+		// given
+		sut := NewLocalDoguDescriptorRepository(newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{
+			overflowIndexKey: `{"7.0.5.1-1":"dogu-spec-cas-overflow-1"}`,
+		})))
+
+		// when
+		_, err := sut.Get(testCtx, DoguVersion{Name: "cas", Version: parseVersionStr(t, casVersionStr)})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "failed to get overflow config map \"dogu-spec-cas-overflow-1\" for dogu \"cas\"")
+	})
+}
+
+func Test_localDoguDescriptorRepository_WatchDescriptors(t *testing.T) {
+	newCasRegistryCm := func(data map[string]string, resourceVersion string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{Data: data, ObjectMeta: metav1.ObjectMeta{Name: "dogu-spec-cas", Labels: casVersionRegistryLabelMap, ResourceVersion: resourceVersion}}
+	}
+	newLdapRegistryCm := func(data map[string]string, resourceVersion string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{Data: data, ObjectMeta: metav1.ObjectMeta{Name: "dogu-spec-ldap", Labels: ldapVersionRegistryLabelMap, ResourceVersion: resourceVersion}}
+	}
+
+	t.Run("should send added and deleted descriptors", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		watcher := watch.NewFake()
+		casCm := newCasRegistryCm(map[string]string{"current": casVersionStr}, "1")
+		casCm.BinaryData = map[string][]byte{casVersionStr: compressDoguStr(t, readCasDoguStr(t))}
+		registryCmList := &corev1.ConfigMapList{Items: []corev1.ConfigMap{*casCm}, ListMeta: metav1.ListMeta{ResourceVersion: "1"}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(registryCmList, nil)
+		configMapClientMock.EXPECT().Watch(ctx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector, ResourceVersion: "1", AllowWatchBookmarks: true}).Return(watcher, nil)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		watchCh, err := sut.WatchDescriptors(ctx)
+
+		// then
+		require.NoError(t, err)
+		go func() {
+			// an empty descriptor config map and a new current version do not change the descriptors
+			watcher.Add(newLdapRegistryCm(map[string]string{}, "2"))
+			watcher.Modify(newCasRegistryCm(map[string]string{"current": casVersionStr, casVersionStr: "{}"}, "3"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{ldapVersionStr: "{}"}, "4"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{
+				ldapVersionStr: "{}", overflowIndexKey: `{"2.6.8-3":"dogu-spec-ldap-overflow-1","2.6.10-1":"dogu-spec-ldap-overflow-1"}`,
+			}, "5"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{upgradeLdapVersionStr: "{}"}, "6"))
+			watcher.Delete(newCasRegistryCm(map[string]string{}, "7"))
+		}()
+
+		result := <-watchCh
+		require.NoError(t, result.Err)
+		assert.Equal(t, DescriptorWatchResult{Name: "ldap", Added: []core.Version{parseVersionStr(t, ldapVersionStr)}, Deleted: []core.Version{}}, result)
+		result = <-watchCh
+		assert.Equal(t, DescriptorWatchResult{Name: "ldap", Added: []core.Version{parseVersionStr(t, upgradeLdapVersionStr), parseVersionStr(t, "2.6.10-1")}, Deleted: []core.Version{}}, result)
+		result = <-watchCh
+		assert.Equal(t, DescriptorWatchResult{Name: "ldap", Added: []core.Version{}, Deleted: []core.Version{parseVersionStr(t, ldapVersionStr), parseVersionStr(t, "2.6.10-1")}}, result)
+		result = <-watchCh
+		assert.Equal(t, DescriptorWatchResult{Name: "cas", Added: []core.Version{}, Deleted: []core.Version{parseVersionStr(t, casVersionStr)}}, result)
+
+		cancel()
+		_, open := <-watchCh
+		assert.False(t, open)
+	})
+
+	t.Run("should send error on invalid versions", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+		watcher := watch.NewFake()
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(&corev1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil)
+		configMapClientMock.EXPECT().Watch(ctx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector, ResourceVersion: "1", AllowWatchBookmarks: true}).Return(watcher, nil)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		watchCh, err := sut.WatchDescriptors(ctx)
+
+		// then
+		require.NoError(t, err)
+		go func() {
+			watcher.Add(newLdapRegistryCm(map[string]string{"abc": "{}", ldapVersionStr: "{}"}, "2"))
+			watcher.Modify(newLdapRegistryCm(map[string]string{overflowIndexKey: "invalid"}, "3"))
+		}()
+
+		result := <-watchCh
+		assert.True(t, errors.IsGenericError(result.Err))
+		assert.ErrorContains(t, result.Err, "failed to parse version \"abc\" for dogu \"ldap\"")
+		assert.Equal(t, []core.Version{parseVersionStr(t, ldapVersionStr)}, result.Added)
+		result = <-watchCh
+		assert.ErrorContains(t, result.Err, "failed to parse overflow index")
+	})
+
+	t.Run("should fail to list initial descriptor config maps", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(nil, assert.AnError)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		_, err := sut.WatchDescriptors(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to list initial descriptor configmaps")
+	})
+
+	t.Run("should fail on invalid initial overflow index", func(t *testing.T) {
+		// given
+		registryCmList := &corev1.ConfigMapList{Items: []corev1.ConfigMap{*newCasRegistryCm(map[string]string{overflowIndexKey: "invalid"}, "1")}}
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(registryCmList, nil)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		_, err := sut.WatchDescriptors(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get initial dogu descriptor versions")
+	})
+
+	t.Run("should fail to create watch without resource version", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(&corev1.ConfigMapList{}, nil)
+		sut := NewLocalDoguDescriptorRepository(configMapClientMock)
+
+		// when
+		_, err := sut.WatchDescriptors(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to create watch for dogu descriptors")
+	})
+}
+
+func Test_handleDescriptorWatchEvent(t *testing.T) {
+	t.Run("should send error from event", func(t *testing.T) {
+		// given
+		resultChannel := make(chan DescriptorWatchResult)
+
+		// when
+		go handleDescriptorWatchEvent(testCtx, watch.Event{Type: watch.Error, Object: &metav1.Status{}}, map[SimpleDoguName][]string{}, resultChannel)
+
+		// then
+		result := <-resultChannel
+		assert.True(t, errors.IsGenericError(result.Err))
+		assert.ErrorContains(t, result.Err, "watch event type is error")
+	})
+
+	t.Run("should send error on wrong object type", func(t *testing.T) {
+		// given
+		resultChannel := make(chan DescriptorWatchResult)
+
+		// when
+		go handleDescriptorWatchEvent(testCtx, watch.Event{Type: watch.Modified, Object: &corev1.Secret{}}, map[SimpleDoguName][]string{}, resultChannel)
+
+		// then
+		result := <-resultChannel
+		assert.ErrorContains(t, result.Err, "failed to cast event object")
+	})
+}
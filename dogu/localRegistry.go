@@ -0,0 +1,131 @@
+package dogu
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+// localRegistry implements the LocalRegistry by combining a DoguVersionRegistry for the current versions and a
+// LocalDoguDescriptorRepository for the dogu specs.
+type localRegistry struct {
+	versionRegistry      DoguVersionRegistry
+	descriptorRepository LocalDoguDescriptorRepository
+}
+
+var _ LocalRegistry = &localRegistry{}
+
+// NewLocalRegistry creates a LocalRegistry which stores the dogu specs and their current versions in config maps.
+func NewLocalRegistry(configMapClient configMapClient) *localRegistry {
+	return &localRegistry{
+		versionRegistry:      NewDoguVersionRegistry(configMapClient),
+		descriptorRepository: NewLocalDoguDescriptorRepository(configMapClient),
+	}
+}
+
+// Enable makes the dogu spec reachable by setting it as current version.
+// The spec has to be registered before.
+func (lr *localRegistry) Enable(ctx context.Context, dogu *core.Dogu) error {
+	return lr.EnableWithOptions(ctx, dogu)
+}
+
+// EnableWithOptions works like Enable, but the given options like WithPreflightCheck are applied.
+// *PreflightError if the pre-flight check of WithPreflightCheck finds violations
+func (lr *localRegistry) EnableWithOptions(ctx context.Context, dogu *core.Dogu, opts ...EnableOption) error {
+	doguVersion, err := toDoguVersion(dogu)
+	if err != nil {
+		return err
+	}
+
+	err = lr.versionRegistry.EnableWithOptions(ctx, doguVersion, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to enable dogu %q: %w", doguVersion.Name, err)
+	}
+
+	return nil
+}
+
+// Register adds the given dogu spec to the local registry.
+// Registering an already existing version of the spec does nothing.
+func (lr *localRegistry) Register(ctx context.Context, dogu *core.Dogu) error {
+	name := SimpleDoguName(dogu.GetSimpleName())
+	err := lr.descriptorRepository.Add(ctx, name, dogu)
+	if err != nil && !cloudoguerrors.IsAlreadyExistsError(err) {
+		return fmt.Errorf("failed to register dogu %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// UnregisterAllVersions deletes all versions of the dogu spec from the local registry.
+// This also removes the current version, so the spec becomes unreachable.
+func (lr *localRegistry) UnregisterAllVersions(ctx context.Context, simpleDoguName string) error {
+	err := lr.descriptorRepository.DeleteAll(ctx, SimpleDoguName(simpleDoguName))
+	if err != nil && !cloudoguerrors.IsNotFoundError(err) {
+		return fmt.Errorf("failed to unregister all versions of dogu %q: %w", simpleDoguName, err)
+	}
+
+	return nil
+}
+
+// GetCurrent retrieves the spec of the referenced dogu's currently installed version.
+func (lr *localRegistry) GetCurrent(ctx context.Context, simpleDoguName string) (*core.Dogu, error) {
+	current, err := lr.versionRegistry.GetCurrent(ctx, SimpleDoguName(simpleDoguName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version of dogu %q: %w", simpleDoguName, err)
+	}
+
+	dogu, err := lr.descriptorRepository.Get(ctx, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current spec of dogu %q: %w", simpleDoguName, err)
+	}
+
+	return dogu, nil
+}
+
+// GetCurrentOfAll retrieves the specs of all dogus' currently installed versions.
+func (lr *localRegistry) GetCurrentOfAll(ctx context.Context) ([]*core.Dogu, error) {
+	currentVersions, err := lr.versionRegistry.GetCurrentOfAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current versions of all dogus: %w", err)
+	}
+
+	dogusByVersion, err := lr.descriptorRepository.GetAll(ctx, currentVersions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current specs of all dogus: %w", err)
+	}
+
+	dogus := make([]*core.Dogu, 0, len(currentVersions))
+	for _, doguVersion := range currentVersions {
+		dogus = append(dogus, dogusByVersion[doguVersion])
+	}
+
+	return dogus, nil
+}
+
+// IsEnabled checks if the current spec of the referenced dogu is reachable.
+// A dogu without a local registry or without a current version is not enabled.
+func (lr *localRegistry) IsEnabled(ctx context.Context, simpleDoguName string) (bool, error) {
+	_, err := lr.versionRegistry.GetCurrent(ctx, SimpleDoguName(simpleDoguName))
+	if err != nil {
+		if cloudoguerrors.IsNotFoundError(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to check if dogu %q is enabled: %w", simpleDoguName, err)
+	}
+
+	return true, nil
+}
+
+func toDoguVersion(dogu *core.Dogu) (DoguVersion, error) {
+	name := SimpleDoguName(dogu.GetSimpleName())
+	version, err := parseDoguVersion(dogu.Version, name)
+	if err != nil {
+		return DoguVersion{}, cloudoguerrors.NewGenericError(err)
+	}
+
+	return DoguVersion{Name: name, Version: version}, nil
+}
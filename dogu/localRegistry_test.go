@@ -0,0 +1,367 @@
+package dogu
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	cloudoguerrors "github.com/cloudogu/k8s-registry-lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testNamespace = "ecosystem"
+
+func newFakeConfigMapClient(objects ...*corev1.ConfigMap) configMapClient {
+	clientset := fake.NewSimpleClientset()
+	for _, object := range objects {
+		_ = clientset.Tracker().Add(object)
+	}
+
+	return clientset.CoreV1().ConfigMaps(testNamespace)
+}
+
+func newDescriptorConfigMap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getDescriptorConfigMapName(SimpleDoguName(name)),
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				appLabelKey:      appLabelValueCes,
+				doguNameLabelKey: name,
+				typeLabelKey:     typeLabelValueLocalDoguRegistry,
+			},
+		},
+		Data: data,
+	}
+}
+
+func TestNewLocalRegistry(t *testing.T) {
+	// given
+	configMapClientMock := newMockConfigMapClient(t)
+
+	// when
+	sut := NewLocalRegistry(configMapClientMock)
+
+	// then
+	require.NotNil(t, sut)
+	assert.NotNil(t, sut.versionRegistry)
+	assert.NotNil(t, sut.descriptorRepository)
+}
+
+func Test_localRegistry_Register(t *testing.T) {
+	t.Run("should register dogu spec in new config map", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient()
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.Register(testCtx, readCasDogu(t))
+
+		// then
+		require.NoError(t, err)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, readCasDoguStr(t), decompressDoguStr(t, cm.BinaryData[casVersionStr]))
+		assert.Equal(t, "cas", cm.Labels[doguNameLabelKey])
+		assert.NotContains(t, cm.Data, currentVersionKey)
+	})
+
+	t.Run("should do nothing if the version is already registered", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: "existing"}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.Register(testCtx, readCasDogu(t))
+
+		// then
+		require.NoError(t, err)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "existing", cm.Data[casVersionStr])
+	})
+
+	t.Run("should return error on update error", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(&corev1.ConfigMap{}, nil)
+		expectedCm := &corev1.ConfigMap{Data: map[string]string{}, BinaryData: map[string][]byte{casVersionStr: compressDoguStr(t, readCasDoguStr(t))}}
+		configMapClientMock.EXPECT().Update(testCtx, expectedCm, metav1.UpdateOptions{}).Return(nil, assert.AnError)
+		sut := NewLocalRegistry(configMapClientMock)
+
+		// when
+		err := sut.Register(testCtx, readCasDogu(t))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to register dogu \"cas\"")
+	})
+}
+
+func Test_localRegistry_EnableWithOptions(t *testing.T) {
+	t.Run("should apply pre-flight check", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t)}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.EnableWithOptions(testCtx, readCasDogu(t), WithPreflightCheck())
+
+		// then
+		require.Error(t, err)
+		var preflightErr *PreflightError
+		assert.ErrorAs(t, err, &preflightErr)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotContains(t, cm.Data, currentVersionKey)
+	})
+}
+
+func Test_localRegistry_Enable(t *testing.T) {
+	t.Run("should enable registered dogu spec", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t)}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.Enable(testCtx, readCasDogu(t))
+
+		// then
+		require.NoError(t, err)
+		cm, err := client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, casVersionStr, cm.Data[currentVersionKey])
+	})
+
+	t.Run("should return error if the spec is not registered", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.Enable(testCtx, readCasDogu(t))
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to enable dogu \"cas\"")
+	})
+
+	t.Run("should return error if the local registry does not exist", func(t *testing.T) {
+		// given
+		sut := NewLocalRegistry(newFakeConfigMapClient())
+
+		// when
+		err := sut.Enable(testCtx, readCasDogu(t))
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get dogu descriptor config map for dogu \"cas\"")
+	})
+
+	t.Run("should return error on invalid dogu version", func(t *testing.T) {
+		// given
+		sut := NewLocalRegistry(newFakeConfigMapClient())
+
+		// when
+		err := sut.Enable(testCtx, &core.Dogu{Name: "official/cas", Version: "abc"})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to parse version \"abc\" for dogu \"cas\"")
+	})
+}
+
+func Test_localRegistry_UnregisterAllVersions(t *testing.T) {
+	t.Run("should delete local registry of dogu", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t), currentVersionKey: casVersionStr}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		err := sut.UnregisterAllVersions(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		_, err = client.Get(testCtx, "dogu-spec-cas", metav1.GetOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should do nothing if the local registry does not exist", func(t *testing.T) {
+		// given
+		sut := NewLocalRegistry(newFakeConfigMapClient())
+
+		// when
+		err := sut.UnregisterAllVersions(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return error on delete error", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().DeleteCollection(testCtx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: "app=ces,dogu.name=cas,k8s.cloudogu.com/type=local-dogu-registry-overflow"}).Return(nil)
+		configMapClientMock.EXPECT().Delete(testCtx, "dogu-spec-cas", metav1.DeleteOptions{}).Return(assert.AnError)
+		sut := NewLocalRegistry(configMapClientMock)
+
+		// when
+		err := sut.UnregisterAllVersions(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to unregister all versions of dogu \"cas\"")
+	})
+}
+
+func Test_localRegistry_GetCurrent(t *testing.T) {
+	t.Run("should return spec of current version", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t), currentVersionKey: casVersionStr}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		dogu, err := sut.GetCurrent(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, readCasDogu(t), dogu)
+	})
+
+	t.Run("should return not found error if the dogu is not enabled", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t)}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		_, err := sut.GetCurrent(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "failed to get current version of dogu \"cas\"")
+	})
+
+	t.Run("should return not found error if the current spec is missing", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{currentVersionKey: casVersionStr}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		_, err := sut.GetCurrent(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "failed to get current spec of dogu \"cas\"")
+	})
+}
+
+func Test_localRegistry_GetCurrentOfAll(t *testing.T) {
+	t.Run("should return specs of all enabled dogus", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(
+			newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t), currentVersionKey: casVersionStr}),
+			newDescriptorConfigMap("ldap", map[string]string{ldapVersionStr: readLdapDoguStr(t)}),
+		)
+		sut := NewLocalRegistry(client)
+
+		// when
+		dogus, err := sut.GetCurrentOfAll(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []*core.Dogu{readCasDogu(t)}, dogus)
+	})
+
+	t.Run("should return error if a current spec is missing", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{currentVersionKey: casVersionStr}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		_, err := sut.GetCurrentOfAll(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get current specs of all dogus")
+	})
+
+	t.Run("should return error on list error", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().List(testCtx, metav1.ListOptions{LabelSelector: versionRegistryLabelSelector}).Return(nil, assert.AnError)
+		sut := NewLocalRegistry(configMapClientMock)
+
+		// when
+		_, err := sut.GetCurrentOfAll(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to get current versions of all dogus")
+	})
+}
+
+func Test_localRegistry_IsEnabled(t *testing.T) {
+	t.Run("should return true if the dogu has a current version", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t), currentVersionKey: casVersionStr}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		enabled, err := sut.IsEnabled(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("should return false if the dogu has no current version", func(t *testing.T) {
+		// given
+		client := newFakeConfigMapClient(newDescriptorConfigMap("cas", map[string]string{casVersionStr: readCasDoguStr(t)}))
+		sut := NewLocalRegistry(client)
+
+		// when
+		enabled, err := sut.IsEnabled(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("should return false if the local registry does not exist", func(t *testing.T) {
+		// given
+		sut := NewLocalRegistry(newFakeConfigMapClient())
+
+		// when
+		enabled, err := sut.IsEnabled(testCtx, "cas")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("should return error on get error", func(t *testing.T) {
+		// given
+		configMapClientMock := newMockConfigMapClient(t)
+		configMapClientMock.EXPECT().Get(testCtx, "dogu-spec-cas", metav1.GetOptions{}).Return(nil, assert.AnError)
+		sut := NewLocalRegistry(configMapClientMock)
+
+		// when
+		_, err := sut.IsEnabled(testCtx, "cas")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, cloudoguerrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "failed to check if dogu \"cas\" is enabled")
+	})
+}
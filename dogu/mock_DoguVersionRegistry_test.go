@@ -5,6 +5,7 @@ package dogu
 import (
 	context "context"
 
+	core "github.com/cloudogu/cesapp-lib/core"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,6 +22,53 @@ func (_m *MockDoguVersionRegistry) EXPECT() *MockDoguVersionRegistry_Expecter {
 	return &MockDoguVersionRegistry_Expecter{mock: &_m.Mock}
 }
 
+// Disable provides a mock function with given fields: _a0, _a1
+func (_m *MockDoguVersionRegistry) Disable(_a0 context.Context, _a1 SimpleDoguName) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDoguVersionRegistry_Disable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Disable'
+type MockDoguVersionRegistry_Disable_Call struct {
+	*mock.Call
+}
+
+// Disable is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 SimpleDoguName
+func (_e *MockDoguVersionRegistry_Expecter) Disable(_a0 interface{}, _a1 interface{}) *MockDoguVersionRegistry_Disable_Call {
+	return &MockDoguVersionRegistry_Disable_Call{Call: _e.mock.On("Disable", _a0, _a1)}
+}
+
+func (_c *MockDoguVersionRegistry_Disable_Call) Run(run func(_a0 context.Context, _a1 SimpleDoguName)) *MockDoguVersionRegistry_Disable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(SimpleDoguName))
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_Disable_Call) Return(_a0 error) *MockDoguVersionRegistry_Disable_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_Disable_Call) RunAndReturn(run func(context.Context, SimpleDoguName) error) *MockDoguVersionRegistry_Disable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Enable provides a mock function with given fields: _a0, _a1
 func (_m *MockDoguVersionRegistry) Enable(_a0 context.Context, _a1 DoguVersion) error {
 	ret := _m.Called(_a0, _a1)
@@ -68,6 +116,201 @@ func (_c *MockDoguVersionRegistry_Enable_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// EnableAll provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockDoguVersionRegistry) EnableAll(_a0 context.Context, _a1 []DoguVersion, _a2 ...EnableOption) ([]EnableResult, error) {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableAll")
+	}
+
+	var r0 []EnableResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []DoguVersion, ...EnableOption) ([]EnableResult, error)); ok {
+		return rf(_a0, _a1, _a2...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []DoguVersion, ...EnableOption) []EnableResult); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]EnableResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []DoguVersion, ...EnableOption) error); ok {
+		r1 = rf(_a0, _a1, _a2...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDoguVersionRegistry_EnableAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableAll'
+type MockDoguVersionRegistry_EnableAll_Call struct {
+	*mock.Call
+}
+
+// EnableAll is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 []DoguVersion
+//   - _a2 ...EnableOption
+func (_e *MockDoguVersionRegistry_Expecter) EnableAll(_a0 interface{}, _a1 interface{}, _a2 ...interface{}) *MockDoguVersionRegistry_EnableAll_Call {
+	return &MockDoguVersionRegistry_EnableAll_Call{Call: _e.mock.On("EnableAll",
+		append([]interface{}{_a0, _a1}, _a2...)...)}
+}
+
+func (_c *MockDoguVersionRegistry_EnableAll_Call) Run(run func(_a0 context.Context, _a1 []DoguVersion, _a2 ...EnableOption)) *MockDoguVersionRegistry_EnableAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]EnableOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(EnableOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].([]DoguVersion), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_EnableAll_Call) Return(_a0 []EnableResult, _a1 error) *MockDoguVersionRegistry_EnableAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_EnableAll_Call) RunAndReturn(run func(context.Context, []DoguVersion, ...EnableOption) ([]EnableResult, error)) *MockDoguVersionRegistry_EnableAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnableWithOptions provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockDoguVersionRegistry) EnableWithOptions(_a0 context.Context, _a1 DoguVersion, _a2 ...EnableOption) error {
+	_va := make([]interface{}, len(_a2))
+	for _i := range _a2 {
+		_va[_i] = _a2[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0, _a1)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableWithOptions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, DoguVersion, ...EnableOption) error); ok {
+		r0 = rf(_a0, _a1, _a2...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockDoguVersionRegistry_EnableWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableWithOptions'
+type MockDoguVersionRegistry_EnableWithOptions_Call struct {
+	*mock.Call
+}
+
+// EnableWithOptions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 DoguVersion
+//   - _a2 ...EnableOption
+func (_e *MockDoguVersionRegistry_Expecter) EnableWithOptions(_a0 interface{}, _a1 interface{}, _a2 ...interface{}) *MockDoguVersionRegistry_EnableWithOptions_Call {
+	return &MockDoguVersionRegistry_EnableWithOptions_Call{Call: _e.mock.On("EnableWithOptions",
+		append([]interface{}{_a0, _a1}, _a2...)...)}
+}
+
+func (_c *MockDoguVersionRegistry_EnableWithOptions_Call) Run(run func(_a0 context.Context, _a1 DoguVersion, _a2 ...EnableOption)) *MockDoguVersionRegistry_EnableWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]EnableOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(EnableOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(DoguVersion), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_EnableWithOptions_Call) Return(_a0 error) *MockDoguVersionRegistry_EnableWithOptions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_EnableWithOptions_Call) RunAndReturn(run func(context.Context, DoguVersion, ...EnableOption) error) *MockDoguVersionRegistry_EnableWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllVersions provides a mock function with given fields: _a0, _a1
+func (_m *MockDoguVersionRegistry) GetAllVersions(_a0 context.Context, _a1 SimpleDoguName) ([]core.Version, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllVersions")
+	}
+
+	var r0 []core.Version
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) ([]core.Version, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) []core.Version); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Version)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, SimpleDoguName) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDoguVersionRegistry_GetAllVersions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllVersions'
+type MockDoguVersionRegistry_GetAllVersions_Call struct {
+	*mock.Call
+}
+
+// GetAllVersions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 SimpleDoguName
+func (_e *MockDoguVersionRegistry_Expecter) GetAllVersions(_a0 interface{}, _a1 interface{}) *MockDoguVersionRegistry_GetAllVersions_Call {
+	return &MockDoguVersionRegistry_GetAllVersions_Call{Call: _e.mock.On("GetAllVersions", _a0, _a1)}
+}
+
+func (_c *MockDoguVersionRegistry_GetAllVersions_Call) Run(run func(_a0 context.Context, _a1 SimpleDoguName)) *MockDoguVersionRegistry_GetAllVersions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(SimpleDoguName))
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_GetAllVersions_Call) Return(_a0 []core.Version, _a1 error) *MockDoguVersionRegistry_GetAllVersions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_GetAllVersions_Call) RunAndReturn(run func(context.Context, SimpleDoguName) ([]core.Version, error)) *MockDoguVersionRegistry_GetAllVersions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCurrent provides a mock function with given fields: _a0, _a1
 func (_m *MockDoguVersionRegistry) GetCurrent(_a0 context.Context, _a1 SimpleDoguName) (DoguVersion, error) {
 	ret := _m.Called(_a0, _a1)
@@ -183,6 +426,63 @@ func (_c *MockDoguVersionRegistry_GetCurrentOfAll_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// GetPrevious provides a mock function with given fields: _a0, _a1
+func (_m *MockDoguVersionRegistry) GetPrevious(_a0 context.Context, _a1 SimpleDoguName) (DoguVersion, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrevious")
+	}
+
+	var r0 DoguVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) (DoguVersion, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) DoguVersion); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(DoguVersion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, SimpleDoguName) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDoguVersionRegistry_GetPrevious_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPrevious'
+type MockDoguVersionRegistry_GetPrevious_Call struct {
+	*mock.Call
+}
+
+// GetPrevious is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 SimpleDoguName
+func (_e *MockDoguVersionRegistry_Expecter) GetPrevious(_a0 interface{}, _a1 interface{}) *MockDoguVersionRegistry_GetPrevious_Call {
+	return &MockDoguVersionRegistry_GetPrevious_Call{Call: _e.mock.On("GetPrevious", _a0, _a1)}
+}
+
+func (_c *MockDoguVersionRegistry_GetPrevious_Call) Run(run func(_a0 context.Context, _a1 SimpleDoguName)) *MockDoguVersionRegistry_GetPrevious_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(SimpleDoguName))
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_GetPrevious_Call) Return(_a0 DoguVersion, _a1 error) *MockDoguVersionRegistry_GetPrevious_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_GetPrevious_Call) RunAndReturn(run func(context.Context, SimpleDoguName) (DoguVersion, error)) *MockDoguVersionRegistry_GetPrevious_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsEnabled provides a mock function with given fields: _a0, _a1
 func (_m *MockDoguVersionRegistry) IsEnabled(_a0 context.Context, _a1 DoguVersion) (bool, error) {
 	ret := _m.Called(_a0, _a1)
@@ -298,6 +598,65 @@ func (_c *MockDoguVersionRegistry_WatchAllCurrent_Call) RunAndReturn(run func(co
 	return _c
 }
 
+// WatchCurrent provides a mock function with given fields: _a0, _a1
+func (_m *MockDoguVersionRegistry) WatchCurrent(_a0 context.Context, _a1 SimpleDoguName) (<-chan CurrentVersionWatchResult, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchCurrent")
+	}
+
+	var r0 <-chan CurrentVersionWatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) (<-chan CurrentVersionWatchResult, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName) <-chan CurrentVersionWatchResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan CurrentVersionWatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, SimpleDoguName) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockDoguVersionRegistry_WatchCurrent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchCurrent'
+type MockDoguVersionRegistry_WatchCurrent_Call struct {
+	*mock.Call
+}
+
+// WatchCurrent is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 SimpleDoguName
+func (_e *MockDoguVersionRegistry_Expecter) WatchCurrent(_a0 interface{}, _a1 interface{}) *MockDoguVersionRegistry_WatchCurrent_Call {
+	return &MockDoguVersionRegistry_WatchCurrent_Call{Call: _e.mock.On("WatchCurrent", _a0, _a1)}
+}
+
+func (_c *MockDoguVersionRegistry_WatchCurrent_Call) Run(run func(_a0 context.Context, _a1 SimpleDoguName)) *MockDoguVersionRegistry_WatchCurrent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(SimpleDoguName))
+	})
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_WatchCurrent_Call) Return(_a0 <-chan CurrentVersionWatchResult, _a1 error) *MockDoguVersionRegistry_WatchCurrent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDoguVersionRegistry_WatchCurrent_Call) RunAndReturn(run func(context.Context, SimpleDoguName) (<-chan CurrentVersionWatchResult, error)) *MockDoguVersionRegistry_WatchCurrent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockDoguVersionRegistry creates a new instance of MockDoguVersionRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockDoguVersionRegistry(t interface {
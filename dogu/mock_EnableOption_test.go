@@ -0,0 +1,65 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package dogu
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockEnableOption is an autogenerated mock type for the EnableOption type
+type MockEnableOption struct {
+	mock.Mock
+}
+
+type MockEnableOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEnableOption) EXPECT() *MockEnableOption_Expecter {
+	return &MockEnableOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *MockEnableOption) Execute(options *enableOptions) {
+	_m.Called(options)
+}
+
+// MockEnableOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockEnableOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *enableOptions
+func (_e *MockEnableOption_Expecter) Execute(options interface{}) *MockEnableOption_Execute_Call {
+	return &MockEnableOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *MockEnableOption_Execute_Call) Run(run func(options *enableOptions)) *MockEnableOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*enableOptions))
+	})
+	return _c
+}
+
+func (_c *MockEnableOption_Execute_Call) Return() *MockEnableOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockEnableOption_Execute_Call) RunAndReturn(run func(*enableOptions)) *MockEnableOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockEnableOption creates a new instance of MockEnableOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEnableOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEnableOption {
+	mock := &MockEnableOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -70,6 +70,53 @@ func (_c *MockLocalDoguDescriptorRepository_Add_Call) RunAndReturn(run func(cont
 	return _c
 }
 
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *MockLocalDoguDescriptorRepository) Delete(_a0 context.Context, _a1 DoguVersion) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, DoguVersion) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLocalDoguDescriptorRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockLocalDoguDescriptorRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 DoguVersion
+func (_e *MockLocalDoguDescriptorRepository_Expecter) Delete(_a0 interface{}, _a1 interface{}) *MockLocalDoguDescriptorRepository_Delete_Call {
+	return &MockLocalDoguDescriptorRepository_Delete_Call{Call: _e.mock.On("Delete", _a0, _a1)}
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Delete_Call) Run(run func(_a0 context.Context, _a1 DoguVersion)) *MockLocalDoguDescriptorRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(DoguVersion))
+	})
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Delete_Call) Return(_a0 error) *MockLocalDoguDescriptorRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Delete_Call) RunAndReturn(run func(context.Context, DoguVersion) error) *MockLocalDoguDescriptorRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteAll provides a mock function with given fields: _a0, _a1
 func (_m *MockLocalDoguDescriptorRepository) DeleteAll(_a0 context.Context, _a1 SimpleDoguName) error {
 	ret := _m.Called(_a0, _a1)
@@ -235,6 +282,124 @@ func (_c *MockLocalDoguDescriptorRepository_GetAll_Call) RunAndReturn(run func(c
 	return _c
 }
 
+// Prune provides a mock function with given fields: ctx, name, keepN
+func (_m *MockLocalDoguDescriptorRepository) Prune(ctx context.Context, name SimpleDoguName, keepN int) ([]core.Version, error) {
+	ret := _m.Called(ctx, name, keepN)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prune")
+	}
+
+	var r0 []core.Version
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName, int) ([]core.Version, error)); ok {
+		return rf(ctx, name, keepN)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, SimpleDoguName, int) []core.Version); ok {
+		r0 = rf(ctx, name, keepN)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]core.Version)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, SimpleDoguName, int) error); ok {
+		r1 = rf(ctx, name, keepN)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLocalDoguDescriptorRepository_Prune_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Prune'
+type MockLocalDoguDescriptorRepository_Prune_Call struct {
+	*mock.Call
+}
+
+// Prune is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name SimpleDoguName
+//   - keepN int
+func (_e *MockLocalDoguDescriptorRepository_Expecter) Prune(ctx interface{}, name interface{}, keepN interface{}) *MockLocalDoguDescriptorRepository_Prune_Call {
+	return &MockLocalDoguDescriptorRepository_Prune_Call{Call: _e.mock.On("Prune", ctx, name, keepN)}
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Prune_Call) Run(run func(ctx context.Context, name SimpleDoguName, keepN int)) *MockLocalDoguDescriptorRepository_Prune_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(SimpleDoguName), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Prune_Call) Return(_a0 []core.Version, _a1 error) *MockLocalDoguDescriptorRepository_Prune_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_Prune_Call) RunAndReturn(run func(context.Context, SimpleDoguName, int) ([]core.Version, error)) *MockLocalDoguDescriptorRepository_Prune_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WatchDescriptors provides a mock function with given fields: _a0
+func (_m *MockLocalDoguDescriptorRepository) WatchDescriptors(_a0 context.Context) (<-chan DescriptorWatchResult, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchDescriptors")
+	}
+
+	var r0 <-chan DescriptorWatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan DescriptorWatchResult, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan DescriptorWatchResult); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan DescriptorWatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLocalDoguDescriptorRepository_WatchDescriptors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchDescriptors'
+type MockLocalDoguDescriptorRepository_WatchDescriptors_Call struct {
+	*mock.Call
+}
+
+// WatchDescriptors is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *MockLocalDoguDescriptorRepository_Expecter) WatchDescriptors(_a0 interface{}) *MockLocalDoguDescriptorRepository_WatchDescriptors_Call {
+	return &MockLocalDoguDescriptorRepository_WatchDescriptors_Call{Call: _e.mock.On("WatchDescriptors", _a0)}
+}
+
+func (_c *MockLocalDoguDescriptorRepository_WatchDescriptors_Call) Run(run func(_a0 context.Context)) *MockLocalDoguDescriptorRepository_WatchDescriptors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_WatchDescriptors_Call) Return(_a0 <-chan DescriptorWatchResult, _a1 error) *MockLocalDoguDescriptorRepository_WatchDescriptors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLocalDoguDescriptorRepository_WatchDescriptors_Call) RunAndReturn(run func(context.Context) (<-chan DescriptorWatchResult, error)) *MockLocalDoguDescriptorRepository_WatchDescriptors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockLocalDoguDescriptorRepository creates a new instance of MockLocalDoguDescriptorRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockLocalDoguDescriptorRepository(t interface {
@@ -69,6 +69,68 @@ func (_c *MockLocalRegistry_Enable_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// EnableWithOptions provides a mock function with given fields: ctx, dogu, opts
+func (_m *MockLocalRegistry) EnableWithOptions(ctx context.Context, dogu *core.Dogu, opts ...EnableOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, dogu)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableWithOptions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Dogu, ...EnableOption) error); ok {
+		r0 = rf(ctx, dogu, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLocalRegistry_EnableWithOptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableWithOptions'
+type MockLocalRegistry_EnableWithOptions_Call struct {
+	*mock.Call
+}
+
+// EnableWithOptions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dogu *core.Dogu
+//   - opts ...EnableOption
+func (_e *MockLocalRegistry_Expecter) EnableWithOptions(ctx interface{}, dogu interface{}, opts ...interface{}) *MockLocalRegistry_EnableWithOptions_Call {
+	return &MockLocalRegistry_EnableWithOptions_Call{Call: _e.mock.On("EnableWithOptions",
+		append([]interface{}{ctx, dogu}, opts...)...)}
+}
+
+func (_c *MockLocalRegistry_EnableWithOptions_Call) Run(run func(ctx context.Context, dogu *core.Dogu, opts ...EnableOption)) *MockLocalRegistry_EnableWithOptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]EnableOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(EnableOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*core.Dogu), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLocalRegistry_EnableWithOptions_Call) Return(_a0 error) *MockLocalRegistry_EnableWithOptions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLocalRegistry_EnableWithOptions_Call) RunAndReturn(run func(context.Context, *core.Dogu, ...EnableOption) error) *MockLocalRegistry_EnableWithOptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCurrent provides a mock function with given fields: ctx, simpleDoguName
 func (_m *MockLocalRegistry) GetCurrent(ctx context.Context, simpleDoguName string) (*core.Dogu, error) {
 	ret := _m.Called(ctx, simpleDoguName)
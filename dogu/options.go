@@ -0,0 +1,33 @@
+package dogu
+
+type enableOptions struct {
+	preflightCheck  bool
+	forcedDowngrade bool
+}
+
+// EnableOption configures a single call of EnableWithOptions or EnableAll.
+type EnableOption func(options *enableOptions)
+
+// WithPreflightCheck makes EnableWithOptions check the dependencies of the dogu version and of all enabled dogus before the version
+// is enabled. Downgrades are refused unless WithForcedDowngrade is set. The violations are returned as *PreflightError.
+func WithPreflightCheck() EnableOption {
+	return func(options *enableOptions) {
+		options.preflightCheck = true
+	}
+}
+
+// WithForcedDowngrade allows the pre-flight check to enable a version that is older than the current version.
+func WithForcedDowngrade() EnableOption {
+	return func(options *enableOptions) {
+		options.forcedDowngrade = true
+	}
+}
+
+func applyEnableOptions(opts []EnableOption) enableOptions {
+	options := enableOptions{}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}
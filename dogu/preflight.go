@@ -0,0 +1,140 @@
+package dogu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/core"
+)
+
+// PreflightViolationType describes why enabling a dogu version would break the installed dogus.
+type PreflightViolationType int
+
+const (
+	// UnsatisfiedDependencyViolation marks a dependency of the dogu version that is not fulfilled by the enabled dogus.
+	UnsatisfiedDependencyViolation PreflightViolationType = iota + 1
+	// BrokenDependentViolation marks a dependency of an enabled dogu that is not fulfilled by the dogu version.
+	BrokenDependentViolation
+	// DowngradeViolation marks a dogu version that is older than the current version.
+	DowngradeViolation
+)
+
+// String returns the string representation of the PreflightViolationType.
+func (vt PreflightViolationType) String() string {
+	switch vt {
+	case UnsatisfiedDependencyViolation:
+		return "unsatisfied dependency"
+	case BrokenDependentViolation:
+		return "broken dependent"
+	case DowngradeViolation:
+		return "downgrade"
+	default:
+		return "unknown violation"
+	}
+}
+
+// PreflightViolation describes a single reason why a dogu version must not be enabled.
+type PreflightViolation struct {
+	Type PreflightViolationType
+	// Dependency is the failing dependency. It is not set for downgrades.
+	Dependency *UnsatisfiedDependency
+	// CurrentVersion is the current version of the dogu. It is only set for downgrades.
+	CurrentVersion *core.Version
+}
+
+// String returns a human-readable representation of the PreflightViolation.
+func (v PreflightViolation) String() string {
+	if v.Dependency == nil {
+		if v.CurrentVersion == nil {
+			return v.Type.String()
+		}
+
+		return fmt.Sprintf("%s from current version %q", v.Type, v.CurrentVersion.Raw)
+	}
+
+	dependency := *v.Dependency
+	if dependency.IsMissing() {
+		return fmt.Sprintf("%s: dogu %q requires dogu %q, which is not enabled", v.Type, dependency.Dependent, dependency.Dependency)
+	}
+
+	return fmt.Sprintf("%s: dogu %q requires dogu %q with version %q, but version %q is given",
+		v.Type, dependency.Dependent, dependency.Dependency, dependency.VersionConstraint, dependency.InstalledVersion.Raw)
+}
+
+// PreflightError is returned if enabling a dogu version would break the installed dogus.
+type PreflightError struct {
+	DoguVersion DoguVersion
+	Violations  []PreflightViolation
+}
+
+// Error returns all violations as a single string.
+func (pe *PreflightError) Error() string {
+	messages := make([]string, 0, len(pe.Violations))
+	for _, v := range pe.Violations {
+		messages = append(messages, v.String())
+	}
+
+	return fmt.Sprintf("pre-flight check for dogu %q with version %q failed: %s", pe.DoguVersion.Name, pe.DoguVersion.Version.Raw, strings.Join(messages, "; "))
+}
+
+// checkPreflight checks if the dogu versions can be enabled together with the currently enabled versions of all other
+// dogus. The violations are returned as *PreflightError per dogu. Dogus without violations are not contained.
+func checkPreflight(ctx context.Context, versionRegistry DoguVersionRegistry, descriptorRepository LocalDoguDescriptorRepository, doguVersions []DoguVersion, options enableOptions) (map[SimpleDoguName]*PreflightError, error) {
+	preflightErrs := map[SimpleDoguName]*PreflightError{}
+	for _, doguVersion := range doguVersions {
+		preflightErrs[doguVersion.Name] = &PreflightError{DoguVersion: doguVersion}
+	}
+
+	currentVersions, err := versionRegistry.GetCurrentOfAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current versions of all dogus: %w", err)
+	}
+
+	// the graph contains the dogu versions to check instead of their current versions
+	graphVersions := slices.Clone(doguVersions)
+	for _, currentVersion := range currentVersions {
+		preflightErr, ok := preflightErrs[currentVersion.Name]
+		if !ok {
+			graphVersions = append(graphVersions, currentVersion)
+			continue
+		}
+
+		if !options.forcedDowngrade && currentVersion.Version.IsNewerThan(preflightErr.DoguVersion.Version) {
+			preflightErr.Violations = append(preflightErr.Violations, PreflightViolation{Type: DowngradeViolation, CurrentVersion: &currentVersion.Version})
+		}
+	}
+
+	dogusByVersion, err := descriptorRepository.GetAll(ctx, graphVersions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dogu descriptors: %w", err)
+	}
+
+	dogus := make([]*core.Dogu, 0, len(graphVersions))
+	for _, graphVersion := range graphVersions {
+		dogus = append(dogus, dogusByVersion[graphVersion])
+	}
+
+	unsatisfiedDependencies, err := NewDependencyGraph(dogus).GetUnsatisfiedDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	// unsatisfied dependencies between other dogus are not affected by the dogu versions
+	for _, dependency := range unsatisfiedDependencies {
+		if preflightErr, ok := preflightErrs[dependency.Dependent]; ok {
+			preflightErr.Violations = append(preflightErr.Violations, PreflightViolation{Type: UnsatisfiedDependencyViolation, Dependency: &dependency})
+		} else if preflightErr, ok := preflightErrs[dependency.Dependency]; ok {
+			preflightErr.Violations = append(preflightErr.Violations, PreflightViolation{Type: BrokenDependentViolation, Dependency: &dependency})
+		}
+	}
+
+	for name, preflightErr := range preflightErrs {
+		if len(preflightErr.Violations) == 0 {
+			delete(preflightErrs, name)
+		}
+	}
+
+	return preflightErrs, nil
+}
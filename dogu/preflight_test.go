@@ -0,0 +1,170 @@
+package dogu
+
+import (
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightViolationType_String(t *testing.T) {
+	assert.Equal(t, "unsatisfied dependency", UnsatisfiedDependencyViolation.String())
+	assert.Equal(t, "broken dependent", BrokenDependentViolation.String())
+	assert.Equal(t, "downgrade", DowngradeViolation.String())
+	assert.Equal(t, "unknown violation", PreflightViolationType(0).String())
+}
+
+func TestPreflightError_Error(t *testing.T) {
+	// given
+	installedVersion := parseVersionStr(t, "14.12-1")
+	currentVersion := parseVersionStr(t, "5.1.4-1")
+	sut := &PreflightError{
+		DoguVersion: DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.3-1")},
+		Violations: []PreflightViolation{
+			{Type: UnsatisfiedDependencyViolation, Dependency: &UnsatisfiedDependency{
+				DoguDependency: DoguDependency{Dependent: "redmine", Dependency: "cas"},
+			}},
+			{Type: UnsatisfiedDependencyViolation, Dependency: &UnsatisfiedDependency{
+				DoguDependency:   DoguDependency{Dependent: "redmine", Dependency: "postgresql", VersionConstraint: ">=15.0.0-1"},
+				InstalledVersion: &installedVersion,
+			}},
+			{Type: DowngradeViolation, CurrentVersion: &currentVersion},
+			{Type: DowngradeViolation},
+		},
+	}
+
+	// when
+	msg := sut.Error()
+
+	// then
+	assert.Equal(t, "pre-flight check for dogu \"redmine\" with version \"5.1.3-1\" failed: "+
+		"unsatisfied dependency: dogu \"redmine\" requires dogu \"cas\", which is not enabled; "+
+		"unsatisfied dependency: dogu \"redmine\" requires dogu \"postgresql\" with version \">=15.0.0-1\", but version \"14.12-1\" is given; "+
+		"downgrade from current version \"5.1.4-1\"; "+
+		"downgrade", msg)
+}
+
+func Test_checkPreflight(t *testing.T) {
+	redmineVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.3-1")}
+	currentRedmineVersion := DoguVersion{Name: "redmine", Version: parseVersionStr(t, "5.1.4-1")}
+	postgresqlVersion := DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "14.12-1")}
+	newPostgresqlVersion := DoguVersion{Name: "postgresql", Version: parseVersionStr(t, "15.8-1")}
+	scmVersion := DoguVersion{Name: "scm", Version: parseVersionStr(t, "3.2.1-1")}
+	casVersion := DoguVersion{Name: "cas", Version: parseVersionStr(t, "7.0.5.1-1")}
+
+	redmine := newTestDogu("redmine", "5.1.3-1", []core.Dependency{{Name: "postgresql", Version: ">=15.0.0-1"}, {Name: "cas"}})
+	postgresql := newTestDogu("postgresql", "14.12-1", nil)
+	newPostgresql := newTestDogu("postgresql", "15.8-1", nil)
+	scm := newTestDogu("scm", "3.2.1-1", nil, core.Dependency{Name: "redmine", Version: ">=5.1.4-1"})
+	cas := newTestDogu("cas", "7.0.5.1-1", []core.Dependency{{Name: "ldap"}})
+
+	t.Run("should return all violations", func(t *testing.T) {
+		// given
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]DoguVersion{postgresqlVersion, currentRedmineVersion, scmVersion}, nil)
+		descriptorRepositoryMock := NewMockLocalDoguDescriptorRepository(t)
+		descriptorRepositoryMock.EXPECT().GetAll(testCtx, []DoguVersion{redmineVersion, postgresqlVersion, scmVersion}).
+			Return(map[DoguVersion]*core.Dogu{redmineVersion: redmine, postgresqlVersion: postgresql, scmVersion: scm}, nil)
+
+		// when
+		preflightErrs, err := checkPreflight(testCtx, versionRegistryMock, descriptorRepositoryMock, []DoguVersion{redmineVersion}, enableOptions{})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, preflightErrs, 1)
+		preflightErr := preflightErrs["redmine"]
+		assert.Equal(t, redmineVersion, preflightErr.DoguVersion)
+		require.Len(t, preflightErr.Violations, 4)
+		assert.Equal(t, DowngradeViolation, preflightErr.Violations[0].Type)
+		assert.Equal(t, "5.1.4-1", preflightErr.Violations[0].CurrentVersion.Raw)
+		assert.Equal(t, UnsatisfiedDependencyViolation, preflightErr.Violations[1].Type)
+		assert.Equal(t, SimpleDoguName("postgresql"), preflightErr.Violations[1].Dependency.Dependency)
+		assert.Equal(t, UnsatisfiedDependencyViolation, preflightErr.Violations[2].Type)
+		assert.Equal(t, SimpleDoguName("cas"), preflightErr.Violations[2].Dependency.Dependency)
+		assert.True(t, preflightErr.Violations[2].Dependency.IsMissing())
+		assert.Equal(t, BrokenDependentViolation, preflightErr.Violations[3].Type)
+		assert.Equal(t, SimpleDoguName("scm"), preflightErr.Violations[3].Dependency.Dependent)
+	})
+
+	t.Run("should check dogu versions together", func(t *testing.T) {
+		// given
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]DoguVersion{postgresqlVersion, casVersion}, nil)
+		descriptorRepositoryMock := NewMockLocalDoguDescriptorRepository(t)
+		descriptorRepositoryMock.EXPECT().GetAll(testCtx, []DoguVersion{redmineVersion, newPostgresqlVersion, casVersion}).
+			Return(map[DoguVersion]*core.Dogu{redmineVersion: redmine, newPostgresqlVersion: newPostgresql, casVersion: cas}, nil)
+
+		// when
+		preflightErrs, err := checkPreflight(testCtx, versionRegistryMock, descriptorRepositoryMock, []DoguVersion{redmineVersion, newPostgresqlVersion}, enableOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, preflightErrs)
+	})
+
+	t.Run("should allow forced downgrade and ignore violations of other dogus", func(t *testing.T) {
+		// given
+		scm := newTestDogu("scm", "3.2.1-1", []core.Dependency{{Name: "jenkins"}})
+		redmine := newTestDogu("redmine", "5.1.3-1", []core.Dependency{{Name: "postgresql", Version: ">=14.0.0-1"}, {Name: "cas"}})
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]DoguVersion{postgresqlVersion, currentRedmineVersion, scmVersion, casVersion}, nil)
+		descriptorRepositoryMock := NewMockLocalDoguDescriptorRepository(t)
+		descriptorRepositoryMock.EXPECT().GetAll(testCtx, []DoguVersion{redmineVersion, postgresqlVersion, scmVersion, casVersion}).
+			Return(map[DoguVersion]*core.Dogu{redmineVersion: redmine, postgresqlVersion: postgresql, scmVersion: scm, casVersion: cas}, nil)
+
+		// when
+		preflightErrs, err := checkPreflight(testCtx, versionRegistryMock, descriptorRepositoryMock, []DoguVersion{redmineVersion}, enableOptions{forcedDowngrade: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, preflightErrs)
+	})
+
+	t.Run("should fail to get current versions", func(t *testing.T) {
+		// given
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return(nil, assert.AnError)
+
+		// when
+		_, err := checkPreflight(testCtx, versionRegistryMock, NewMockLocalDoguDescriptorRepository(t), []DoguVersion{redmineVersion}, enableOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get current versions of all dogus")
+	})
+
+	t.Run("should fail to get dogu descriptors", func(t *testing.T) {
+		// given
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]DoguVersion{postgresqlVersion}, nil)
+		descriptorRepositoryMock := NewMockLocalDoguDescriptorRepository(t)
+		descriptorRepositoryMock.EXPECT().GetAll(testCtx, []DoguVersion{redmineVersion, postgresqlVersion}).Return(nil, assert.AnError)
+
+		// when
+		_, err := checkPreflight(testCtx, versionRegistryMock, descriptorRepositoryMock, []DoguVersion{redmineVersion}, enableOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get dogu descriptors")
+	})
+
+	t.Run("should fail on invalid version constraint", func(t *testing.T) {
+		// given
+		redmine := newTestDogu("redmine", "5.1.3-1", []core.Dependency{{Name: "postgresql", Version: ">>>15.0.0-1"}})
+		versionRegistryMock := NewMockDoguVersionRegistry(t)
+		versionRegistryMock.EXPECT().GetCurrentOfAll(testCtx).Return([]DoguVersion{postgresqlVersion}, nil)
+		descriptorRepositoryMock := NewMockLocalDoguDescriptorRepository(t)
+		descriptorRepositoryMock.EXPECT().GetAll(testCtx, []DoguVersion{redmineVersion, postgresqlVersion}).
+			Return(map[DoguVersion]*core.Dogu{redmineVersion: redmine, postgresqlVersion: postgresql}, nil)
+
+		// when
+		_, err := checkPreflight(testCtx, versionRegistryMock, descriptorRepositoryMock, []DoguVersion{redmineVersion}, enableOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse version constraint")
+	})
+}
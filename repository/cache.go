@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+const cacheNameIndex = "name"
+
+// ConfigCache is an informer-backed cache of the config objects (global, dogu and sensitive configs) of a namespace.
+// A single cache can be shared by multiple cached repositories. It has to be started with Start before it is used.
+// As long as the cache has not synced, the cached repositories read from the API server.
+type ConfigCache struct {
+	informer cache.SharedIndexInformer
+	// ctx is the context the cache was started with. It is used for the list and watch requests of the informer.
+	ctx context.Context
+}
+
+// NewConfigMapCache creates a cache for the config maps of the global and the dogu configs.
+func NewConfigMapCache(client ConfigMapClient) *ConfigCache {
+	listFunc := func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+		return client.List(ctx, options)
+	}
+
+	return newConfigCache(listFunc, client.Watch, &v1.ConfigMap{})
+}
+
+// NewSecretCache creates a cache for the secrets of the sensitive dogu configs.
+func NewSecretCache(client SecretClient) *ConfigCache {
+	listFunc := func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+		return client.List(ctx, options)
+	}
+
+	return newConfigCache(listFunc, client.Watch, &v1.Secret{})
+}
+
+func newConfigCache(
+	listFunc func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error),
+	watchFunc func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error),
+	objectType runtime.Object,
+) *ConfigCache {
+	configCache := &ConfigCache{ctx: context.Background()}
+
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = configCacheSelector().String()
+			return listFunc(configCache.ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = configCacheSelector().String()
+			return watchFunc(configCache.ctx, options)
+		},
+	}
+
+	configCache.informer = cache.NewSharedIndexInformer(listWatch, objectType, time.Duration(0), cache.Indexers{
+		cacheNameIndex: func(obj any) ([]string, error) {
+			object, ok := obj.(metav1.Object)
+			if !ok {
+				return nil, fmt.Errorf("object of type %T has no name", obj)
+			}
+
+			return []string{object.GetName()}, nil
+		},
+	})
+
+	return configCache
+}
+
+func configCacheSelector() labels.Selector {
+	appRequirement, _ := labels.NewRequirement(appLabelKey, selection.Equals, []string{appLabelValueCes})
+	typeRequirement, _ := labels.NewRequirement(typeLabelKey, selection.In, []string{
+		globalConfigType.String(),
+		doguConfigType.String(),
+		sensitiveConfigType.String(),
+	})
+
+	return labels.NewSelector().Add(*appRequirement, *typeRequirement)
+}
+
+// Start runs the informer of the cache in the background until the context is done.
+// The list and watch requests of the informer are made with the given context.
+func (cc *ConfigCache) Start(ctx context.Context) {
+	cc.ctx = ctx
+	go cc.informer.Run(ctx.Done())
+}
+
+// HasSynced returns true if the initial list of all config objects has been loaded into the cache.
+func (cc *ConfigCache) HasSynced() bool {
+	return cc.informer.HasSynced()
+}
+
+// WaitForCacheSync blocks until the cache has synced or the context is done.
+// It returns false if the context was done before the cache has synced.
+func (cc *ConfigCache) WaitForCacheSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), cc.HasSynced)
+}
+
+// apiServerReadsKey marks a context whose reads must bypass the cache, e.g. because a previous update conflicted with
+// a modification the cache has not received yet.
+type apiServerReadsKey struct{}
+
+// withAPIServerReads returns a context that makes cached clients read from the API server.
+func withAPIServerReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiServerReadsKey{}, true)
+}
+
+func readsFromAPIServer(ctx context.Context) bool {
+	bypass, _ := ctx.Value(apiServerReadsKey{}).(bool)
+	return bypass
+}
+
+// cachedConfigClient serves reads from a ConfigCache as soon as it has synced and delegates everything else to the
+// underlying client. Writes still detect conflicts through the resource version of the read objects. As the cache may
+// lag behind the API server, Get, which is used to merge changes before an update, always reads from the API server.
+// Other reads do so if the context is marked with withAPIServerReads.
+type cachedConfigClient struct {
+	configClient
+	cache   *ConfigCache
+	labels  labels.Set
+	dataKey string
+}
+
+var _ configClient = cachedConfigClient{}
+
+func (ccc cachedConfigClient) GetWithListResourceVersion(ctx context.Context, name string) (clientData, string, error) {
+	if !ccc.cache.HasSynced() || readsFromAPIServer(ctx) {
+		return ccc.configClient.GetWithListResourceVersion(ctx, name)
+	}
+
+	cd, err := ccc.getFromCache(name)
+	if err != nil {
+		return clientData{}, ccc.cache.informer.LastSyncResourceVersion(), fmt.Errorf("unable to get config from cache: %w", err)
+	}
+
+	return cd, ccc.cache.informer.LastSyncResourceVersion(), nil
+}
+
+func (ccc cachedConfigClient) List(ctx context.Context, selector labels.Selector) ([]clientData, string, error) {
+	if !ccc.cache.HasSynced() {
+		return ccc.configClient.List(ctx, selector)
+	}
+
+	clientSelector, selectable := listSelector(ccc.labels, selector)
+	if !selectable {
+		return nil, ccc.cache.informer.LastSyncResourceVersion(), nil
+	}
+
+	var result []clientData
+	for _, obj := range ccc.cache.informer.GetStore().List() {
+		object, ok := obj.(metav1.Object)
+		if !ok || !clientSelector.Matches(labels.Set(object.GetLabels())) {
+			continue
+		}
+
+		if cd, hasData := toClientData(obj, ccc.dataKey); hasData {
+			result = append(result, cd)
+		}
+	}
+
+	return result, ccc.cache.informer.LastSyncResourceVersion(), nil
+}
+
+func (ccc cachedConfigClient) getFromCache(name string) (clientData, error) {
+	objects, err := ccc.cache.informer.GetIndexer().ByIndex(cacheNameIndex, name)
+	if err != nil {
+		return clientData{}, errors.NewGenericError(err)
+	}
+
+	clientSelector, _ := listSelector(ccc.labels, labels.Everything())
+	for _, obj := range objects {
+		object, ok := obj.(metav1.Object)
+		if !ok || !clientSelector.Matches(labels.Set(object.GetLabels())) {
+			continue
+		}
+
+		cd, hasData := toClientData(obj, ccc.dataKey)
+		if !hasData {
+			return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", ccc.dataKey))
+		}
+
+		return cd, nil
+	}
+
+	return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find config %s in cache", name))
+}
+
+// toClientData converts a cached object to client data. The object is copied, because the raw data may be modified
+// for updates.
+func toClientData(obj any, dataKey string) (clientData, bool) {
+	switch o := obj.(type) {
+	case *v1.ConfigMap:
+		dataStr, ok := o.Data[dataKey]
+		return clientData{dataStr: dataStr, rawData: o.DeepCopy()}, ok
+	case *v1.Secret:
+		dataBytes, ok := o.Data[dataKey]
+		return clientData{dataStr: string(dataBytes), rawData: o.DeepCopy()}, ok
+	default:
+		return clientData{}, false
+	}
+}
+
+func newCachedConfigMapClient(client ConfigMapClient, t configType, dataKey string, configCache *ConfigCache) cachedConfigClient {
+	cmClient := createConfigMapClient(client, t, dataKey)
+	return cachedConfigClient{configClient: cmClient, cache: configCache, labels: cmClient.labels, dataKey: dataKey}
+}
+
+func newCachedSecretClient(client SecretClient, t configType, dataKey string, configCache *ConfigCache) cachedConfigClient {
+	sClient := createSecretClient(client, t, dataKey)
+	return cachedConfigClient{configClient: sClient, cache: configCache, labels: sClient.labels, dataKey: dataKey}
+}
+
+// CachedGlobalConfigRepository is a GlobalConfigRepository that reads the global config from a ConfigCache.
+type CachedGlobalConfigRepository struct {
+	*GlobalConfigRepository
+	cache *ConfigCache
+}
+
+// NewCachedGlobalConfigRepository creates a GlobalConfigRepository that reads from the given config map cache.
+// Writes are sent to the API server with the client.
+func NewCachedGlobalConfigRepository(client ConfigMapClient, configCache *ConfigCache, opts ...ConfigRepositoryOption) *CachedGlobalConfigRepository {
+	options := applyConfigRepositoryOptions(opts)
+	cfgClient := newCachedConfigMapClient(client, globalConfigType, options.dataKey, configCache)
+
+	return &CachedGlobalConfigRepository{
+		GlobalConfigRepository: &GlobalConfigRepository{
+			generalConfigRepository: newConfigRepo(cfgClient, opts...),
+		},
+		cache: configCache,
+	}
+}
+
+// HasSynced returns true if the cache of the repository has synced.
+func (cgcr CachedGlobalConfigRepository) HasSynced() bool {
+	return cgcr.cache.HasSynced()
+}
+
+// CachedDoguConfigRepository is a DoguConfigRepository that reads the dogu configs from a ConfigCache.
+type CachedDoguConfigRepository struct {
+	*DoguConfigRepository
+	cache *ConfigCache
+}
+
+// NewCachedDoguConfigRepository creates a DoguConfigRepository that reads from the given config map cache.
+// Writes are sent to the API server with the client.
+func NewCachedDoguConfigRepository(client ConfigMapClient, configCache *ConfigCache, opts ...DoguConfigRepositoryOption) *CachedDoguConfigRepository {
+	options := applyDoguConfigRepositoryOptions(opts)
+	cfgClient := newCachedConfigMapClient(client, doguConfigType, options.dataKey, configCache)
+
+	return &CachedDoguConfigRepository{
+		DoguConfigRepository: newDoguConfigRepository(cfgClient, config.NewDoguConfigValidator(), options),
+		cache:                configCache,
+	}
+}
+
+// NewCachedSensitiveDoguConfigRepository creates a sensitive DoguConfigRepository that reads from the given secret
+// cache. Writes are sent to the API server with the client.
+func NewCachedSensitiveDoguConfigRepository(client SecretClient, configCache *ConfigCache, opts ...DoguConfigRepositoryOption) *CachedDoguConfigRepository {
+	options := applyDoguConfigRepositoryOptions(opts)
+	cfgClient := newCachedSecretClient(client, sensitiveConfigType, options.dataKey, configCache)
+
+	return &CachedDoguConfigRepository{
+		DoguConfigRepository: newDoguConfigRepository(cfgClient, config.NewSensitiveDoguConfigValidator(), options),
+		cache:                configCache,
+	}
+}
+
+// HasSynced returns true if the cache of the repository has synced.
+func (cdcr CachedDoguConfigRepository) HasSynced() bool {
+	return cdcr.cache.HasSynced()
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	liberrors "github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+const cacheTestNamespace = "ecosystem"
+
+func newCacheTestConfigMap(name string, t configType, doguName string, data string) *v1.ConfigMap {
+	cmLabels := map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: t.String()}
+	if doguName != "" {
+		cmLabels[doguNameLabelKey] = doguName
+	}
+
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cacheTestNamespace, Labels: cmLabels},
+		Data:       map[string]string{dataKeyName: data},
+	}
+}
+
+func startSyncedCache(t *testing.T, configCache *ConfigCache) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	configCache.Start(ctx)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer syncCancel()
+	require.True(t, configCache.WaitForCacheSync(syncCtx))
+}
+
+// startLaggingCache syncs the cache and stops it afterward, so that later modifications are not cached.
+func startLaggingCache(t *testing.T, configCache *ConfigCache) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configCache.Start(ctx)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer syncCancel()
+	require.True(t, configCache.WaitForCacheSync(syncCtx))
+}
+
+func countActions(clientSet *fake.Clientset, verb string) int {
+	count := 0
+	for _, action := range clientSet.Actions() {
+		if action.GetVerb() == verb {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestConfigCache_HasSynced(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+	configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+	assert.False(t, configCache.HasSynced())
+
+	startSyncedCache(t, configCache)
+
+	assert.True(t, configCache.HasSynced())
+}
+
+func TestConfigCache_WaitForCacheSync(t *testing.T) {
+	configCache := NewConfigMapCache(fake.NewSimpleClientset().CoreV1().ConfigMaps(cacheTestNamespace))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.False(t, configCache.WaitForCacheSync(ctx))
+}
+
+func TestConfigCache_Start(t *testing.T) {
+	type ctxKey struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "start"))
+	defer cancel()
+
+	client := NewMockConfigMapClient(t)
+	client.EXPECT().List(ctx, mock.Anything).Return(&v1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil)
+	client.EXPECT().Watch(ctx, mock.Anything).Return(watch.NewFake(), nil).Maybe()
+	configCache := NewConfigMapCache(client)
+
+	configCache.Start(ctx)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer syncCancel()
+	assert.True(t, configCache.WaitForCacheSync(syncCtx))
+}
+
+func TestCachedGlobalConfigRepository(t *testing.T) {
+	t.Run("should read from cache after sync", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(
+			newCacheTestConfigMap("global-config", globalConfigType, "", "fqdn: example.com\n"),
+			newCacheTestConfigMap("cas-config", doguConfigType, "cas", "logging: INFO\n"),
+		)
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		repo := NewCachedGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+
+		startSyncedCache(t, configCache)
+		assert.True(t, repo.HasSynced())
+		listCountAfterSync := countActions(clientSet, "list")
+
+		globalConfig, err := repo.Get(context.TODO())
+
+		require.NoError(t, err)
+		value, _ := globalConfig.Get("fqdn")
+		assert.Equal(t, config.Value("example.com"), value)
+		assert.Equal(t, 0, countActions(clientSet, "get"))
+		assert.Equal(t, listCountAfterSync, countActions(clientSet, "list"))
+	})
+
+	t.Run("should write to API server", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(newCacheTestConfigMap("global-config", globalConfigType, "", "fqdn: example.com\n"))
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		repo := NewCachedGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+		startSyncedCache(t, configCache)
+
+		globalConfig, err := repo.Get(context.TODO())
+		require.NoError(t, err)
+		globalConfig.Config, err = globalConfig.Set("fqdn", "new.example.com")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(context.TODO(), globalConfig)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, countActions(clientSet, "update"))
+		cm, err := clientSet.CoreV1().ConfigMaps(cacheTestNamespace).Get(context.TODO(), "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "fqdn: new.example.com\n", cm.Data[dataKeyName])
+
+		assert.Eventually(t, func() bool {
+			cached, getErr := repo.Get(context.TODO())
+			value, _ := cached.Get("fqdn")
+			return getErr == nil && value == "new.example.com"
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should merge with API server state if cache lags behind", func(t *testing.T) {
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		client := clientSet.CoreV1().ConfigMaps(cacheTestNamespace)
+		configCache := NewConfigMapCache(client)
+		repo := NewCachedGlobalConfigRepository(client, configCache)
+		startLaggingCache(t, configCache)
+
+		cm, err := client.Get(context.TODO(), "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		cm.Data[dataKeyName] = "fqdn: example.com\nadmin_group: admins\n"
+		_, err = client.Update(context.TODO(), cm, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		globalConfig, err := repo.Get(context.TODO())
+		require.NoError(t, err)
+		_, isAdminGroupCached := globalConfig.Get("admin_group")
+		require.False(t, isAdminGroupCached)
+		globalConfig.Config, err = globalConfig.Set("fqdn", "new.example.com")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(context.TODO(), globalConfig)
+
+		require.NoError(t, err)
+		cm, err = client.Get(context.TODO(), "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "3", cm.ResourceVersion)
+		assert.Equal(t, "admin_group: admins\nfqdn: new.example.com\n", cm.Data[dataKeyName])
+	})
+
+	t.Run("should read from API server before sync", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(newCacheTestConfigMap("global-config", globalConfigType, "", "fqdn: example.com\n"))
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		repo := NewCachedGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+
+		globalConfig, err := repo.Get(context.TODO())
+
+		require.NoError(t, err)
+		value, _ := globalConfig.Get("fqdn")
+		assert.Equal(t, config.Value("example.com"), value)
+		assert.False(t, repo.HasSynced())
+	})
+
+	t.Run("should return not found for missing config", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(newCacheTestConfigMap("global-config", doguConfigType, "global", "fqdn: example.com\n"))
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		repo := NewCachedGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+		startSyncedCache(t, configCache)
+
+		_, err := repo.Get(context.TODO())
+
+		assert.True(t, liberrors.IsNotFoundError(err))
+	})
+}
+
+func TestCachedDoguConfigRepository(t *testing.T) {
+	clientSet := fake.NewSimpleClientset(
+		newCacheTestConfigMap("global-config", globalConfigType, "", "fqdn: example.com\n"),
+		newCacheTestConfigMap("cas-config", doguConfigType, "cas", "logging: INFO\n"),
+		newCacheTestConfigMap("ldap-config", doguConfigType, "ldap", "logging: WARN\n"),
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: cacheTestNamespace, Labels: map[string]string{appLabelKey: appLabelValueCes}}},
+	)
+	configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+	repo := NewCachedDoguConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+	startSyncedCache(t, configCache)
+	assert.True(t, repo.HasSynced())
+	assert.Len(t, configCache.informer.GetStore().List(), 3)
+
+	t.Run("should get dogu config from cache", func(t *testing.T) {
+		doguConfig, err := repo.Get(context.TODO(), "cas")
+
+		require.NoError(t, err)
+		value, _ := doguConfig.Get("logging")
+		assert.Equal(t, config.Value("INFO"), value)
+	})
+
+	t.Run("should list dogu configs from cache", func(t *testing.T) {
+		result, err := repo.ListBySelector(context.TODO(), labels.SelectorFromSet(labels.Set{doguNameLabelKey: "ldap"}))
+
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		value, _ := result["ldap"].Get("logging")
+		assert.Equal(t, config.Value("WARN"), value)
+
+		all, err := repo.List(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("should return empty result for selector without matches", func(t *testing.T) {
+		result, err := repo.ListBySelector(context.TODO(), labels.Nothing())
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	assert.Equal(t, 0, countActions(clientSet, "get"))
+}
+
+func TestCachedDoguConfigRepository_WithConverter(t *testing.T) {
+	jsonConfigMap := newCacheTestConfigMap("cas-config", doguConfigType, "cas", "logging: INFO\n")
+	jsonConfigMap.Data["config.json"] = "{\"logging\": \"WARN\"}"
+	clientSet := fake.NewSimpleClientset(jsonConfigMap, newCacheTestConfigMap("ldap-config", doguConfigType, "ldap", "logging: INFO\n"))
+	configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+	repo := NewCachedDoguConfigRepository(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache, WithConverter(&config.JsonConverter{}))
+	startSyncedCache(t, configCache)
+
+	doguConfig, err := repo.Get(context.TODO(), "cas")
+	require.NoError(t, err)
+	value, _ := doguConfig.Get("logging")
+	assert.Equal(t, config.Value("WARN"), value)
+
+	_, err = repo.Get(context.TODO(), "ldap")
+	assert.True(t, liberrors.IsNotFoundError(err))
+	assert.ErrorContains(t, err, "could not find data for key config.json")
+}
+
+func TestCachedDoguConfigRepository_WithSchemaValidation(t *testing.T) {
+	mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+	configCache := NewConfigMapCache(NewMockConfigMapClient(t))
+
+	repo := NewCachedDoguConfigRepository(NewMockConfigMapClient(t), configCache, WithSchemaValidation(mDescriptorGetter))
+	assert.Equal(t, mDescriptorGetter, repo.descriptorGetter)
+	assert.Equal(t, config.NewDoguConfigValidator(), repo.validator)
+
+	sensitiveRepo := NewCachedSensitiveDoguConfigRepository(NewMockSecretClient(t), NewSecretCache(NewMockSecretClient(t)), WithSchemaValidation(mDescriptorGetter))
+	assert.Equal(t, mDescriptorGetter, sensitiveRepo.descriptorGetter)
+	assert.Equal(t, config.NewSensitiveDoguConfigValidator(), sensitiveRepo.validator)
+}
+
+func TestCachedSensitiveDoguConfigRepository(t *testing.T) {
+	clientSet := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cas-config",
+			Namespace: cacheTestNamespace,
+			Labels:    map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: sensitiveConfigType.String(), doguNameLabelKey: "cas"},
+		},
+		Data: map[string][]byte{dataKeyName: []byte("password: secret\n")},
+	})
+	configCache := NewSecretCache(clientSet.CoreV1().Secrets(cacheTestNamespace))
+	repo := NewCachedSensitiveDoguConfigRepository(clientSet.CoreV1().Secrets(cacheTestNamespace), configCache)
+	startSyncedCache(t, configCache)
+
+	doguConfig, err := repo.Get(context.TODO(), "cas")
+
+	require.NoError(t, err)
+	value, _ := doguConfig.Get("password")
+	assert.Equal(t, config.Value("secret"), value)
+	assert.Equal(t, 0, countActions(clientSet, "get"))
+}
+
+func TestCachedConfigClient_getFromCache(t *testing.T) {
+	t.Run("should return not found for missing data", func(t *testing.T) {
+		cm := newCacheTestConfigMap("cas-config", doguConfigType, "cas", "")
+		delete(cm.Data, dataKeyName)
+		clientSet := fake.NewSimpleClientset(cm)
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		startSyncedCache(t, configCache)
+
+		client := newCachedConfigMapClient(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), doguConfigType, dataKeyName, configCache)
+		_, _, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+
+		assert.True(t, liberrors.IsNotFoundError(err))
+		assert.ErrorContains(t, err, "could not find data for key config.yaml")
+	})
+
+	t.Run("should copy cached objects", func(t *testing.T) {
+		clientSet := fake.NewSimpleClientset(newCacheTestConfigMap("cas-config", doguConfigType, "cas", "key: value"))
+		configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+		startSyncedCache(t, configCache)
+
+		client := newCachedConfigMapClient(clientSet.CoreV1().ConfigMaps(cacheTestNamespace), doguConfigType, dataKeyName, configCache)
+		cd, _, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+		require.NoError(t, err)
+		cd.rawData.(*v1.ConfigMap).Data[dataKeyName] = "changed"
+
+		cd, _, err = client.GetWithListResourceVersion(context.TODO(), "cas-config")
+		require.NoError(t, err)
+		assert.Equal(t, "key: value", cd.dataStr)
+	})
+}
+
+func TestCachedMaintenanceModeAdapter_LaggingCache(t *testing.T) {
+	clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+	client := clientSet.CoreV1().ConfigMaps(cacheTestNamespace)
+	configCache := NewConfigMapCache(client)
+	sut := NewCachedMaintenanceModeAdapter("k8s-blueprint-operator", client, configCache)
+	startLaggingCache(t, configCache)
+
+	cm, err := client.Get(context.TODO(), "global-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data[dataKeyName] = "fqdn: other.example.com\n"
+	_, err = client.Update(context.TODO(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = sut.Activate(context.TODO(), MaintenanceModeDescription{Title: "title", Text: "text"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, countActions(clientSet, "update"))
+	cm, err = client.Get(context.TODO(), "global-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "3", cm.ResourceVersion)
+	assert.Equal(t, "fqdn: other.example.com\nmaintenance: '{\"title\":\"title\",\"text\":\"text\",\"holder\":\"k8s-blueprint-operator\"}'\n", cm.Data[dataKeyName])
+}
+
+func TestNewCachedMaintenanceModeAdapter(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+	configCache := NewConfigMapCache(clientSet.CoreV1().ConfigMaps(cacheTestNamespace))
+
+	adapter := NewCachedMaintenanceModeAdapter("owner", clientSet.CoreV1().ConfigMaps(cacheTestNamespace), configCache)
+
+	assert.Equal(t, "owner", adapter.owner)
+	_, isCached := adapter.globalConfigRepo.generalConfigRepository.(configRepository).client.(cachedConfigClient)
+	assert.True(t, isCached)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	v1 "k8s.io/api/core/v1"
 	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
@@ -47,6 +48,7 @@ const (
 	doguNameLabelKey = "dogu.name"
 )
 
+// dataKeyName is the key of the config data for converters without a config.DataKeyProvider.
 const dataKeyName = "config.yaml"
 
 type ConfigMapClient interface {
@@ -59,15 +61,17 @@ type clientData struct {
 }
 
 type configMapClient struct {
-	client ConfigMapClient
-	labels labels.Set
+	client  ConfigMapClient
+	labels  labels.Set
+	dataKey string
 }
 
 var _ configClient = configMapClient{}
 
-func createConfigMapClient(c ConfigMapClient, t configType) configMapClient {
+func createConfigMapClient(c ConfigMapClient, t configType, dataKey string) configMapClient {
 	return configMapClient{
-		client: c,
+		client:  c,
+		dataKey: dataKey,
 		labels: labels.Set{
 			appLabelKey:  appLabelValueCes,
 			typeLabelKey: t.String(),
@@ -101,9 +105,9 @@ func (cmc configMapClient) Get(ctx context.Context, name string) (clientData, er
 		return clientData{}, fmt.Errorf("unable to get config-map from cluster: %w", handleError(err))
 	}
 
-	dataStr, ok := cm.Data[dataKeyName]
+	dataStr, ok := cm.Data[cmc.dataKey]
 	if !ok {
-		return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", dataKeyName))
+		return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", cmc.dataKey))
 	}
 
 	return clientData{
@@ -113,7 +117,8 @@ func (cmc configMapClient) Get(ctx context.Context, name string) (clientData, er
 }
 
 // GetWithListResourceVersion gets a list of configmaps containing a single item. This is used for the config-watches, because they
-// are operating on lists instead of single objects.
+// are operating on lists instead of single objects. The resource version of the list is also returned with a not found
+// error, so that a watch can wait for the creation of the config.
 func (cmc configMapClient) GetWithListResourceVersion(ctx context.Context, name string) (clientData, string, error) {
 	list, err := cmc.client.List(ctx, metav1.SingleObject(metav1.ObjectMeta{Name: name}))
 	if err != nil {
@@ -121,13 +126,13 @@ func (cmc configMapClient) GetWithListResourceVersion(ctx context.Context, name
 	}
 
 	if len(list.Items) == 0 {
-		return clientData{}, "", errors.NewNotFoundError(fmt.Errorf("could not find a configmap with the given name: %s", name))
+		return clientData{}, list.ResourceVersion, errors.NewNotFoundError(fmt.Errorf("could not find a configmap with the given name: %s", name))
 	}
 
-	configMap := list.Items[0]
-	dataStr, ok := configMap.Data[dataKeyName]
+	configMap := &list.Items[0]
+	dataStr, ok := configMap.Data[cmc.dataKey]
 	if !ok {
-		return clientData{}, "", errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", dataKeyName))
+		return clientData{}, list.ResourceVersion, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", cmc.dataKey))
 	}
 
 	return clientData{
@@ -136,6 +141,36 @@ func (cmc configMapClient) GetWithListResourceVersion(ctx context.Context, name
 	}, list.ResourceVersion, nil
 }
 
+// List gets all configmaps of the config type of the client that match the given selector in a single call.
+// Configmaps without config data are skipped.
+func (cmc configMapClient) List(ctx context.Context, selector labels.Selector) ([]clientData, string, error) {
+	clientSelector, selectable := listSelector(cmc.labels, selector)
+	if !selectable {
+		return nil, "", nil
+	}
+
+	list, err := cmc.client.List(ctx, metav1.ListOptions{LabelSelector: clientSelector.String()})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to list config-maps from cluster: %w", handleError(err))
+	}
+
+	result := make([]clientData, 0, len(list.Items))
+	for i := range list.Items {
+		configMap := &list.Items[i]
+		dataStr, ok := configMap.Data[cmc.dataKey]
+		if !ok {
+			continue
+		}
+
+		result = append(result, clientData{
+			dataStr: dataStr,
+			rawData: configMap,
+		})
+	}
+
+	return result, list.ResourceVersion, nil
+}
+
 func (cmc configMapClient) Delete(ctx context.Context, name string) error {
 	if err := cmc.client.Delete(ctx, name, metav1.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
 		return fmt.Errorf("could not delete config-map in cluster: %w", handleError(err))
@@ -145,18 +180,22 @@ func (cmc configMapClient) Delete(ctx context.Context, name string) error {
 }
 
 func (cmc configMapClient) createConfigMap(pCtx string, name string, doguName string, dataStr string) *v1.ConfigMap {
+	objectLabels := maps.Clone(cmc.labels)
+	if objectLabels == nil {
+		objectLabels = make(labels.Set)
+	}
 	if doguName != "" {
-		cmc.labels[doguNameLabelKey] = doguName
+		objectLabels[doguNameLabelKey] = doguName
 	}
 
 	configMap := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
-			Labels:          cmc.labels,
+			Labels:          objectLabels,
 			ResourceVersion: pCtx,
 		},
 		Data: map[string]string{
-			dataKeyName: dataStr,
+			cmc.dataKey: dataStr,
 		},
 	}
 
@@ -191,7 +230,7 @@ func (cmc configMapClient) UpdateClientData(ctx context.Context, update clientDa
 		return nil, fmt.Errorf("configData cannot be used as configMap")
 	}
 
-	cm.Data[dataKeyName] = update.dataStr
+	cm.Data[cmc.dataKey] = update.dataStr
 
 	cm, err := cmc.client.Update(ctx, cm, metav1.UpdateOptions{})
 	if err != nil {
@@ -202,7 +241,16 @@ func (cmc configMapClient) UpdateClientData(ctx context.Context, update clientDa
 }
 
 func (cmc configMapClient) Watch(ctx context.Context, name string, resourceVersion string) (<-chan clientWatchResult, error) {
-	return watchWithClient(ctx, cmc.client, name, resourceVersion)
+	return watchWithClient(ctx, cmc.client, cmc.dataKey, name, resourceVersion)
+}
+
+func (cmc configMapClient) WatchAll(ctx context.Context, selector labels.Selector, resourceVersion string) (<-chan clientWatchResult, error) {
+	clientSelector, selectable := listSelector(cmc.labels, selector)
+	if !selectable {
+		return emptyWatch(ctx), nil
+	}
+
+	return watchAllWithClient(ctx, cmc.client, cmc.dataKey, clientSelector, resourceVersion)
 }
 
 type SecretClient interface {
@@ -210,15 +258,17 @@ type SecretClient interface {
 }
 
 type secretClient struct {
-	client SecretClient
-	labels labels.Set
+	client  SecretClient
+	labels  labels.Set
+	dataKey string
 }
 
 var _ configClient = secretClient{}
 
-func createSecretClient(c SecretClient, t configType) secretClient {
+func createSecretClient(c SecretClient, t configType, dataKey string) secretClient {
 	return secretClient{
-		client: c,
+		client:  c,
+		dataKey: dataKey,
 		labels: labels.Set{
 			appLabelKey:  appLabelValueCes,
 			typeLabelKey: t.String(),
@@ -232,9 +282,9 @@ func (sc secretClient) Get(ctx context.Context, name string) (clientData, error)
 		return clientData{}, fmt.Errorf("unable to get secret from cluster: %w", handleError(err))
 	}
 
-	dataBytes, ok := secret.Data[dataKeyName]
+	dataBytes, ok := secret.Data[sc.dataKey]
 	if !ok {
-		return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", dataKeyName))
+		return clientData{}, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", sc.dataKey))
 	}
 
 	return clientData{
@@ -244,7 +294,8 @@ func (sc secretClient) Get(ctx context.Context, name string) (clientData, error)
 }
 
 // GetWithListResourceVersion gets a list of secrets containing a single item. This is used for the config-watches, because they
-// are operating on lists instead of single objects.
+// are operating on lists instead of single objects. The resource version of the list is also returned with a not found
+// error, so that a watch can wait for the creation of the config.
 func (sc secretClient) GetWithListResourceVersion(ctx context.Context, name string) (clientData, string, error) {
 	list, err := sc.client.List(ctx, metav1.SingleObject(metav1.ObjectMeta{Name: name}))
 	if err != nil {
@@ -252,13 +303,13 @@ func (sc secretClient) GetWithListResourceVersion(ctx context.Context, name stri
 	}
 
 	if len(list.Items) == 0 {
-		return clientData{}, "", errors.NewNotFoundError(fmt.Errorf("could not find a configmap with the given name %s", name))
+		return clientData{}, list.ResourceVersion, errors.NewNotFoundError(fmt.Errorf("could not find a configmap with the given name %s", name))
 	}
 
-	secret := list.Items[0]
-	dataBytes, ok := secret.Data[dataKeyName]
+	secret := &list.Items[0]
+	dataBytes, ok := secret.Data[sc.dataKey]
 	if !ok {
-		return clientData{}, "", errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", dataKeyName))
+		return clientData{}, list.ResourceVersion, errors.NewNotFoundError(fmt.Errorf("could not find data for key %s", sc.dataKey))
 	}
 
 	return clientData{
@@ -267,6 +318,36 @@ func (sc secretClient) GetWithListResourceVersion(ctx context.Context, name stri
 	}, list.ResourceVersion, nil
 }
 
+// List gets all secrets of the config type of the client that match the given selector in a single call.
+// Secrets without config data are skipped.
+func (sc secretClient) List(ctx context.Context, selector labels.Selector) ([]clientData, string, error) {
+	clientSelector, selectable := listSelector(sc.labels, selector)
+	if !selectable {
+		return nil, "", nil
+	}
+
+	list, err := sc.client.List(ctx, metav1.ListOptions{LabelSelector: clientSelector.String()})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to list secrets from cluster: %w", handleError(err))
+	}
+
+	result := make([]clientData, 0, len(list.Items))
+	for i := range list.Items {
+		secret := &list.Items[i]
+		dataBytes, ok := secret.Data[sc.dataKey]
+		if !ok {
+			continue
+		}
+
+		result = append(result, clientData{
+			dataStr: string(dataBytes),
+			rawData: secret,
+		})
+	}
+
+	return result, list.ResourceVersion, nil
+}
+
 func (sc secretClient) Delete(ctx context.Context, name string) error {
 	if err := sc.client.Delete(ctx, name, metav1.DeleteOptions{}); client.IgnoreNotFound(err) != nil {
 		return fmt.Errorf("could not delete secret in cluster: %w", handleError(err))
@@ -276,18 +357,22 @@ func (sc secretClient) Delete(ctx context.Context, name string) error {
 }
 
 func (sc secretClient) createSecret(pCtx string, name string, doguName string, dataStr string) *v1.Secret {
+	objectLabels := maps.Clone(sc.labels)
+	if objectLabels == nil {
+		objectLabels = make(labels.Set)
+	}
 	if doguName != "" {
-		sc.labels[doguNameLabelKey] = doguName
+		objectLabels[doguNameLabelKey] = doguName
 	}
 
 	secret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
-			Labels:          sc.labels,
+			Labels:          objectLabels,
 			ResourceVersion: pCtx,
 		},
 		StringData: map[string]string{
-			dataKeyName: dataStr,
+			sc.dataKey: dataStr,
 		},
 	}
 
@@ -323,7 +408,7 @@ func (sc secretClient) UpdateClientData(ctx context.Context, update clientData)
 	}
 
 	secret.StringData = map[string]string{
-		dataKeyName: update.dataStr,
+		sc.dataKey: update.dataStr,
 	}
 
 	resource, err := sc.client.Update(ctx, secret, metav1.UpdateOptions{})
@@ -335,7 +420,49 @@ func (sc secretClient) UpdateClientData(ctx context.Context, update clientData)
 }
 
 func (sc secretClient) Watch(ctx context.Context, name string, resourceVersion string) (<-chan clientWatchResult, error) {
-	return watchWithClient(ctx, sc.client, name, resourceVersion)
+	return watchWithClient(ctx, sc.client, sc.dataKey, name, resourceVersion)
+}
+
+func (sc secretClient) WatchAll(ctx context.Context, selector labels.Selector, resourceVersion string) (<-chan clientWatchResult, error) {
+	clientSelector, selectable := listSelector(sc.labels, selector)
+	if !selectable {
+		return emptyWatch(ctx), nil
+	}
+
+	return watchAllWithClient(ctx, sc.client, sc.dataKey, clientSelector, resourceVersion)
+}
+
+// listSelector restricts the given selector to the objects with the labels of the client, i.e. the config type.
+// It returns false if the selector cannot match any object, e.g. labels.Nothing(). The restricted selector must not
+// be used then, because it would match all objects of the client.
+func listSelector(clientLabels labels.Set, selector labels.Selector) (labels.Selector, bool) {
+	result := labels.SelectorFromSet(labels.Set{
+		appLabelKey:  clientLabels[appLabelKey],
+		typeLabelKey: clientLabels[typeLabelKey],
+	})
+
+	if selector == nil {
+		return result, true
+	}
+
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return labels.Nothing(), false
+	}
+
+	return result.Add(requirements...), true
+}
+
+// emptyWatch returns the results of a watch for a selector that cannot match any object. The channel is closed as
+// soon as the context is done.
+func emptyWatch(ctx context.Context) <-chan clientWatchResult {
+	resultChan := make(chan clientWatchResult)
+	go func() {
+		<-ctx.Done()
+		close(resultChan)
+	}()
+
+	return resultChan
 }
 
 type clientWatcher interface {
@@ -345,13 +472,33 @@ type clientWatcher interface {
 type clientWatchResult struct {
 	dataStr           string
 	persistentContext string
-	err               error
+	// eventType and doguName are only set for events of config objects
+	eventType watch.EventType
+	doguName  string
+	err       error
+}
+
+func watchWithClient(ctx context.Context, client clientWatcher, dataKey, name, initialResourceVersion string) (<-chan clientWatchResult, error) {
+	nameSelector := func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+
+	return watchWithListOptions(ctx, client, dataKey, name, initialResourceVersion, nameSelector)
 }
 
-func watchWithClient(ctx context.Context, client clientWatcher, name, initialResourceVersion string) (<-chan clientWatchResult, error) {
+// watchAllWithClient watches all objects matching the given label selector with a single watch.
+func watchAllWithClient(ctx context.Context, client clientWatcher, dataKey string, selector labels.Selector, initialResourceVersion string) (<-chan clientWatchResult, error) {
+	labelSelector := func(options *metav1.ListOptions) {
+		options.LabelSelector = selector.String()
+	}
+
+	return watchWithListOptions(ctx, client, dataKey, selector.String(), initialResourceVersion, labelSelector)
+}
+
+func watchWithListOptions(ctx context.Context, client clientWatcher, dataKey, description, initialResourceVersion string, applyOptions func(options *metav1.ListOptions)) (<-chan clientWatchResult, error) {
 	logger := log.FromContext(ctx).WithName("watchWithClient")
 
-	watcher, err := createRetryWatcher(ctx, client, name, initialResourceVersion)
+	watcher, err := createRetryWatcher(ctx, client, description, initialResourceVersion, applyOptions)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create retry watcher: %w", err)
 	}
@@ -367,13 +514,13 @@ func watchWithClient(ctx context.Context, client clientWatcher, name, initialRes
 				return
 			case event, open := <-watcher.ResultChan():
 				if !open {
-					logger.Info(fmt.Sprintf("watch for %q closed", name))
+					logger.Info(fmt.Sprintf("watch for %q closed", description))
 
 					return
 				}
 
 				var result clientWatchResult
-				result = handleWatchEvent(name, event)
+				result = handleWatchEvent(description, dataKey, event)
 				resultChan <- result
 			}
 		}
@@ -382,9 +529,9 @@ func watchWithClient(ctx context.Context, client clientWatcher, name, initialRes
 	return resultChan, nil
 }
 
-func createRetryWatcher(ctx context.Context, client clientWatcher, name, initialResourceVersion string) (*toolsWatch.RetryWatcher, error) {
+func createRetryWatcher(ctx context.Context, client clientWatcher, description, initialResourceVersion string, applyOptions func(options *metav1.ListOptions)) (*toolsWatch.RetryWatcher, error) {
 	watchFunc := func(options metav1.ListOptions) (watch.Interface, error) {
-		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		applyOptions(&options)
 		watchInterface, err := client.Watch(ctx, options)
 		if err != nil {
 			return nil, handleError(err)
@@ -394,13 +541,13 @@ func createRetryWatcher(ctx context.Context, client clientWatcher, name, initial
 	}
 	watcher, err := toolsWatch.NewRetryWatcher(initialResourceVersion, &cache.ListWatch{WatchFunc: watchFunc})
 	if err != nil {
-		return nil, fmt.Errorf("could not watch '%s' in cluster: %w", name, handleError(err))
+		return nil, fmt.Errorf("could not watch '%s' in cluster: %w", description, handleError(err))
 	}
 
 	return watcher, nil
 }
 
-func handleWatchEvent(cfgName string, event watch.Event) clientWatchResult {
+func handleWatchEvent(cfgName, dataKey string, event watch.Event) clientWatchResult {
 	if event.Type == watch.Error {
 		var err error
 		status, ok := event.Object.(*metav1.Status)
@@ -419,33 +566,38 @@ func handleWatchEvent(cfgName string, event watch.Event) clientWatchResult {
 
 	switch r := event.Object.(type) {
 	case *v1.Secret:
-		dataBytes, ok := r.Data[dataKeyName]
-		if !ok {
+		dataBytes, ok := r.Data[dataKey]
+		// deleted configs are reported without data
+		if !ok && event.Type != watch.Deleted {
 			return clientWatchResult{
 				dataStr:           "",
 				persistentContext: "",
-				err:               errors.NewNotFoundError(fmt.Errorf("could not find data for key %s in secret %s", dataKeyName, cfgName)),
+				err:               errors.NewNotFoundError(fmt.Errorf("could not find data for key %s in secret %s", dataKey, cfgName)),
 			}
 		}
 
 		return clientWatchResult{
 			dataStr:           string(dataBytes),
 			persistentContext: r.GetResourceVersion(),
+			eventType:         event.Type,
+			doguName:          getDoguName(r).String(),
 			err:               nil,
 		}
 	case *v1.ConfigMap:
-		dataString, ok := r.Data[dataKeyName]
-		if !ok {
+		dataString, ok := r.Data[dataKey]
+		if !ok && event.Type != watch.Deleted {
 			return clientWatchResult{
 				dataStr:           "",
 				persistentContext: "",
-				err:               errors.NewNotFoundError(fmt.Errorf("could not find data for key %s in configmap %s", dataKeyName, cfgName)),
+				err:               errors.NewNotFoundError(fmt.Errorf("could not find data for key %s in configmap %s", dataKey, cfgName)),
 			}
 		}
 
 		return clientWatchResult{
 			dataStr:           dataString,
 			persistentContext: r.GetResourceVersion(),
+			eventType:         event.Type,
+			doguName:          getDoguName(r).String(),
 			err:               nil,
 		}
 	default:
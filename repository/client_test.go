@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
 	"testing"
 	"time"
 )
@@ -61,13 +62,14 @@ func TestCreateConfigMapClient(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			c := createConfigMapClient(tc.m, tc.in)
+			c := createConfigMapClient(tc.m, tc.in, dataKeyName)
 
 			assert.NotNil(t, c)
 			assert.NotNil(t, c.client)
 
 			assert.Equal(t, appLabelValueCes, c.labels.Get(appLabelKey))
 			assert.Equal(t, tc.in.String(), c.labels.Get(typeLabelKey))
+			assert.Equal(t, dataKeyName, c.dataKey)
 		})
 	}
 
@@ -84,13 +86,14 @@ func TestCreateSecretClient(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			c := createSecretClient(tc.m, tc.in)
+			c := createSecretClient(tc.m, tc.in, dataKeyName)
 
 			assert.NotNil(t, c)
 			assert.NotNil(t, c.client)
 
 			assert.Equal(t, appLabelValueCes, c.labels.Get(appLabelKey))
 			assert.Equal(t, tc.in.String(), c.labels.Get(typeLabelKey))
+			assert.Equal(t, dataKeyName, c.dataKey)
 		})
 	}
 
@@ -99,8 +102,9 @@ func TestCreateSecretClient(t *testing.T) {
 func TestConfigMapClient_createConfigMap(t *testing.T) {
 	t.Run("create with doguName", func(t *testing.T) {
 		client := configMapClient{
-			client: nil,
-			labels: make(labels.Set),
+			dataKey: dataKeyName,
+			client:  nil,
+			labels:  make(labels.Set),
 		}
 
 		cm := client.createConfigMap(resourceVersion, "test-config", "test", "testValue")
@@ -112,8 +116,9 @@ func TestConfigMapClient_createConfigMap(t *testing.T) {
 
 	t.Run("create without doguName", func(t *testing.T) {
 		client := configMapClient{
-			client: nil,
-			labels: make(labels.Set),
+			dataKey: dataKeyName,
+			client:  nil,
+			labels:  make(labels.Set),
 		}
 
 		cm := client.createConfigMap(resourceVersion, "test-config", "", "testValue")
@@ -180,7 +185,8 @@ func TestConfigMapClient_Get(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			_, err := client.Get(context.TODO(), "")
@@ -265,7 +271,8 @@ func TestConfigmapClient_GetWithListResourceVersion(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			_, resourceVersion, err := client.GetWithListResourceVersion(context.TODO(), "")
@@ -277,6 +284,39 @@ func TestConfigmapClient_GetWithListResourceVersion(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("should return list resource version with not found error", func(t *testing.T) {
+		m := NewMockConfigMapClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(&v1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: "42"}}, nil)
+
+		client := configMapClient{
+			dataKey: dataKeyName,
+			client:  m,
+		}
+
+		_, resourceVersion, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+
+		assert.True(t, liberrors.IsNotFoundError(err))
+		assert.Equal(t, "42", resourceVersion)
+	})
+
+	t.Run("should keep resource version of item for optimistic locking", func(t *testing.T) {
+		m := NewMockConfigMapClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(&v1.ConfigMapList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "42"},
+			Items:    []v1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "7"}, Data: map[string]string{dataKeyName: "key: value"}}},
+		}, nil)
+
+		client := configMapClient{
+			dataKey: dataKeyName,
+			client:  m,
+		}
+
+		cd, _, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+
+		require.NoError(t, err)
+		assert.Equal(t, "7", getPersistentContext(cd.rawData))
+	})
 }
 
 func TestConfigMapClient_Delete(t *testing.T) {
@@ -319,7 +359,8 @@ func TestConfigMapClient_Delete(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			err := client.Delete(context.TODO(), "")
@@ -364,7 +405,8 @@ func TestConfigMapClient_Create(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			cm, err := client.Create(context.TODO(), "", "", "")
@@ -415,7 +457,8 @@ func TestConfigMapClient_Update(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			cm, err := client.Update(context.TODO(), "", "", "", "")
@@ -489,7 +532,8 @@ func TestConfigMapClient_UpdateClientData(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := configMapClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			cm, err := client.UpdateClientData(context.TODO(), tc.cd)
@@ -506,8 +550,9 @@ func TestConfigMapClient_UpdateClientData(t *testing.T) {
 func TestSecretClient_createSecret(t *testing.T) {
 	t.Run("create with doguName", func(t *testing.T) {
 		client := secretClient{
-			client: nil,
-			labels: make(labels.Set),
+			dataKey: dataKeyName,
+			client:  nil,
+			labels:  make(labels.Set),
 		}
 
 		s := client.createSecret(resourceVersion, "test-config", "test", "testValue")
@@ -519,8 +564,9 @@ func TestSecretClient_createSecret(t *testing.T) {
 
 	t.Run("create without doguName", func(t *testing.T) {
 		client := secretClient{
-			client: nil,
-			labels: make(labels.Set),
+			dataKey: dataKeyName,
+			client:  nil,
+			labels:  make(labels.Set),
 		}
 
 		s := client.createSecret(resourceVersion, "test-config", "", "testValue")
@@ -587,7 +633,8 @@ func TestSecretClient_Get(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			_, err := client.Get(context.TODO(), "")
@@ -672,7 +719,8 @@ func TestSecretClient_GetWithListResourceVersion(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			_, resourceVersion, err := client.GetWithListResourceVersion(context.TODO(), "")
@@ -684,6 +732,39 @@ func TestSecretClient_GetWithListResourceVersion(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("should return list resource version with not found error", func(t *testing.T) {
+		m := NewMockSecretClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(&v1.SecretList{ListMeta: metav1.ListMeta{ResourceVersion: "42"}}, nil)
+
+		client := secretClient{
+			dataKey: dataKeyName,
+			client:  m,
+		}
+
+		_, resourceVersion, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+
+		assert.True(t, liberrors.IsNotFoundError(err))
+		assert.Equal(t, "42", resourceVersion)
+	})
+
+	t.Run("should keep resource version of item for optimistic locking", func(t *testing.T) {
+		m := NewMockSecretClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(&v1.SecretList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "42"},
+			Items:    []v1.Secret{{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "7"}, Data: map[string][]byte{dataKeyName: []byte("key: value")}}},
+		}, nil)
+
+		client := secretClient{
+			dataKey: dataKeyName,
+			client:  m,
+		}
+
+		cd, _, err := client.GetWithListResourceVersion(context.TODO(), "cas-config")
+
+		require.NoError(t, err)
+		assert.Equal(t, "7", getPersistentContext(cd.rawData))
+	})
 }
 
 func TestSecretClient_Delete(t *testing.T) {
@@ -726,7 +807,8 @@ func TestSecretClient_Delete(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			err := client.Delete(context.TODO(), "")
@@ -771,7 +853,8 @@ func TestSecretClient_Create(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			s, err := client.Create(context.TODO(), "", "", "")
@@ -822,7 +905,8 @@ func TestSecretClient_Update(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			cm, err := client.Update(context.TODO(), "", "", "", "")
@@ -891,7 +975,8 @@ func TestSecretClient_UpdateClientData(t *testing.T) {
 			applyTestCase(m, tc.tc)
 
 			client := secretClient{
-				client: m,
+				dataKey: dataKeyName,
+				client:  m,
 			}
 
 			s, err := client.UpdateClientData(context.TODO(), tc.cd)
@@ -917,7 +1002,7 @@ func Test_watchWithClient(t *testing.T) {
 		mockWatcher := newMockClientWatcher(t)
 		mockWatcher.EXPECT().Watch(ctx, listOptions).Return(fakeWatcher, nil)
 
-		watchChan, err := watchWithClient(ctx, mockWatcher, "dogu-config", resourceVersion)
+		watchChan, err := watchWithClient(ctx, mockWatcher, dataKeyName, "dogu-config", resourceVersion)
 		require.NoError(t, err)
 		require.NotNil(t, watchChan)
 
@@ -978,7 +1063,7 @@ func Test_watchWithClient(t *testing.T) {
 		mockWatcher := newMockClientWatcher(t)
 		mockWatcher.EXPECT().Watch(ctx, listOptions).Return(fakeWatcher, nil)
 
-		watchChan, err := watchWithClient(ctx, mockWatcher, "dogu-config", resourceVersion)
+		watchChan, err := watchWithClient(ctx, mockWatcher, dataKeyName, "dogu-config", resourceVersion)
 		require.NoError(t, err)
 		require.NotNil(t, watchChan)
 
@@ -1029,7 +1114,7 @@ func Test_watchWithClient(t *testing.T) {
 		mockWatcher := newMockClientWatcher(t)
 		mockWatcher.EXPECT().Watch(ctx, listOptions).Return(fakeWatcher, nil)
 
-		watchChan, err := watchWithClient(ctx, mockWatcher, "dogu-config", resourceVersion)
+		watchChan, err := watchWithClient(ctx, mockWatcher, dataKeyName, "dogu-config", resourceVersion)
 		require.NoError(t, err)
 		require.NotNil(t, watchChan)
 
@@ -1075,7 +1160,7 @@ func Test_watchWithClient(t *testing.T) {
 		mockWatcher := newMockClientWatcher(t)
 		mockWatcher.EXPECT().Watch(cancelCtx, listOptions).Return(fakeWatcher, nil)
 
-		watchChan, err := watchWithClient(cancelCtx, mockWatcher, "dogu-config", resourceVersion)
+		watchChan, err := watchWithClient(cancelCtx, mockWatcher, dataKeyName, "dogu-config", resourceVersion)
 		require.NoError(t, err)
 		require.NotNil(t, watchChan)
 
@@ -1101,7 +1186,7 @@ func Test_watchWithClient(t *testing.T) {
 		ctx := context.Background()
 		mockWatcher := newMockClientWatcher(t)
 
-		_, err := watchWithClient(ctx, mockWatcher, "dogu-config", "")
+		_, err := watchWithClient(ctx, mockWatcher, dataKeyName, "dogu-config", "")
 
 		require.Error(t, err)
 		assert.ErrorContains(t, err, "could not watch 'dogu-config' in cluster:")
@@ -1117,7 +1202,7 @@ func Test_handleWatchEvent(t *testing.T) {
 		}
 
 		//when
-		watchResult := handleWatchEvent("testName", event)
+		watchResult := handleWatchEvent("testName", dataKeyName, event)
 
 		//then
 		assert.Empty(t, watchResult.dataStr)
@@ -1134,7 +1219,7 @@ func Test_handleWatchEvent(t *testing.T) {
 		}
 
 		//when
-		watchResult := handleWatchEvent("testName", event)
+		watchResult := handleWatchEvent("testName", dataKeyName, event)
 
 		//then
 		assert.Empty(t, watchResult.dataStr)
@@ -1142,6 +1227,48 @@ func Test_handleWatchEvent(t *testing.T) {
 		assert.Error(t, watchResult.err)
 		assert.ErrorContains(t, watchResult.err, "error result in watcher for config 'testName'")
 	})
+	t.Run("should report deleted config map without data", func(t *testing.T) {
+		//given
+		event := watch.Event{
+			Type:   watch.Deleted,
+			Object: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cas-config", ResourceVersion: "3"}},
+		}
+
+		//when
+		watchResult := handleWatchEvent("cas-config", dataKeyName, event)
+
+		//then
+		require.NoError(t, watchResult.err)
+		assert.Equal(t, watch.Deleted, watchResult.eventType)
+		assert.Equal(t, "3", watchResult.persistentContext)
+	})
+	t.Run("should report deleted secret without data", func(t *testing.T) {
+		//given
+		event := watch.Event{
+			Type:   watch.Deleted,
+			Object: &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cas-config", ResourceVersion: "3"}},
+		}
+
+		//when
+		watchResult := handleWatchEvent("cas-config", dataKeyName, event)
+
+		//then
+		require.NoError(t, watchResult.err)
+		assert.Equal(t, watch.Deleted, watchResult.eventType)
+	})
+	t.Run("should return not found error for modified config map without data", func(t *testing.T) {
+		//given
+		event := watch.Event{
+			Type:   watch.Modified,
+			Object: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cas-config"}},
+		}
+
+		//when
+		watchResult := handleWatchEvent("cas-config", dataKeyName, event)
+
+		//then
+		assert.True(t, liberrors.IsNotFoundError(watchResult.err))
+	})
 }
 
 func Test_secretClient_Watch(t *testing.T) {
@@ -1150,7 +1277,8 @@ func Test_secretClient_Watch(t *testing.T) {
 	t.Run("should return error for error when starting watch", func(t *testing.T) {
 		mockClient := NewMockSecretClient(t)
 		client := secretClient{
-			client: mockClient,
+			dataKey: dataKeyName,
+			client:  mockClient,
 		}
 
 		_, err := client.Watch(ctx, "dogu-config", "")
@@ -1166,7 +1294,8 @@ func Test_configMapClient_Watch(t *testing.T) {
 	t.Run("should return error for error when starting watch", func(t *testing.T) {
 		mockClient := NewMockConfigMapClient(t)
 		client := configMapClient{
-			client: mockClient,
+			dataKey: dataKeyName,
+			client:  mockClient,
 		}
 
 		_, err := client.Watch(ctx, "dogu-config", "")
@@ -1221,3 +1350,216 @@ func Test_handleError(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigMapClient_createConfigMap_doesNotModifyClientLabels(t *testing.T) {
+	client := createConfigMapClient(nil, doguConfigType, dataKeyName)
+
+	client.createConfigMap("", "cas-config", "cas", "")
+
+	_, ok := client.labels[doguNameLabelKey]
+	assert.False(t, ok)
+}
+
+func TestConfigMapClient_List(t *testing.T) {
+	newConfigMap := func(name, configType, doguName string, data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ecosystem",
+				Labels:    map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: configType, doguNameLabelKey: doguName},
+			},
+			Data: data,
+		}
+	}
+
+	clientSet := fake.NewSimpleClientset(
+		newConfigMap("cas-config", doguConfigType.String(), "cas", map[string]string{dataKeyName: "cas"}),
+		newConfigMap("ldap-config", doguConfigType.String(), "ldap", map[string]string{dataKeyName: "ldap"}),
+		newConfigMap("empty-config", doguConfigType.String(), "empty", map[string]string{}),
+		newConfigMap("global-config", globalConfigType.String(), "", map[string]string{dataKeyName: "global"}),
+	)
+	client := createConfigMapClient(clientSet.CoreV1().ConfigMaps("ecosystem"), doguConfigType, dataKeyName)
+
+	t.Run("should list all configmaps of the config type with data", func(t *testing.T) {
+		result, _, err := client.List(context.TODO(), labels.Everything())
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.ElementsMatch(t, []string{"cas", "ldap"}, []string{result[0].dataStr, result[1].dataStr})
+	})
+
+	t.Run("should restrict list to selector", func(t *testing.T) {
+		result, _, err := client.List(context.TODO(), labels.SelectorFromSet(labels.Set{doguNameLabelKey: "ldap"}))
+
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "ldap", result[0].dataStr)
+		assert.Equal(t, "ldap-config", result[0].rawData.(*v1.ConfigMap).Name)
+	})
+
+	t.Run("should return empty result for selector without matches", func(t *testing.T) {
+		result, _, err := configMapClient{client: NewMockConfigMapClient(t), dataKey: dataKeyName}.List(context.TODO(), labels.Nothing())
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("should return error on list error", func(t *testing.T) {
+		m := NewMockConfigMapClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(nil, k8serrors.NewTimeoutError("timeout", 1))
+
+		_, _, err := configMapClient{client: m, dataKey: dataKeyName}.List(context.TODO(), nil)
+
+		assert.True(t, liberrors.IsConnectionError(err))
+		assert.ErrorContains(t, err, "unable to list config-maps from cluster")
+	})
+}
+
+func TestSecretClient_List(t *testing.T) {
+	newSecret := func(name, configType, doguName string, data map[string][]byte) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ecosystem",
+				Labels:    map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: configType, doguNameLabelKey: doguName},
+			},
+			Data: data,
+		}
+	}
+
+	clientSet := fake.NewSimpleClientset(
+		newSecret("cas-config", sensitiveConfigType.String(), "cas", map[string][]byte{dataKeyName: []byte("cas")}),
+		newSecret("empty-config", sensitiveConfigType.String(), "empty", map[string][]byte{}),
+		newSecret("other", "other-type", "other", map[string][]byte{dataKeyName: []byte("other")}),
+	)
+	client := createSecretClient(clientSet.CoreV1().Secrets("ecosystem"), sensitiveConfigType, dataKeyName)
+
+	t.Run("should list all secrets of the config type with data", func(t *testing.T) {
+		result, _, err := client.List(context.TODO(), nil)
+
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "cas", result[0].dataStr)
+		assert.Equal(t, "cas-config", result[0].rawData.(*v1.Secret).Name)
+	})
+
+	t.Run("should return empty result for selector without matches", func(t *testing.T) {
+		result, _, err := secretClient{client: NewMockSecretClient(t), dataKey: dataKeyName}.List(context.TODO(), labels.Nothing())
+
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("should return error on list error", func(t *testing.T) {
+		m := NewMockSecretClient(t)
+		m.EXPECT().List(mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, _, err := secretClient{client: m, dataKey: dataKeyName}.List(context.TODO(), nil)
+
+		assert.True(t, liberrors.IsGenericError(err))
+		assert.ErrorContains(t, err, "unable to list secrets from cluster")
+	})
+}
+
+func Test_listSelector(t *testing.T) {
+	clientLabels := labels.Set{appLabelKey: appLabelValueCes, typeLabelKey: doguConfigType.String()}
+
+	t.Run("should restrict selector to client labels", func(t *testing.T) {
+		selector, selectable := listSelector(clientLabels, labels.SelectorFromSet(labels.Set{doguNameLabelKey: "cas"}))
+
+		assert.True(t, selectable)
+		assert.Equal(t, "app=ces,dogu.name=cas,k8s.cloudogu.com/type=dogu-config", selector.String())
+	})
+
+	t.Run("should use client labels without selector", func(t *testing.T) {
+		selector, selectable := listSelector(clientLabels, nil)
+
+		assert.True(t, selectable)
+		assert.Equal(t, "app=ces,k8s.cloudogu.com/type=dogu-config", selector.String())
+	})
+
+	t.Run("should not be selectable for selector without matches", func(t *testing.T) {
+		selector, selectable := listSelector(clientLabels, labels.Nothing())
+
+		assert.False(t, selectable)
+		assert.False(t, selector.Matches(clientLabels))
+	})
+}
+
+func TestConfigMapClient_WatchAll(t *testing.T) {
+	t.Run("should not watch for selector without matches", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client := configMapClient{client: NewMockConfigMapClient(t), dataKey: dataKeyName}
+
+		watchChan, err := client.WatchAll(ctx, labels.Nothing(), resourceVersion)
+		require.NoError(t, err)
+		cancel()
+
+		_, open := <-watchChan
+		assert.False(t, open)
+	})
+}
+
+func Test_watchAllWithClient(t *testing.T) {
+	t.Run("should watch all objects matching the selector", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		fakeWatcher := watch.NewFake()
+		selector, _ := listSelector(labels.Set{appLabelKey: appLabelValueCes, typeLabelKey: doguConfigType.String()}, labels.Everything())
+
+		mockWatcher := newMockClientWatcher(t)
+		mockWatcher.EXPECT().Watch(ctx, metav1.ListOptions{
+			LabelSelector:       "app=ces,k8s.cloudogu.com/type=dogu-config",
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		}).Return(fakeWatcher, nil)
+
+		watchChan, err := watchAllWithClient(ctx, mockWatcher, dataKeyName, selector, resourceVersion)
+		require.NoError(t, err)
+
+		go func() {
+			fakeWatcher.Add(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cas-config", ResourceVersion: "2", Labels: map[string]string{doguNameLabelKey: "cas"}},
+				Data:       map[string]string{dataKeyName: "cas-data"},
+			})
+			fakeWatcher.Delete(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "ldap-config", ResourceVersion: "3"},
+				Data:       map[string]string{dataKeyName: "ldap-data"},
+			})
+		}()
+
+		result := <-watchChan
+		require.NoError(t, result.err)
+		assert.Equal(t, watch.Added, result.eventType)
+		assert.Equal(t, "cas", result.doguName)
+		assert.Equal(t, "cas-data", result.dataStr)
+		assert.Equal(t, "2", result.persistentContext)
+
+		result = <-watchChan
+		require.NoError(t, result.err)
+		assert.Equal(t, watch.Deleted, result.eventType)
+		assert.Equal(t, "ldap", result.doguName)
+	})
+}
+
+func Test_configMapClient_WatchAll(t *testing.T) {
+	mockClient := NewMockConfigMapClient(t)
+	mockClient.EXPECT().Watch(mock.Anything, mock.Anything).Return(watch.NewFake(), nil).Maybe()
+
+	client := createConfigMapClient(mockClient, doguConfigType, dataKeyName)
+	watchChan, err := client.WatchAll(context.TODO(), labels.Everything(), resourceVersion)
+
+	require.NoError(t, err)
+	assert.NotNil(t, watchChan)
+}
+
+func Test_secretClient_WatchAll(t *testing.T) {
+	mockClient := NewMockSecretClient(t)
+	mockClient.EXPECT().Watch(mock.Anything, mock.Anything).Return(watch.NewFake(), nil).Maybe()
+
+	client := createSecretClient(mockClient, sensitiveConfigType, dataKeyName)
+	watchChan, err := client.WatchAll(context.TODO(), labels.Everything(), resourceVersion)
+
+	require.NoError(t, err)
+	assert.NotNil(t, watchChan)
+}
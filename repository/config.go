@@ -5,6 +5,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
 	"reflect"
 	"strings"
 )
@@ -26,10 +30,11 @@ type configRepository struct {
 
 var _ generalConfigRepository = configRepository{}
 
-func newConfigRepo(client configClient) configRepository {
+func newConfigRepo(client configClient, opts ...ConfigRepositoryOption) configRepository {
+	options := applyConfigRepositoryOptions(opts)
 	cr := configRepository{
 		client:    client,
-		converter: &config.YamlConverter{},
+		converter: options.converter,
 	}
 
 	return cr
@@ -57,6 +62,46 @@ func (cr configRepository) get(ctx context.Context, name configName) (config.Con
 	return cfg, nil
 }
 
+func (cr configRepository) list(ctx context.Context, selector labels.Selector) (map[config.SimpleDoguName]config.Config, error) {
+	cds, listResourceVersion, err := cr.client.List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list data from cluster: %w", err)
+	}
+
+	result := make(map[config.SimpleDoguName]config.Config, len(cds))
+	for _, cd := range cds {
+		doguName := getDoguName(cd.rawData)
+
+		cfgData, lErr := cr.converter.Read(strings.NewReader(cd.dataStr))
+		if lErr != nil {
+			return nil, fmt.Errorf("could not convert client data of dogu %s to config data: %w", doguName, lErr)
+		}
+
+		result[doguName] = config.CreateConfig(
+			cfgData,
+			config.WithPersistenceContext(getPersistentContext(cd.rawData)),
+			config.WithInitialListResourceVersion(listResourceVersion),
+		)
+	}
+
+	return result, nil
+}
+
+// getDoguName returns the dogu name from the label of the config object. Objects without the label are named
+// after the config, e.g. "ldap-config" belongs to the dogu "ldap".
+func getDoguName(rawData any) config.SimpleDoguName {
+	object, ok := rawData.(metav1.Object)
+	if !ok {
+		return ""
+	}
+
+	if doguName, exists := object.GetLabels()[doguNameLabelKey]; exists && doguName != "" {
+		return config.SimpleDoguName(doguName)
+	}
+
+	return config.SimpleDoguName(strings.TrimSuffix(object.GetName(), "-config"))
+}
+
 func (cr configRepository) delete(ctx context.Context, name configName) error {
 	if err := cr.client.Delete(ctx, name.String()); err != nil {
 		return fmt.Errorf("could not delete data '%s' in cluster: %w", name, err)
@@ -99,7 +144,11 @@ func (cr configRepository) update(ctx context.Context, name configName, doguName
 	return cfg, nil
 }
 
-func (cr configRepository) saveOrMerge(ctx context.Context, name configName, cfg config.Config) (config.Config, error) {
+// mergedConfigValidator checks the entries that result from merging local changes into the remote config before they
+// are written. A nil validator accepts all entries.
+type mergedConfigValidator func(entries config.Entries) error
+
+func (cr configRepository) saveOrMerge(ctx context.Context, name configName, cfg config.Config, strategy MergeStrategy, validate mergedConfigValidator) (config.Config, error) {
 	if len(cfg.GetChangeHistory()) == 0 {
 		return cfg, nil
 	}
@@ -120,11 +169,17 @@ func (cr configRepository) saveOrMerge(ctx context.Context, name configName, cfg
 		return cfg, nil
 	}
 
-	updatedRemoteConfigData, err := mergeConfigData(remoteConfigData, cfg)
+	updatedRemoteConfigData, err := mergeConfigData(remoteConfigData, cfg, strategy)
 	if err != nil {
 		return config.Config{}, fmt.Errorf("could not apply local changes to remote data: %w", err)
 	}
 
+	if validate != nil {
+		if vErr := validate(updatedRemoteConfigData); vErr != nil {
+			return config.Config{}, fmt.Errorf("merged config is invalid: %w", vErr)
+		}
+	}
+
 	var buf bytes.Buffer
 
 	if lErr := cr.converter.Write(&buf, updatedRemoteConfigData); lErr != nil {
@@ -146,37 +201,155 @@ func (cr configRepository) saveOrMerge(ctx context.Context, name configName, cfg
 	return updatedConfig, nil
 }
 
-func mergeConfigData(remoteCfgData config.Entries, localCfg config.Config) (config.Entries, error) {
-	for _, c := range localCfg.GetChangeHistory() {
-		if c.Deleted {
-			delete(remoteCfgData, c.KeyPath)
+type configWatchResult struct {
+	// doguName is only set for results of watchAll
+	doguName  config.SimpleDoguName
+	eventKind config.EventKind
+	prevState config.Config
+	newState  config.Config
+	err       error
+}
+
+func (cr configRepository) watch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
+	lastCfg, err := cr.get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not get config: %w", err)
+	}
+
+	clientResultChan, err := cr.client.Watch(ctx, name.String(), lastCfg.InitialListResourceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not start watch: %w", err)
+	}
+
+	resultChan := make(chan configWatchResult)
+
+	go func() {
+		defer close(resultChan)
+		cr.forwardWatchResults(lastCfg, clientResultChan, resultChan, filters)
+	}()
+
+	return resultChan, nil
+}
+
+// awaitAndWatch works like watch but does not fail if the config does not exist yet. Instead, it waits for the config
+// to be created. The first result always contains the initial state of the config with an empty previous state,
+// regardless of the filters. Afterward, the watch continues like a normal watch.
+func (cr configRepository) awaitAndWatch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
+	cd, listResourceVersion, err := cr.client.GetWithListResourceVersion(ctx, name.String())
+	if err != nil && !errors.IsNotFoundError(err) {
+		return nil, fmt.Errorf("could not get config: %w", err)
+	}
+
+	exists := err == nil
+
+	var initialCfg config.Config
+	if exists {
+		cfgData, rErr := cr.converter.Read(strings.NewReader(cd.dataStr))
+		if rErr != nil {
+			return nil, fmt.Errorf("could not convert client data to config data: %w", rErr)
+		}
+
+		initialCfg = config.CreateConfig(
+			cfgData,
+			config.WithPersistenceContext(getPersistentContext(cd.rawData)),
+			config.WithInitialListResourceVersion(listResourceVersion),
+		)
+	}
+
+	clientResultChan, err := cr.client.Watch(ctx, name.String(), listResourceVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not start watch: %w", err)
+	}
+
+	resultChan := make(chan configWatchResult)
+
+	go func() {
+		defer close(resultChan)
+
+		if exists {
+			resultChan <- configWatchResult{
+				eventKind: config.EventCreated,
+				prevState: config.CreateConfig(make(config.Entries)),
+				newState:  initialCfg,
+			}
+		} else {
+			var ok bool
+			initialCfg, ok = awaitCreation(clientResultChan, resultChan, cr.converter)
+			if !ok {
+				return
+			}
+		}
+
+		cr.forwardWatchResults(initialCfg, clientResultChan, resultChan, filters)
+	}()
+
+	return resultChan, nil
+}
+
+// awaitCreation forwards the first result of a created config and returns its state. Errors are forwarded while
+// waiting. It returns false if the client watch ends before the config has been created.
+func awaitCreation(clientResultChan <-chan clientWatchResult, resultChan chan<- configWatchResult, converter config.Converter) (config.Config, bool) {
+	for clientResult := range clientResultChan {
+		configResult := createConfigWatchResult(config.CreateConfig(make(config.Entries)), clientResult, converter)
+		if configResult.err != nil {
+			resultChan <- configResult
 			continue
 		}
 
-		updatedValue, ok := localCfg.Get(c.KeyPath)
-		if !ok {
-			return nil, fmt.Errorf("unable get local config value for key %s to update remote config value", c.KeyPath)
+		if configResult.eventKind == config.EventDeleted {
+			continue
 		}
 
-		remoteCfgData[c.KeyPath] = updatedValue
+		configResult.eventKind = config.EventCreated
+		resultChan <- configResult
+
+		return configResult.newState, true
 	}
 
-	return remoteCfgData, nil
+	return config.Config{}, false
 }
 
-type configWatchResult struct {
-	prevState config.Config
-	newState  config.Config
-	err       error
+// forwardWatchResults converts the client results to config results and forwards the ones that match any filter.
+func (cr configRepository) forwardWatchResults(lastCfg config.Config, clientResultChan <-chan clientWatchResult, resultChan chan<- configWatchResult, filters []config.WatchFilter) {
+	for clientResult := range clientResultChan {
+		configResult := createConfigWatchResult(lastCfg, clientResult, cr.converter)
+
+		if configResult.err != nil {
+			resultChan <- configResult
+			continue
+		}
+
+		notify := matchesAnyFilter(configResult, filters)
+		if notify {
+			resultChan <- configResult
+		}
+
+		// a deleted config is always forgotten, so that a recreated config is compared with an empty config
+		if notify || configResult.eventKind == config.EventDeleted {
+			lastCfg = configResult.newState
+		}
+	}
 }
 
-func (cr configRepository) watch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
-	lastCfg, err := cr.get(ctx, name)
+// watchAll watches all configs matching the selector with a single watch. Configs that are created after the start of
+// the watch are reported with an empty previous state, deleted configs with an empty new state.
+func (cr configRepository) watchAll(ctx context.Context, selector labels.Selector, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
+	cds, listResourceVersion, err := cr.client.List(ctx, selector)
 	if err != nil {
-		return nil, fmt.Errorf("could not get config: %w", err)
+		return nil, fmt.Errorf("could not list configs: %w", err)
 	}
 
-	clientResultChan, err := cr.client.Watch(ctx, name.String(), lastCfg.InitialListResourceVersion)
+	lastCfgs := make(map[config.SimpleDoguName]config.Config, len(cds))
+	for _, cd := range cds {
+		cfgData, lErr := cr.converter.Read(strings.NewReader(cd.dataStr))
+		if lErr != nil {
+			return nil, fmt.Errorf("could not convert client data of dogu %s to config data: %w", getDoguName(cd.rawData), lErr)
+		}
+
+		lastCfgs[getDoguName(cd.rawData)] = config.CreateConfig(cfgData, config.WithPersistenceContext(getPersistentContext(cd.rawData)))
+	}
+
+	clientResultChan, err := cr.client.WatchAll(ctx, selector, listResourceVersion)
 	if err != nil {
 		return nil, fmt.Errorf("could not start watch: %w", err)
 	}
@@ -186,28 +359,31 @@ func (cr configRepository) watch(ctx context.Context, name configName, filters .
 	go func() {
 		defer close(resultChan)
 		for clientResult := range clientResultChan {
+			doguName := config.SimpleDoguName(clientResult.doguName)
+			lastCfg, ok := lastCfgs[doguName]
+			if !ok {
+				lastCfg = config.CreateConfig(make(config.Entries))
+			}
+
 			configResult := createConfigWatchResult(lastCfg, clientResult, cr.converter)
+			configResult.doguName = doguName
 
 			if configResult.err != nil {
 				resultChan <- configResult
 				continue
 			}
 
-			// when no filter is set, notify about every change
-			if len(filters) == 0 {
+			notify := matchesAnyFilter(configResult, filters)
+			if notify {
 				resultChan <- configResult
-				lastCfg = configResult.newState
-				continue
 			}
 
-			// apply filters, notify if one of the filters matches
-			for _, filter := range filters {
-				if filter(configResult.prevState.Diff(configResult.newState)) {
-					resultChan <- configResult
-					lastCfg = configResult.newState
-
-					break
-				}
+			// like in forwardWatchResults, the last config is only updated if it was reported, so that changes that
+			// do not match any filter are contained in the previous state of the next result
+			if configResult.eventKind == config.EventDeleted {
+				delete(lastCfgs, doguName)
+			} else if notify {
+				lastCfgs[doguName] = configResult.newState
 			}
 		}
 	}()
@@ -215,6 +391,21 @@ func (cr configRepository) watch(ctx context.Context, name configName, filters .
 	return resultChan, nil
 }
 
+func matchesAnyFilter(result configWatchResult, filters []config.WatchFilter) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	diff := result.prevState.Diff(result.newState)
+	for _, filter := range filters {
+		if filter(diff) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func createConfigWatchResult(lastCfg config.Config, result clientWatchResult, converter config.Converter) configWatchResult {
 	if result.err != nil {
 		return configWatchResult{
@@ -224,6 +415,20 @@ func createConfigWatchResult(lastCfg config.Config, result clientWatchResult, co
 		}
 	}
 
+	eventKind := toEventKind(result.eventType)
+	if eventKind == config.EventDeleted {
+		return configWatchResult{
+			eventKind: eventKind,
+			prevState: lastCfg,
+			newState:  config.CreateConfig(make(config.Entries), config.WithPersistenceContext(result.persistentContext)),
+			err:       nil,
+		}
+	}
+
+	if eventKind == config.EventCreated {
+		lastCfg = config.CreateConfig(make(config.Entries))
+	}
+
 	reader := strings.NewReader(result.dataStr)
 
 	cfgData, err := converter.Read(reader)
@@ -236,12 +441,24 @@ func createConfigWatchResult(lastCfg config.Config, result clientWatchResult, co
 	}
 
 	return configWatchResult{
+		eventKind: eventKind,
 		prevState: lastCfg,
 		newState:  config.CreateConfig(cfgData, config.WithPersistenceContext(result.persistentContext)),
 		err:       nil,
 	}
 }
 
+func toEventKind(eventType watch.EventType) config.EventKind {
+	switch eventType {
+	case watch.Added:
+		return config.EventCreated
+	case watch.Deleted:
+		return config.EventDeleted
+	default:
+		return config.EventModified
+	}
+}
+
 func getPersistentContext(rawData any) string {
 	switch r := rawData.(type) {
 	case string:
@@ -3,10 +3,14 @@ package repository
 import (
 	"context"
 	"github.com/cloudogu/k8s-registry-lib/config"
+	liberrors "github.com/cloudogu/k8s-registry-lib/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
 	"sync"
 	"testing"
 	"time"
@@ -48,6 +52,18 @@ func TestNewConfigRepo(t *testing.T) {
 			assert.IsType(t, &config.YamlConverter{}, repo.converter)
 		})
 	}
+
+	t.Run("should use converter from option", func(t *testing.T) {
+		repo := newConfigRepo(newMockConfigClient(t), WithConverter(&config.PropertiesConverter{}))
+
+		assert.IsType(t, &config.PropertiesConverter{}, repo.converter)
+	})
+}
+
+func Test_getDataKey(t *testing.T) {
+	assert.Equal(t, "config.yaml", getDataKey(&config.YamlConverter{}))
+	assert.Equal(t, "config.json", getDataKey(&config.JsonConverter{}))
+	assert.Equal(t, dataKeyName, getDataKey(newMockConverter(t)))
 }
 
 func TestConfigRepo_get(t *testing.T) {
@@ -444,7 +460,7 @@ func TestConfigRepo_write(t *testing.T) {
 				converter: mConverter,
 			}
 
-			uCfg, err := r.saveOrMerge(context.TODO(), "", test.inCfg)
+			uCfg, err := r.saveOrMerge(context.TODO(), "", test.inCfg, LocalWins, nil)
 			assert.Equal(t, test.xErr, err != nil)
 
 			if err == nil {
@@ -454,6 +470,52 @@ func TestConfigRepo_write(t *testing.T) {
 	}
 }
 
+func TestConfigRepo_saveOrMergeValidation(t *testing.T) {
+	localCfg := createConfigWithChanges(t, config.Entries{"key1": "localValue"}, []config.Change{{KeyPath: "key1"}})
+
+	t.Run("should validate merged entries before update", func(t *testing.T) {
+		// given
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().Get(mock.Anything, mock.Anything).Return(clientData{}, nil)
+		mConverter := newMockConverter(t)
+		mConverter.EXPECT().Read(mock.Anything).Return(config.Entries{"key2": "remoteValue"}, nil)
+		r := configRepository{client: mClient, converter: mConverter}
+
+		var validatedEntries config.Entries
+		validate := func(entries config.Entries) error {
+			validatedEntries = entries
+			return assert.AnError
+		}
+
+		// when
+		_, err := r.saveOrMerge(context.TODO(), "", localCfg, LocalWins, validate)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "merged config is invalid")
+		assert.Equal(t, config.Entries{"key1": "localValue", "key2": "remoteValue"}, validatedEntries)
+	})
+
+	t.Run("should update valid merged entries", func(t *testing.T) {
+		// given
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().Get(mock.Anything, mock.Anything).Return(clientData{}, nil)
+		mClient.EXPECT().UpdateClientData(mock.Anything, mock.Anything).Return(&v1.ConfigMap{}, nil)
+		mConverter := newMockConverter(t)
+		mConverter.EXPECT().Read(mock.Anything).Return(config.Entries{"key2": "remoteValue"}, nil)
+		mConverter.EXPECT().Write(mock.Anything, mock.Anything).Return(nil)
+		r := configRepository{client: mClient, converter: mConverter}
+
+		// when
+		uCfg, err := r.saveOrMerge(context.TODO(), "", localCfg, LocalWins, func(config.Entries) error { return nil })
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, config.Entries{"key1": "localValue", "key2": "remoteValue"}, uCfg.GetAll())
+	})
+}
+
 func TestMergeConfigData(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -608,7 +670,7 @@ func TestMergeConfigData(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			res, err := mergeConfigData(tc.remoteCfg, tc.localCfg)
+			res, err := mergeConfigData(tc.remoteCfg, tc.localCfg, LocalWins)
 			assert.Equal(t, tc.xErr, err != nil)
 			assert.Equal(t, tc.xResult, res)
 		})
@@ -642,9 +704,9 @@ func Test_configRepo_watch(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			resultChan <- clientWatchResult{"foo: value", "", nil}
-			resultChan <- clientWatchResult{"key: other", "", nil}
-			resultChan <- clientWatchResult{"", "", assert.AnError}
+			resultChan <- clientWatchResult{dataStr: "foo: value", persistentContext: "", err: nil}
+			resultChan <- clientWatchResult{dataStr: "key: other", persistentContext: "", err: nil}
+			resultChan <- clientWatchResult{dataStr: "", persistentContext: "", err: assert.AnError}
 
 			close(resultChan)
 		}()
@@ -717,9 +779,9 @@ func Test_configRepo_watch(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			resultChan <- clientWatchResult{"foo: value", "", nil}
-			resultChan <- clientWatchResult{"key: other", "", nil}
-			resultChan <- clientWatchResult{"", "", assert.AnError}
+			resultChan <- clientWatchResult{dataStr: "foo: value", persistentContext: "", err: nil}
+			resultChan <- clientWatchResult{dataStr: "key: other", persistentContext: "", err: nil}
+			resultChan <- clientWatchResult{dataStr: "", persistentContext: "", err: assert.AnError}
 
 			close(resultChan)
 		}()
@@ -781,8 +843,8 @@ func Test_configRepo_watch(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
-			resultChan <- clientWatchResult{"foo: value", "", nil}
-			resultChan <- clientWatchResult{"key: other", "", nil}
+			resultChan <- clientWatchResult{dataStr: "foo: value", persistentContext: "", err: nil}
+			resultChan <- clientWatchResult{dataStr: "key: other", persistentContext: "", err: nil}
 
 			close(resultChan)
 		}()
@@ -807,6 +869,48 @@ func Test_configRepo_watch(t *testing.T) {
 			t.Errorf("did not reach all evente in time")
 		}
 	})
+
+	t.Run("should report deletion and recreation of config", func(t *testing.T) {
+		resultChan := make(chan clientWatchResult)
+
+		ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctxTimeout, "dogu-config").Return(clientData{"key: bar", &v1.ConfigMap{}}, "1", nil)
+		mockClient.EXPECT().Watch(ctxTimeout, "dogu-config", "1").Return(resultChan, nil)
+
+		repo := newConfigRepo(mockClient)
+
+		watchChan, err := repo.watch(ctxTimeout, "dogu-config", config.KeyFilter("key"))
+		require.NoError(t, err)
+
+		go func() {
+			resultChan <- clientWatchResult{dataStr: "key: value", persistentContext: "2", eventType: watch.Modified}
+			resultChan <- clientWatchResult{persistentContext: "3", eventType: watch.Deleted}
+			resultChan <- clientWatchResult{dataStr: "key: value", persistentContext: "4", eventType: watch.Added}
+
+			close(resultChan)
+		}()
+
+		var results []configWatchResult
+		for result := range watchChan {
+			results = append(results, result)
+		}
+
+		require.Len(t, results, 3)
+		assert.Equal(t, config.EventModified, results[0].eventKind)
+
+		assert.NoError(t, results[1].err)
+		assert.Equal(t, config.EventDeleted, results[1].eventKind)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"key": "value"}, config.WithPersistenceContext("2")), results[1].prevState)
+		assert.Equal(t, config.CreateConfig(make(config.Entries), config.WithPersistenceContext("3")), results[1].newState)
+
+		assert.NoError(t, results[2].err)
+		assert.Equal(t, config.EventCreated, results[2].eventKind)
+		assert.Equal(t, config.CreateConfig(make(config.Entries)), results[2].prevState)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"key": "value"}, config.WithPersistenceContext("4")), results[2].newState)
+	})
 }
 
 func Test_createConfigWatchResult(t *testing.T) {
@@ -826,7 +930,19 @@ func Test_createConfigWatchResult(t *testing.T) {
 }
 
 func createConfigWithChanges(t *testing.T, initialEntries config.Entries, changes []config.Change) config.Config {
-	cfg := config.CreateConfig(initialEntries, config.WithPersistenceContext(""))
+	// keys that are set by a change are not part of the base, so that setting them is an actual local change
+	baseEntries := make(config.Entries, len(initialEntries))
+	for k, v := range initialEntries {
+		baseEntries[k] = v
+	}
+
+	for _, c := range changes {
+		if !c.Deleted {
+			delete(baseEntries, c.KeyPath)
+		}
+	}
+
+	cfg := config.CreateConfig(baseEntries, config.WithPersistenceContext(""))
 
 	for _, c := range changes {
 		if c.Deleted {
@@ -842,3 +958,366 @@ func createConfigWithChanges(t *testing.T, initialEntries config.Entries, change
 
 	return cfg
 }
+
+func TestConfigRepo_list(t *testing.T) {
+	t.Run("should convert listed data to configs", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, labels.Everything()).Return([]clientData{
+			{
+				dataStr: "key: value",
+				rawData: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cas-config", ResourceVersion: "1", Labels: map[string]string{doguNameLabelKey: "cas"}}},
+			},
+			{
+				dataStr: "other: value",
+				rawData: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ldap-config", ResourceVersion: "2"}},
+			},
+		}, "3", nil)
+
+		r := newConfigRepo(mClient)
+		result, err := r.list(context.TODO(), labels.Everything())
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, config.Entries{"key": "value"}, result["cas"].GetAll())
+		assert.Equal(t, "1", result["cas"].PersistenceContext)
+		assert.Equal(t, "3", result["cas"].InitialListResourceVersion)
+		assert.Equal(t, config.Entries{"other": "value"}, result["ldap"].GetAll())
+		assert.Equal(t, "2", result["ldap"].PersistenceContext)
+	})
+
+	t.Run("should return error on client error", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, mock.Anything).Return(nil, "", assert.AnError)
+
+		_, err := newConfigRepo(mClient).list(context.TODO(), nil)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "unable to list data from cluster")
+	})
+
+	t.Run("should return error on converter error", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, mock.Anything).Return([]clientData{
+			{dataStr: "invalid", rawData: &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cas-config"}}},
+		}, "1", nil)
+		mConverter := newMockConverter(t)
+		mConverter.EXPECT().Read(mock.Anything).Return(nil, assert.AnError)
+
+		r := configRepository{client: mClient, converter: mConverter}
+		_, err := r.list(context.TODO(), nil)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not convert client data of dogu cas to config data")
+	})
+}
+
+func Test_getDoguName(t *testing.T) {
+	assert.Equal(t, config.SimpleDoguName("cas"), getDoguName(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-config", Labels: map[string]string{doguNameLabelKey: "cas"}}}))
+	assert.Equal(t, config.SimpleDoguName("ldap"), getDoguName(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ldap-config"}}))
+	assert.Equal(t, config.SimpleDoguName(""), getDoguName("no object"))
+}
+
+func Test_configRepo_watchAll(t *testing.T) {
+	newDoguConfigMap := func(doguName, resourceVersion string) *v1.ConfigMap {
+		return &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:            doguName + "-config",
+			ResourceVersion: resourceVersion,
+			Labels:          map[string]string{doguNameLabelKey: doguName},
+		}}
+	}
+
+	t.Run("should track configs of added, modified and deleted dogus", func(t *testing.T) {
+		ctx := context.Background()
+		clientResultChan := make(chan clientWatchResult)
+
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(ctx, labels.Everything()).Return([]clientData{
+			{dataStr: "foo: bar", rawData: newDoguConfigMap("cas", "1")},
+		}, "2", nil)
+		mClient.EXPECT().WatchAll(ctx, labels.Everything(), "2").Return(clientResultChan, nil)
+
+		repo := newConfigRepo(mClient)
+		resultChan, err := repo.watchAll(ctx, labels.Everything())
+		require.NoError(t, err)
+
+		go func() {
+			clientResultChan <- clientWatchResult{dataStr: "foo: changed", persistentContext: "3", eventType: watch.Modified, doguName: "cas"}
+			clientResultChan <- clientWatchResult{dataStr: "key: value", persistentContext: "4", eventType: watch.Added, doguName: "ldap"}
+			clientResultChan <- clientWatchResult{dataStr: "foo: changed", persistentContext: "5", eventType: watch.Deleted, doguName: "cas"}
+			clientResultChan <- clientWatchResult{err: assert.AnError}
+			close(clientResultChan)
+		}()
+
+		result := <-resultChan
+		require.NoError(t, result.err)
+		assert.Equal(t, config.SimpleDoguName("cas"), result.doguName)
+		assert.Equal(t, config.Entries{"foo": "bar"}, result.prevState.GetAll())
+		assert.Equal(t, config.Entries{"foo": "changed"}, result.newState.GetAll())
+		assert.Equal(t, "3", result.newState.PersistenceContext)
+
+		result = <-resultChan
+		require.NoError(t, result.err)
+		assert.Equal(t, config.SimpleDoguName("ldap"), result.doguName)
+		assert.Empty(t, result.prevState.GetAll())
+		assert.Equal(t, config.Entries{"key": "value"}, result.newState.GetAll())
+
+		result = <-resultChan
+		require.NoError(t, result.err)
+		assert.Equal(t, config.SimpleDoguName("cas"), result.doguName)
+		assert.Equal(t, config.Entries{"foo": "changed"}, result.prevState.GetAll())
+		assert.Empty(t, result.newState.GetAll())
+
+		result = <-resultChan
+		assert.ErrorIs(t, result.err, assert.AnError)
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should only notify about changes matching the filters", func(t *testing.T) {
+		ctx := context.Background()
+		clientResultChan := make(chan clientWatchResult)
+
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(ctx, labels.Everything()).Return([]clientData{
+			{dataStr: "foo: bar", rawData: newDoguConfigMap("cas", "1")},
+			{dataStr: "foo: bar", rawData: newDoguConfigMap("ldap", "1")},
+		}, "2", nil)
+		mClient.EXPECT().WatchAll(ctx, labels.Everything(), "2").Return(clientResultChan, nil)
+
+		repo := newConfigRepo(mClient)
+		resultChan, err := repo.watchAll(ctx, labels.Everything(), config.KeyFilter("foo"))
+		require.NoError(t, err)
+
+		go func() {
+			clientResultChan <- clientWatchResult{dataStr: "foo: bar\nother: value", persistentContext: "3", eventType: watch.Modified, doguName: "cas"}
+			clientResultChan <- clientWatchResult{dataStr: "foo: changed", persistentContext: "4", eventType: watch.Modified, doguName: "ldap"}
+			close(clientResultChan)
+		}()
+
+		result := <-resultChan
+		require.NoError(t, result.err)
+		assert.Equal(t, config.SimpleDoguName("ldap"), result.doguName)
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should compare with the last reported config of a dogu", func(t *testing.T) {
+		ctx := context.Background()
+		clientResultChan := make(chan clientWatchResult)
+
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(ctx, labels.Everything()).Return([]clientData{
+			{dataStr: "foo: bar", rawData: newDoguConfigMap("cas", "1")},
+		}, "2", nil)
+		mClient.EXPECT().WatchAll(ctx, labels.Everything(), "2").Return(clientResultChan, nil)
+
+		repo := newConfigRepo(mClient)
+		resultChan, err := repo.watchAll(ctx, labels.Everything(), config.KeyFilter("foo"))
+		require.NoError(t, err)
+
+		go func() {
+			clientResultChan <- clientWatchResult{dataStr: "foo: bar\nother: value", persistentContext: "3", eventType: watch.Modified, doguName: "cas"}
+			clientResultChan <- clientWatchResult{dataStr: "foo: changed\nother: value", persistentContext: "4", eventType: watch.Modified, doguName: "cas"}
+			close(clientResultChan)
+		}()
+
+		result := <-resultChan
+		require.NoError(t, result.err)
+		assert.Equal(t, config.SimpleDoguName("cas"), result.doguName)
+		assert.Equal(t, config.Entries{"foo": "bar"}, result.prevState.GetAll())
+		assert.Equal(t, config.Entries{"foo": "changed", "other": "value"}, result.newState.GetAll())
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should fail on list error", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, mock.Anything).Return(nil, "", assert.AnError)
+
+		_, err := newConfigRepo(mClient).watchAll(context.TODO(), labels.Everything())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not list configs")
+	})
+
+	t.Run("should fail on converter error", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, mock.Anything).Return([]clientData{{dataStr: "invalid", rawData: newDoguConfigMap("cas", "1")}}, "2", nil)
+		mConverter := newMockConverter(t)
+		mConverter.EXPECT().Read(mock.Anything).Return(nil, assert.AnError)
+
+		r := configRepository{client: mClient, converter: mConverter}
+		_, err := r.watchAll(context.TODO(), labels.Everything())
+
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should fail on watch error", func(t *testing.T) {
+		mClient := newMockConfigClient(t)
+		mClient.EXPECT().List(mock.Anything, mock.Anything).Return(nil, "2", nil)
+		mClient.EXPECT().WatchAll(mock.Anything, mock.Anything, "2").Return(nil, assert.AnError)
+
+		_, err := newConfigRepo(mClient).watchAll(context.TODO(), labels.Everything())
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not start watch")
+	})
+}
+
+func Test_toEventKind(t *testing.T) {
+	tests := []struct {
+		eventType watch.EventType
+		want      config.EventKind
+	}{
+		{watch.Added, config.EventCreated},
+		{watch.Modified, config.EventModified},
+		{watch.Deleted, config.EventDeleted},
+		{"", config.EventModified},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.eventType), func(t *testing.T) {
+			assert.Equal(t, tt.want, toEventKind(tt.eventType))
+		})
+	}
+}
+
+func Test_configRepo_awaitAndWatch(t *testing.T) {
+	ctx := context.TODO()
+
+	collectResults := func(watchChan <-chan configWatchResult) []configWatchResult {
+		var results []configWatchResult
+		for result := range watchChan {
+			results = append(results, result)
+		}
+
+		return results
+	}
+
+	t.Run("should emit initial state of existing config", func(t *testing.T) {
+		resultChan := make(chan clientWatchResult)
+
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{"key: bar", &v1.ConfigMap{}}, "1", nil)
+		mockClient.EXPECT().Watch(ctx, "dogu-config", "1").Return(resultChan, nil)
+
+		repo := newConfigRepo(mockClient)
+
+		watchChan, err := repo.awaitAndWatch(ctx, "dogu-config", config.KeyFilter("key"))
+		require.NoError(t, err)
+
+		go func() {
+			resultChan <- clientWatchResult{dataStr: "key: bar\nfoo: value", persistentContext: "2", eventType: watch.Modified}
+			resultChan <- clientWatchResult{dataStr: "key: value", persistentContext: "3", eventType: watch.Modified}
+			close(resultChan)
+		}()
+
+		results := collectResults(watchChan)
+
+		require.Len(t, results, 2)
+		assert.Equal(t, config.EventCreated, results[0].eventKind)
+		assert.Equal(t, config.CreateConfig(make(config.Entries)), results[0].prevState)
+		assert.Equal(t, config.CreateConfig(
+			map[config.Key]config.Value{"key": "bar"},
+			config.WithPersistenceContext(""),
+			config.WithInitialListResourceVersion("1"),
+		), results[0].newState)
+
+		assert.Equal(t, config.EventModified, results[1].eventKind)
+		assert.Equal(t, results[0].newState, results[1].prevState)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"key": "value"}, config.WithPersistenceContext("3")), results[1].newState)
+	})
+
+	t.Run("should wait for creation of config", func(t *testing.T) {
+		resultChan := make(chan clientWatchResult)
+
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{}, "1", liberrors.NewNotFoundError(assert.AnError))
+		mockClient.EXPECT().Watch(ctx, "dogu-config", "1").Return(resultChan, nil)
+
+		repo := newConfigRepo(mockClient)
+
+		watchChan, err := repo.awaitAndWatch(ctx, "dogu-config", config.KeyFilter("key"))
+		require.NoError(t, err)
+
+		go func() {
+			resultChan <- clientWatchResult{err: assert.AnError}
+			resultChan <- clientWatchResult{dataStr: "foo: bar", persistentContext: "2", eventType: watch.Added}
+			resultChan <- clientWatchResult{dataStr: "foo: changed", persistentContext: "3", eventType: watch.Modified}
+			resultChan <- clientWatchResult{dataStr: "foo: changed\nkey: value", persistentContext: "4", eventType: watch.Modified}
+			close(resultChan)
+		}()
+
+		results := collectResults(watchChan)
+
+		require.Len(t, results, 3)
+		assert.ErrorIs(t, results[0].err, assert.AnError)
+
+		assert.NoError(t, results[1].err)
+		assert.Equal(t, config.EventCreated, results[1].eventKind)
+		assert.Equal(t, config.CreateConfig(make(config.Entries)), results[1].prevState)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"foo": "bar"}, config.WithPersistenceContext("2")), results[1].newState)
+
+		assert.Equal(t, config.EventModified, results[2].eventKind)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"foo": "bar"}, config.WithPersistenceContext("2")), results[2].prevState)
+		assert.Equal(t, config.CreateConfig(map[config.Key]config.Value{"foo": "changed", "key": "value"}, config.WithPersistenceContext("4")), results[2].newState)
+	})
+
+	t.Run("should close result channel if watch ends before creation", func(t *testing.T) {
+		resultChan := make(chan clientWatchResult)
+
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{}, "1", liberrors.NewNotFoundError(assert.AnError))
+		mockClient.EXPECT().Watch(ctx, "dogu-config", "1").Return(resultChan, nil)
+
+		repo := newConfigRepo(mockClient)
+
+		watchChan, err := repo.awaitAndWatch(ctx, "dogu-config")
+		require.NoError(t, err)
+
+		go func() {
+			resultChan <- clientWatchResult{persistentContext: "2", eventType: watch.Deleted}
+			close(resultChan)
+		}()
+
+		assert.Empty(t, collectResults(watchChan))
+	})
+
+	t.Run("should fail on error other than not found", func(t *testing.T) {
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{}, "", liberrors.NewConnectionError(assert.AnError))
+
+		repo := newConfigRepo(mockClient)
+
+		_, err := repo.awaitAndWatch(ctx, "dogu-config")
+
+		assert.True(t, liberrors.IsConnectionError(err))
+		assert.ErrorContains(t, err, "could not get config")
+	})
+
+	t.Run("should fail to convert existing config", func(t *testing.T) {
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{"key: [", &v1.ConfigMap{}}, "1", nil)
+
+		repo := newConfigRepo(mockClient)
+
+		_, err := repo.awaitAndWatch(ctx, "dogu-config")
+
+		assert.ErrorContains(t, err, "could not convert client data to config data")
+	})
+
+	t.Run("should fail to start watch", func(t *testing.T) {
+		mockClient := newMockConfigClient(t)
+		mockClient.EXPECT().GetWithListResourceVersion(ctx, "dogu-config").Return(clientData{}, "1", liberrors.NewNotFoundError(assert.AnError))
+		mockClient.EXPECT().Watch(ctx, "dogu-config", "1").Return(nil, assert.AnError)
+
+		repo := newConfigRepo(mockClient)
+
+		_, err := repo.awaitAndWatch(ctx, "dogu-config")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not start watch")
+	})
+}
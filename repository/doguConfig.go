@@ -4,27 +4,37 @@ import (
 	"context"
 	"fmt"
 	"github.com/cloudogu/k8s-registry-lib/config"
+	"k8s.io/apimachinery/pkg/labels"
+	"strings"
 )
 
 type DoguConfigRepository struct {
 	generalConfigRepository
+	descriptorGetter DoguDescriptorGetter
+	validator        config.DoguConfigValidator
 }
 
-func NewDoguConfigRepository(client ConfigMapClient) *DoguConfigRepository {
-	cfgClient := createConfigMapClient(client, doguConfigType)
-	cfgRepository := newConfigRepo(cfgClient)
+func NewDoguConfigRepository(client ConfigMapClient, opts ...DoguConfigRepositoryOption) *DoguConfigRepository {
+	options := applyDoguConfigRepositoryOptions(opts)
+	cfgClient := createConfigMapClient(client, doguConfigType, options.dataKey)
 
-	return &DoguConfigRepository{
-		generalConfigRepository: cfgRepository,
-	}
+	return newDoguConfigRepository(cfgClient, config.NewDoguConfigValidator(), options)
 }
 
-func NewSensitiveDoguConfigRepository(client SecretClient) *DoguConfigRepository {
-	cfgClient := createSecretClient(client, sensitiveConfigType)
-	cfgRepository := newConfigRepo(cfgClient)
+func NewSensitiveDoguConfigRepository(client SecretClient, opts ...DoguConfigRepositoryOption) *DoguConfigRepository {
+	options := applyDoguConfigRepositoryOptions(opts)
+	cfgClient := createSecretClient(client, sensitiveConfigType, options.dataKey)
+
+	return newDoguConfigRepository(cfgClient, config.NewSensitiveDoguConfigValidator(), options)
+}
 
+// newDoguConfigRepository creates a DoguConfigRepository for the given client. It is used by the cached and the
+// uncached constructors, so that both apply the options in the same way.
+func newDoguConfigRepository(cfgClient configClient, validator config.DoguConfigValidator, options doguConfigRepositoryOptions) *DoguConfigRepository {
 	return &DoguConfigRepository{
-		generalConfigRepository: cfgRepository,
+		generalConfigRepository: newConfigRepo(cfgClient, WithConverter(options.converter)),
+		descriptorGetter:        options.descriptorGetter,
+		validator:               validator,
 	}
 }
 
@@ -40,9 +50,53 @@ func (dcr DoguConfigRepository) Get(ctx context.Context, name config.SimpleDoguN
 	}, nil
 }
 
+// List returns the configs of all dogus, fetched in a single call.
+func (dcr DoguConfigRepository) List(ctx context.Context) (map[config.SimpleDoguName]config.DoguConfig, error) {
+	return dcr.ListBySelector(ctx, labels.Everything())
+}
+
+// ListBySelector returns the configs of all dogus whose config objects match the given label selector, e.g.
+// "dogu.name in (cas, ldap)". The selector is always restricted to the config type of the repository.
+func (dcr DoguConfigRepository) ListBySelector(ctx context.Context, selector labels.Selector) (map[config.SimpleDoguName]config.DoguConfig, error) {
+	cfgs, err := dcr.list(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("could not list dogu configs: %w", err)
+	}
+
+	result := make(map[config.SimpleDoguName]config.DoguConfig, len(cfgs))
+	for doguName, cfg := range cfgs {
+		result[doguName] = config.DoguConfig{
+			DoguName: doguName,
+			Config:   cfg,
+		}
+	}
+
+	return result, nil
+}
+
+// ListByPrefix returns the configs of all dogus whose name starts with the given prefix, fetched in a single call.
+func (dcr DoguConfigRepository) ListByPrefix(ctx context.Context, prefix string) (map[config.SimpleDoguName]config.DoguConfig, error) {
+	cfgs, err := dcr.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for doguName := range cfgs {
+		if !strings.HasPrefix(doguName.String(), prefix) {
+			delete(cfgs, doguName)
+		}
+	}
+
+	return cfgs, nil
+}
+
 func (dcr DoguConfigRepository) Create(ctx context.Context, doguConfig config.DoguConfig) (config.DoguConfig, error) {
 	doguName := doguConfig.DoguName
 
+	if err := dcr.validate(ctx, doguConfig); err != nil {
+		return config.DoguConfig{}, fmt.Errorf("could not create config for dogu %s: %w", doguName, err)
+	}
+
 	cfg, err := dcr.create(ctx, createConfigName(doguName.String()), doguName, doguConfig.Config)
 	if err != nil {
 		return config.DoguConfig{}, fmt.Errorf("could not create config for dogu %s: %w", doguName, err)
@@ -57,6 +111,10 @@ func (dcr DoguConfigRepository) Create(ctx context.Context, doguConfig config.Do
 func (dcr DoguConfigRepository) Update(ctx context.Context, doguConfig config.DoguConfig) (config.DoguConfig, error) {
 	doguName := doguConfig.DoguName
 
+	if err := dcr.validate(ctx, doguConfig); err != nil {
+		return config.DoguConfig{}, fmt.Errorf("could not update config for dogu %s: %w", doguName, err)
+	}
+
 	cfg, err := dcr.update(ctx, createConfigName(doguName.String()), doguName, doguConfig.Config)
 	if err != nil {
 		return config.DoguConfig{}, fmt.Errorf("could not update config for dogu %s: %w", doguName, err)
@@ -68,8 +126,11 @@ func (dcr DoguConfigRepository) Update(ctx context.Context, doguConfig config.Do
 	}, nil
 }
 
-func (dcr DoguConfigRepository) SaveOrMerge(ctx context.Context, doguConfig config.DoguConfig) (config.DoguConfig, error) {
-	cfg, err := dcr.saveOrMerge(ctx, createConfigName(doguConfig.DoguName.String()), doguConfig.Config)
+// SaveOrMerge merges the local changes of the config into the current config of the dogu. If the schema validation is
+// enabled, the merged config is validated instead of the given one, because it is the config that gets written.
+func (dcr DoguConfigRepository) SaveOrMerge(ctx context.Context, doguConfig config.DoguConfig, opts ...SaveOrMergeOption) (config.DoguConfig, error) {
+	options := applySaveOrMergeOptions(opts)
+	cfg, err := dcr.saveOrMerge(ctx, createConfigName(doguConfig.DoguName.String()), doguConfig.Config, options.strategy, dcr.mergedConfigValidator(ctx, doguConfig.DoguName))
 	if err != nil {
 		return config.DoguConfig{}, fmt.Errorf("could not save and merge config of dogu %s: %w", doguConfig.DoguName, err)
 	}
@@ -80,6 +141,32 @@ func (dcr DoguConfigRepository) SaveOrMerge(ctx context.Context, doguConfig conf
 	}, nil
 }
 
+// validate checks the config against the current dogu descriptor if the schema validation is enabled.
+func (dcr DoguConfigRepository) validate(ctx context.Context, doguConfig config.DoguConfig) error {
+	if dcr.descriptorGetter == nil {
+		return nil
+	}
+
+	descriptor, err := dcr.descriptorGetter.GetCurrent(ctx, doguConfig.DoguName.String())
+	if err != nil {
+		return fmt.Errorf("could not get current descriptor of dogu %s for validation: %w", doguConfig.DoguName, err)
+	}
+
+	return dcr.validator.ValidateOrError(doguConfig, descriptor)
+}
+
+// mergedConfigValidator returns a validator for the merged config of the dogu or nil if the schema validation is
+// disabled.
+func (dcr DoguConfigRepository) mergedConfigValidator(ctx context.Context, doguName config.SimpleDoguName) mergedConfigValidator {
+	if dcr.descriptorGetter == nil {
+		return nil
+	}
+
+	return func(entries config.Entries) error {
+		return dcr.validate(ctx, config.DoguConfig{DoguName: doguName, Config: config.CreateConfig(entries)})
+	}
+}
+
 func (dcr DoguConfigRepository) Delete(ctx context.Context, name config.SimpleDoguName) error {
 	if err := dcr.delete(ctx, createConfigName(name.String())); err != nil {
 		return fmt.Errorf("could not delete config for dogu %s: %w", name, err)
@@ -91,6 +178,8 @@ func (dcr DoguConfigRepository) Delete(ctx context.Context, name config.SimpleDo
 type DoguConfigWatchResult struct {
 	PrevState config.DoguConfig
 	NewState  config.DoguConfig
+	// EventKind tells whether the config was modified, created or deleted.
+	EventKind config.EventKind
 	Err       error
 }
 
@@ -100,24 +189,65 @@ func (dcr DoguConfigRepository) Watch(ctx context.Context, dName config.SimpleDo
 		return nil, fmt.Errorf("unable to start watch for config from dogu %s: %w", dName, err)
 	}
 
+	return toDoguConfigWatchResults(cfgWatch, dName), nil
+}
+
+// AwaitAndWatch works like Watch but does not fail if the config of the dogu does not exist yet. Instead, it waits
+// for the config to be created. The first result always contains the initial state of the config with an empty
+// previous state and the event kind config.EventCreated. Afterward, the watch continues like Watch.
+func (dcr DoguConfigRepository) AwaitAndWatch(ctx context.Context, dName config.SimpleDoguName, filters ...config.WatchFilter) (<-chan DoguConfigWatchResult, error) {
+	cfgWatch, err := dcr.awaitAndWatch(ctx, createConfigName(dName.String()), filters...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start watch for config from dogu %s: %w", dName, err)
+	}
+
+	return toDoguConfigWatchResults(cfgWatch, dName), nil
+}
+
+// WatchAll watches the configs of all dogus with a single watch. Every result is tagged with the name of its dogu.
+// Configs of dogus that are added later are reported with an empty previous state, configs of removed dogus with an
+// empty new state. The filters are applied to the changes of each single dogu config.
+func (dcr DoguConfigRepository) WatchAll(ctx context.Context, filters ...config.WatchFilter) (<-chan DoguConfigWatchResult, error) {
+	return dcr.WatchAllBySelector(ctx, labels.Everything(), filters...)
+}
+
+// WatchAllBySelector works like WatchAll but only watches the configs whose objects match the given label selector.
+func (dcr DoguConfigRepository) WatchAllBySelector(ctx context.Context, selector labels.Selector, filters ...config.WatchFilter) (<-chan DoguConfigWatchResult, error) {
+	cfgWatch, err := dcr.watchAll(ctx, selector, filters...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start watch for all dogu configs: %w", err)
+	}
+
+	return toDoguConfigWatchResults(cfgWatch, ""), nil
+}
+
+// toDoguConfigWatchResults converts the config watch results to dogu config watch results. The dogu name of a result
+// is used if it is set, otherwise the given dogu name.
+func toDoguConfigWatchResults(cfgWatch <-chan configWatchResult, dName config.SimpleDoguName) <-chan DoguConfigWatchResult {
 	watchChan := make(chan DoguConfigWatchResult)
 
 	go func() {
 		defer close(watchChan)
 		for result := range cfgWatch {
+			doguName := dName
+			if result.doguName != "" {
+				doguName = result.doguName
+			}
+
 			watchChan <- DoguConfigWatchResult{
 				PrevState: config.DoguConfig{
-					DoguName: dName,
+					DoguName: doguName,
 					Config:   result.prevState,
 				},
 				NewState: config.DoguConfig{
-					DoguName: dName,
+					DoguName: doguName,
 					Config:   result.newState,
 				},
-				Err: result.err,
+				EventKind: result.eventKind,
+				Err:       result.err,
 			}
 		}
 	}()
 
-	return watchChan, nil
+	return watchChan
 }
@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"github.com/cloudogu/cesapp-lib/core"
 	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
 	"testing"
 	"time"
 )
@@ -24,6 +30,147 @@ func TestNewSensitiveDoguConfigRepository(t *testing.T) {
 	repo := NewSensitiveDoguConfigRepository(sClient)
 	assert.NotNil(t, repo)
 	assert.Equal(t, sClient, repo.generalConfigRepository.(configRepository).client.(secretClient).client)
+	assert.Nil(t, repo.descriptorGetter)
+}
+
+func TestNewDoguConfigRepository_WithSchemaValidation(t *testing.T) {
+	mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+
+	repo := NewDoguConfigRepository(NewMockConfigMapClient(t), WithSchemaValidation(mDescriptorGetter))
+	assert.Equal(t, mDescriptorGetter, repo.descriptorGetter)
+	assert.Equal(t, config.NewDoguConfigValidator(), repo.validator)
+
+	sensitiveRepo := NewSensitiveDoguConfigRepository(NewMockSecretClient(t), WithSchemaValidation(mDescriptorGetter))
+	assert.Equal(t, mDescriptorGetter, sensitiveRepo.descriptorGetter)
+	assert.Equal(t, config.NewSensitiveDoguConfigValidator(), sensitiveRepo.validator)
+}
+
+func TestNewDoguConfigRepository_WithConverterAndSchemaValidation(t *testing.T) {
+	mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+
+	repo := NewDoguConfigRepository(NewMockConfigMapClient(t), WithConverter(&config.JsonConverter{}), WithSchemaValidation(mDescriptorGetter))
+
+	assert.IsType(t, &config.JsonConverter{}, repo.generalConfigRepository.(configRepository).converter)
+	assert.Equal(t, "config.json", repo.generalConfigRepository.(configRepository).client.(configMapClient).dataKey)
+	assert.Equal(t, mDescriptorGetter, repo.descriptorGetter)
+}
+
+func TestNewDoguConfigRepository_WithConverter(t *testing.T) {
+	repo := NewDoguConfigRepository(NewMockConfigMapClient(t), WithConverter(&config.PropertiesConverter{}))
+	assert.IsType(t, &config.PropertiesConverter{}, repo.generalConfigRepository.(configRepository).converter)
+	assert.Equal(t, "config.properties", repo.generalConfigRepository.(configRepository).client.(configMapClient).dataKey)
+
+	sensitiveRepo := NewSensitiveDoguConfigRepository(NewMockSecretClient(t), WithConverter(&config.DotenvConverter{}))
+	assert.IsType(t, &config.DotenvConverter{}, sensitiveRepo.generalConfigRepository.(configRepository).converter)
+	assert.Equal(t, "config.env", sensitiveRepo.generalConfigRepository.(configRepository).client.(secretClient).dataKey)
+}
+
+func TestDoguConfigRepository_SchemaValidation(t *testing.T) {
+	descriptor := &core.Dogu{
+		Name: "official/test",
+		Configuration: []core.ConfigurationField{
+			{Name: "logging/root", Validation: core.ValidationDescriptor{Type: config.ValidationTypeOneOf, Values: []string{"WARN", "INFO"}}},
+		},
+	}
+	validCfg := config.CreateDoguConfig(_DoguName, config.Entries{"logging/root": "INFO"})
+	invalidCfg := config.CreateDoguConfig(_DoguName, config.Entries{"logging/root": "TRACE", "foo": "bar"})
+
+	t.Run("should write valid config", func(t *testing.T) {
+		mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+		mDescriptorGetter.EXPECT().GetCurrent(mock.Anything, _DoguName.String()).Return(descriptor, nil).Times(2)
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().create(mock.Anything, createConfigName(_DoguName.String()), _DoguName, mock.Anything).Return(config.Config{}, nil)
+		mConfigRepo.EXPECT().update(mock.Anything, createConfigName(_DoguName.String()), _DoguName, mock.Anything).Return(config.Config{}, nil)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything, LocalWins, mock.Anything).Return(config.Config{}, nil)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+			descriptorGetter:        mDescriptorGetter,
+			validator:               config.NewDoguConfigValidator(),
+		}
+
+		_, err := repo.Create(context.TODO(), validCfg)
+		assert.NoError(t, err)
+		_, err = repo.Update(context.TODO(), validCfg)
+		assert.NoError(t, err)
+		_, err = repo.SaveOrMerge(context.TODO(), validCfg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject invalid config", func(t *testing.T) {
+		mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+		mDescriptorGetter.EXPECT().GetCurrent(mock.Anything, _DoguName.String()).Return(descriptor, nil).Times(2)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: newMockGeneralConfigRepository(t),
+			descriptorGetter:        mDescriptorGetter,
+			validator:               config.NewDoguConfigValidator(),
+		}
+
+		_, err := repo.Create(context.TODO(), invalidCfg)
+		assert.ErrorContains(t, err, "could not create config for dogu test")
+		_, err = repo.Update(context.TODO(), invalidCfg)
+		assert.ErrorContains(t, err, "could not update config for dogu test")
+
+		var validationErr *config.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []config.Violation{
+			{Key: "foo", Type: config.UnknownKeyViolation},
+			{Key: "logging/root", Type: config.InvalidValueViolation, Message: `input should be one of ["WARN" "INFO"]`},
+		}, validationErr.Violations)
+	})
+
+	// newMergingRepo creates a repository whose merge of local changes always results in the given entries
+	newMergingRepo := func(t *testing.T, mergedEntries config.Entries) *DoguConfigRepository {
+		mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+		mDescriptorGetter.EXPECT().GetCurrent(mock.Anything, _DoguName.String()).Return(descriptor, nil)
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything, LocalWins, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ configName, _ config.Config, _ MergeStrategy, validate mergedConfigValidator) (config.Config, error) {
+				return config.CreateConfig(mergedEntries), validate(mergedEntries)
+			})
+
+		return &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+			descriptorGetter:        mDescriptorGetter,
+			validator:               config.NewDoguConfigValidator(),
+		}
+	}
+
+	t.Run("should save and merge invalid local config if merged config is valid", func(t *testing.T) {
+		repo := newMergingRepo(t, config.Entries{"logging/root": "INFO"})
+
+		_, err := repo.SaveOrMerge(context.TODO(), invalidCfg)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject valid local config on save and merge if merged config is invalid", func(t *testing.T) {
+		repo := newMergingRepo(t, config.Entries{"logging/root": "TRACE"})
+
+		_, err := repo.SaveOrMerge(context.TODO(), validCfg)
+		assert.ErrorContains(t, err, "could not save and merge config of dogu test")
+
+		var validationErr *config.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, []config.Violation{
+			{Key: "logging/root", Type: config.InvalidValueViolation, Message: `input should be one of ["WARN" "INFO"]`},
+		}, validationErr.Violations)
+	})
+
+	t.Run("should fail if descriptor cannot be retrieved", func(t *testing.T) {
+		mDescriptorGetter := NewMockDoguDescriptorGetter(t)
+		mDescriptorGetter.EXPECT().GetCurrent(mock.Anything, _DoguName.String()).Return(nil, assert.AnError)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: newMockGeneralConfigRepository(t),
+			descriptorGetter:        mDescriptorGetter,
+			validator:               config.NewDoguConfigValidator(),
+		}
+
+		_, err := repo.Update(context.TODO(), validCfg)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not get current descriptor of dogu test for validation")
+	})
 }
 
 func TestDoguConfigRepository_Get(t *testing.T) {
@@ -113,7 +260,7 @@ func TestDoguConfigRepository_Update(t *testing.T) {
 func TestDoguConfigRepository_SaveOrMerge(t *testing.T) {
 	t.Run("Save&Merge Dogu Config", func(t *testing.T) {
 		mConfigRepo := newMockGeneralConfigRepository(t)
-		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything, LocalWins, mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
 
 		repo := &DoguConfigRepository{
 			generalConfigRepository: mConfigRepo,
@@ -125,9 +272,22 @@ func TestDoguConfigRepository_SaveOrMerge(t *testing.T) {
 		assert.Equal(t, resourceVersion, cfg.PersistenceContext)
 	})
 
+	t.Run("Save&Merge Dogu Config with merge strategy", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything, RemoteWins, mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		cfg, err := repo.SaveOrMerge(context.TODO(), config.CreateDoguConfig(_DoguName, make(config.Entries)), WithMergeStrategy(RemoteWins))
+		assert.NoError(t, err)
+		assert.Equal(t, resourceVersion, cfg.PersistenceContext)
+	})
+
 	t.Run("Config repo error", func(t *testing.T) {
 		mConfigRepo := newMockGeneralConfigRepository(t)
-		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything).Return(config.Config{}, assert.AnError)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_DoguName.String()), mock.Anything, LocalWins, mock.Anything).Return(config.Config{}, assert.AnError)
 
 		repo := &DoguConfigRepository{
 			generalConfigRepository: mConfigRepo,
@@ -184,9 +344,9 @@ func TestDoguConfigRepository_Watch(t *testing.T) {
 		cancel := make(chan bool, 1)
 
 		go func() {
-			mockResultChan <- configWatchResult{config.CreateConfig(config.Entries{"foo": "val"}), config.CreateConfig(config.Entries{"foo": "val2"}), nil}
-			mockResultChan <- configWatchResult{config.CreateConfig(config.Entries{"foo": "val2"}), config.CreateConfig(nil), nil}
-			mockResultChan <- configWatchResult{config.CreateConfig(nil), config.CreateConfig(nil), assert.AnError}
+			mockResultChan <- configWatchResult{prevState: config.CreateConfig(config.Entries{"foo": "val"}), newState: config.CreateConfig(config.Entries{"foo": "val2"}), err: nil}
+			mockResultChan <- configWatchResult{eventKind: config.EventDeleted, prevState: config.CreateConfig(config.Entries{"foo": "val2"}), newState: config.CreateConfig(nil), err: nil}
+			mockResultChan <- configWatchResult{prevState: config.CreateConfig(nil), newState: config.CreateConfig(nil), err: assert.AnError}
 		}()
 
 		go func() {
@@ -202,6 +362,7 @@ func TestDoguConfigRepository_Watch(t *testing.T) {
 					assert.NoError(t, result.Err)
 					assert.Equal(t, result.PrevState, config.DoguConfig{DoguName: "myDogu", Config: config.CreateConfig(config.Entries{"foo": "val2"})})
 					assert.Equal(t, result.NewState, config.DoguConfig{DoguName: "myDogu", Config: config.CreateConfig(nil)})
+					assert.Equal(t, config.EventDeleted, result.EventKind)
 				}
 
 				if i == 2 {
@@ -238,3 +399,159 @@ func TestDoguConfigRepository_Watch(t *testing.T) {
 		assert.ErrorContains(t, err, "unable to start watch for config from dogu myDogu:")
 	})
 }
+
+func TestDoguConfigRepository_AwaitAndWatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should watch config with initial state", func(t *testing.T) {
+		mockResultChan := make(chan configWatchResult)
+
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().awaitAndWatch(ctx, createConfigName("myDogu")).Return(mockResultChan, nil)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		resultChan, err := repo.AwaitAndWatch(ctx, "myDogu")
+		require.NoError(t, err)
+
+		go func() {
+			mockResultChan <- configWatchResult{eventKind: config.EventCreated, prevState: config.CreateConfig(nil), newState: config.CreateConfig(config.Entries{"foo": "val"})}
+			close(mockResultChan)
+		}()
+
+		result := <-resultChan
+		assert.NoError(t, result.Err)
+		assert.Equal(t, config.EventCreated, result.EventKind)
+		assert.Equal(t, config.DoguConfig{DoguName: "myDogu", Config: config.CreateConfig(nil)}, result.PrevState)
+		assert.Equal(t, config.DoguConfig{DoguName: "myDogu", Config: config.CreateConfig(config.Entries{"foo": "val"})}, result.NewState)
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should fail to watch config for error while starting watch", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().awaitAndWatch(ctx, createConfigName("myDogu")).Return(nil, assert.AnError)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		_, err := repo.AwaitAndWatch(ctx, "myDogu")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "unable to start watch for config from dogu myDogu:")
+	})
+}
+
+func TestDoguConfigRepository_List(t *testing.T) {
+	newDoguConfigMap := func(doguName, data string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      doguName + "-config",
+				Namespace: "ecosystem",
+				Labels:    map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: doguConfigType.String(), doguNameLabelKey: doguName},
+			},
+			Data: map[string]string{dataKeyName: data},
+		}
+	}
+
+	clientSet := fake.NewSimpleClientset(
+		newDoguConfigMap("cas", "logging:\n  root: INFO\n"),
+		newDoguConfigMap("cockpit", "url: https://example.com\n"),
+		newDoguConfigMap("ldap", "password_change:\n  enabled: \"true\"\n"),
+	)
+	repo := NewDoguConfigRepository(clientSet.CoreV1().ConfigMaps("ecosystem"))
+
+	t.Run("should list all dogu configs", func(t *testing.T) {
+		result, err := repo.List(context.TODO())
+
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		assert.Equal(t, config.SimpleDoguName("cas"), result["cas"].DoguName)
+		value, _ := result["cas"].Get("logging/root")
+		assert.Equal(t, config.Value("INFO"), value)
+		value, _ = result["ldap"].Get("password_change/enabled")
+		assert.Equal(t, config.Value("true"), value)
+	})
+
+	t.Run("should list dogu configs by selector", func(t *testing.T) {
+		selector, err := labels.Parse("dogu.name in (cas, ldap)")
+		require.NoError(t, err)
+
+		result, err := repo.ListBySelector(context.TODO(), selector)
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []config.SimpleDoguName{"cas", "ldap"}, maps.Keys(result))
+	})
+
+	t.Run("should list dogu configs by prefix", func(t *testing.T) {
+		result, err := repo.ListByPrefix(context.TODO(), "c")
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []config.SimpleDoguName{"cas", "cockpit"}, maps.Keys(result))
+	})
+
+	t.Run("should return error on list error", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().list(mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		errRepo := &DoguConfigRepository{generalConfigRepository: mConfigRepo}
+
+		_, err := errRepo.ListByPrefix(context.TODO(), "c")
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not list dogu configs")
+	})
+}
+
+func TestDoguConfigRepository_WatchAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should tag results with dogu name", func(t *testing.T) {
+		mockResultChan := make(chan configWatchResult)
+
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().watchAll(ctx, labels.Everything(), mock.AnythingOfType("config.WatchFilter")).Return(mockResultChan, nil)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		resultChan, err := repo.WatchAll(ctx, config.KeyFilter("foo"))
+		require.NoError(t, err)
+
+		go func() {
+			mockResultChan <- configWatchResult{doguName: "cas", prevState: config.CreateConfig(config.Entries{"foo": "val"}), newState: config.CreateConfig(config.Entries{"foo": "val2"})}
+			mockResultChan <- configWatchResult{doguName: "ldap", err: assert.AnError}
+			close(mockResultChan)
+		}()
+
+		result := <-resultChan
+		assert.NoError(t, result.Err)
+		assert.Equal(t, config.DoguConfig{DoguName: "cas", Config: config.CreateConfig(config.Entries{"foo": "val"})}, result.PrevState)
+		assert.Equal(t, config.DoguConfig{DoguName: "cas", Config: config.CreateConfig(config.Entries{"foo": "val2"})}, result.NewState)
+
+		result = <-resultChan
+		assert.ErrorIs(t, result.Err, assert.AnError)
+		assert.Equal(t, config.SimpleDoguName("ldap"), result.NewState.DoguName)
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should fail for error while starting watch", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().watchAll(ctx, labels.Everything()).Return(nil, assert.AnError)
+
+		repo := &DoguConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		_, err := repo.WatchAll(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "unable to start watch for all dogu configs")
+	})
+}
@@ -12,9 +12,10 @@ type GlobalConfigRepository struct {
 	generalConfigRepository
 }
 
-func NewGlobalConfigRepository(client ConfigMapClient) *GlobalConfigRepository {
-	cfgClient := createConfigMapClient(client, globalConfigType)
-	cfgRepository := newConfigRepo(cfgClient)
+func NewGlobalConfigRepository(client ConfigMapClient, opts ...ConfigRepositoryOption) *GlobalConfigRepository {
+	options := applyConfigRepositoryOptions(opts)
+	cfgClient := createConfigMapClient(client, globalConfigType, options.dataKey)
+	cfgRepository := newConfigRepo(cfgClient, opts...)
 
 	return &GlobalConfigRepository{
 		generalConfigRepository: cfgRepository,
@@ -54,8 +55,9 @@ func (gcr GlobalConfigRepository) Update(ctx context.Context, globalConfig confi
 	}, nil
 }
 
-func (gcr GlobalConfigRepository) SaveOrMerge(ctx context.Context, globalConfig config.GlobalConfig) (config.GlobalConfig, error) {
-	cfg, err := gcr.saveOrMerge(ctx, createConfigName(_SimpleGlobalConfigName), globalConfig.Config)
+func (gcr GlobalConfigRepository) SaveOrMerge(ctx context.Context, globalConfig config.GlobalConfig, opts ...SaveOrMergeOption) (config.GlobalConfig, error) {
+	options := applySaveOrMergeOptions(opts)
+	cfg, err := gcr.saveOrMerge(ctx, createConfigName(_SimpleGlobalConfigName), globalConfig.Config, options.strategy, nil)
 	if err != nil {
 		return config.GlobalConfig{}, fmt.Errorf("could not save and merge global config: %w", err)
 	}
@@ -76,6 +78,8 @@ func (gcr GlobalConfigRepository) Delete(ctx context.Context) error {
 type GlobalConfigWatchResult struct {
 	PrevState config.GlobalConfig
 	NewState  config.GlobalConfig
+	// EventKind tells whether the config was modified, created or deleted.
+	EventKind config.EventKind
 	Err       error
 }
 
@@ -85,6 +89,22 @@ func (gcr GlobalConfigRepository) Watch(ctx context.Context, filters ...config.W
 		return nil, fmt.Errorf("unable to start watch for global config: %w", err)
 	}
 
+	return toGlobalConfigWatchResults(cfgWatch), nil
+}
+
+// AwaitAndWatch works like Watch but does not fail if the global config does not exist yet. Instead, it waits for the
+// config to be created. The first result always contains the initial state of the config with an empty previous state
+// and the event kind config.EventCreated. Afterward, the watch continues like Watch.
+func (gcr GlobalConfigRepository) AwaitAndWatch(ctx context.Context, filters ...config.WatchFilter) (<-chan GlobalConfigWatchResult, error) {
+	cfgWatch, err := gcr.awaitAndWatch(ctx, createConfigName(_SimpleGlobalConfigName), filters...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start watch for global config: %w", err)
+	}
+
+	return toGlobalConfigWatchResults(cfgWatch), nil
+}
+
+func toGlobalConfigWatchResults(cfgWatch <-chan configWatchResult) <-chan GlobalConfigWatchResult {
 	watchChan := make(chan GlobalConfigWatchResult)
 
 	go func() {
@@ -93,10 +113,11 @@ func (gcr GlobalConfigRepository) Watch(ctx context.Context, filters ...config.W
 			watchChan <- GlobalConfigWatchResult{
 				PrevState: config.GlobalConfig{Config: result.prevState},
 				NewState:  config.GlobalConfig{Config: result.newState},
+				EventKind: result.eventKind,
 				Err:       result.err,
 			}
 		}
 	}()
 
-	return watchChan, nil
+	return watchChan
 }
@@ -16,6 +16,13 @@ func TestNewGlobalConfigRepository(t *testing.T) {
 	assert.NotNil(t, repo)
 }
 
+func TestNewGlobalConfigRepository_WithConverter(t *testing.T) {
+	mClient := NewMockConfigMapClient(t)
+	repo := NewGlobalConfigRepository(mClient, WithConverter(&config.JsonConverter{}))
+	assert.IsType(t, &config.JsonConverter{}, repo.generalConfigRepository.(configRepository).converter)
+	assert.Equal(t, "config.json", repo.generalConfigRepository.(configRepository).client.(configMapClient).dataKey)
+}
+
 func TestGlobalConfigRepository_Get(t *testing.T) {
 	t.Run("Get Global Config", func(t *testing.T) {
 		mConfigRepo := newMockGeneralConfigRepository(t)
@@ -99,7 +106,7 @@ func TestGlobalConfigRepository_Update(t *testing.T) {
 func TestGlobalConfigRepository_SaveOrMerge(t *testing.T) {
 	t.Run("Save&Merge Global Config", func(t *testing.T) {
 		mConfigRepo := newMockGeneralConfigRepository(t)
-		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_SimpleGlobalConfigName), mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_SimpleGlobalConfigName), mock.Anything, LocalWins, mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
 
 		repo := &GlobalConfigRepository{
 			generalConfigRepository: mConfigRepo,
@@ -110,9 +117,22 @@ func TestGlobalConfigRepository_SaveOrMerge(t *testing.T) {
 		assert.Equal(t, resourceVersion, gCfg.PersistenceContext)
 	})
 
+	t.Run("Save&Merge Global Config with merge strategy", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_SimpleGlobalConfigName), mock.Anything, FailOnConflict, mock.Anything).Return(config.Config{PersistenceContext: resourceVersion}, nil)
+
+		repo := &GlobalConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		gCfg, err := repo.SaveOrMerge(context.TODO(), config.CreateGlobalConfig(make(config.Entries)), WithMergeStrategy(FailOnConflict))
+		assert.NoError(t, err)
+		assert.Equal(t, resourceVersion, gCfg.PersistenceContext)
+	})
+
 	t.Run("Config repo error", func(t *testing.T) {
 		mConfigRepo := newMockGeneralConfigRepository(t)
-		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_SimpleGlobalConfigName), mock.Anything).Return(config.Config{}, assert.AnError)
+		mConfigRepo.EXPECT().saveOrMerge(mock.Anything, createConfigName(_SimpleGlobalConfigName), mock.Anything, LocalWins, mock.Anything).Return(config.Config{}, assert.AnError)
 
 		repo := &GlobalConfigRepository{
 			generalConfigRepository: mConfigRepo,
@@ -169,9 +189,9 @@ func TestGlobalConfigRepository_Watch(t *testing.T) {
 		cancel := make(chan bool, 1)
 
 		go func() {
-			mockResultChan <- configWatchResult{config.CreateConfig(config.Entries{"foo": "val"}), config.CreateConfig(config.Entries{"foo": "val2"}), nil}
-			mockResultChan <- configWatchResult{config.CreateConfig(config.Entries{"foo": "val2"}), config.CreateConfig(nil), nil}
-			mockResultChan <- configWatchResult{config.CreateConfig(nil), config.CreateConfig(nil), assert.AnError}
+			mockResultChan <- configWatchResult{prevState: config.CreateConfig(config.Entries{"foo": "val"}), newState: config.CreateConfig(config.Entries{"foo": "val2"}), err: nil}
+			mockResultChan <- configWatchResult{eventKind: config.EventDeleted, prevState: config.CreateConfig(config.Entries{"foo": "val2"}), newState: config.CreateConfig(nil), err: nil}
+			mockResultChan <- configWatchResult{prevState: config.CreateConfig(nil), newState: config.CreateConfig(nil), err: assert.AnError}
 		}()
 
 		go func() {
@@ -187,6 +207,7 @@ func TestGlobalConfigRepository_Watch(t *testing.T) {
 					assert.NoError(t, result.Err)
 					assert.Equal(t, result.PrevState, config.GlobalConfig{Config: config.CreateConfig(config.Entries{"foo": "val2"})})
 					assert.Equal(t, result.NewState, config.GlobalConfig{Config: config.CreateConfig(nil)})
+					assert.Equal(t, config.EventDeleted, result.EventKind)
 				}
 
 				if i == 2 {
@@ -223,3 +244,50 @@ func TestGlobalConfigRepository_Watch(t *testing.T) {
 		assert.ErrorContains(t, err, "unable to start watch for global config:")
 	})
 }
+
+func TestGlobalConfigRepository_AwaitAndWatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should watch config with initial state", func(t *testing.T) {
+		mockResultChan := make(chan configWatchResult)
+
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().awaitAndWatch(ctx, createConfigName(_SimpleGlobalConfigName), mock.AnythingOfType("config.WatchFilter")).Return(mockResultChan, nil)
+
+		repo := &GlobalConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		resultChan, err := repo.AwaitAndWatch(ctx, config.KeyFilter("foo"))
+		require.NoError(t, err)
+
+		go func() {
+			mockResultChan <- configWatchResult{eventKind: config.EventCreated, prevState: config.CreateConfig(nil), newState: config.CreateConfig(config.Entries{"foo": "val"})}
+			close(mockResultChan)
+		}()
+
+		result := <-resultChan
+		assert.NoError(t, result.Err)
+		assert.Equal(t, config.EventCreated, result.EventKind)
+		assert.Equal(t, config.GlobalConfig{Config: config.CreateConfig(nil)}, result.PrevState)
+		assert.Equal(t, config.GlobalConfig{Config: config.CreateConfig(config.Entries{"foo": "val"})}, result.NewState)
+
+		_, open := <-resultChan
+		assert.False(t, open)
+	})
+
+	t.Run("should fail to watch config for error while starting watch", func(t *testing.T) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().awaitAndWatch(ctx, createConfigName(_SimpleGlobalConfigName)).Return(nil, assert.AnError)
+
+		repo := &GlobalConfigRepository{
+			generalConfigRepository: mConfigRepo,
+		}
+
+		_, err := repo.AwaitAndWatch(ctx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "unable to start watch for global config:")
+	})
+}
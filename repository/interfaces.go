@@ -2,16 +2,27 @@ package repository
 
 import (
 	"context"
+	"github.com/cloudogu/cesapp-lib/core"
 	"github.com/cloudogu/k8s-registry-lib/config"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
+// DoguDescriptorGetter provides the descriptor of the currently installed version of a dogu.
+// It is implemented by the LocalRegistry of the dogu package.
+type DoguDescriptorGetter interface {
+	GetCurrent(ctx context.Context, simpleDoguName string) (*core.Dogu, error)
+}
+
 type generalConfigRepository interface {
 	get(context.Context, configName) (config.Config, error)
 	delete(context.Context, configName) error
 	create(context.Context, configName, config.SimpleDoguName, config.Config) (config.Config, error)
 	update(context.Context, configName, config.SimpleDoguName, config.Config) (config.Config, error)
-	saveOrMerge(context.Context, configName, config.Config) (config.Config, error)
+	saveOrMerge(context.Context, configName, config.Config, MergeStrategy, mergedConfigValidator) (config.Config, error)
+	list(context.Context, labels.Selector) (map[config.SimpleDoguName]config.Config, error)
 	watch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error)
+	awaitAndWatch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error)
+	watchAll(ctx context.Context, selector labels.Selector, filters ...config.WatchFilter) (<-chan configWatchResult, error)
 }
 
 type resourceVersionGetter interface {
@@ -21,9 +32,11 @@ type resourceVersionGetter interface {
 type configClient interface {
 	Get(ctx context.Context, name string) (clientData, error)
 	GetWithListResourceVersion(ctx context.Context, name string) (clientData, string, error)
+	List(ctx context.Context, selector labels.Selector) ([]clientData, string, error)
 	Delete(ctx context.Context, name string) error
 	Create(ctx context.Context, name string, doguName string, dataStr string) (resourceVersionGetter, error)
 	Update(ctx context.Context, pCtx string, name string, doguName string, dataStr string) (resourceVersionGetter, error)
 	UpdateClientData(ctx context.Context, update clientData) (resourceVersionGetter, error)
 	Watch(ctx context.Context, name string, resourceVersion string) (<-chan clientWatchResult, error)
+	WatchAll(ctx context.Context, selector labels.Selector, resourceVersion string) (<-chan clientWatchResult, error)
 }
@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+// lockObjectNamePrefix is prepended to the names of the objects that store locks, so that a lock cannot collide with
+// other config maps or leases in the namespace.
+const lockObjectNamePrefix = "k8s-registry-lock-"
+
+// Lock is a named mutex in the cluster that can be used to serialize operations of several components, e.g. dogu
+// upgrades and backups. The lock is held by a holder for a TTL and has to be renewed before the TTL expires.
+// Expired locks can be acquired by any other holder.
+// All changes use optimistic concurrency, so that only one holder can acquire the lock at a time.
+type Lock struct {
+	name string
+	// objectName is the name of the config map or lease that stores the lock.
+	objectName string
+	holder     string
+	ttl        time.Duration
+	client     lockClient
+	// now returns the current time to check the expiry of the lock. time.Now is used if it is not set.
+	now func() time.Time
+}
+
+// NewConfigMapLock creates a lock that is stored in a config map. The name of the config map is the name of the lock
+// with the prefix "k8s-registry-lock-".
+// GenericError if the TTL is not positive
+func NewConfigMapLock(client ConfigMapClient, name string, holder string, ttl time.Duration) (*Lock, error) {
+	return newLock(configMapLockClient{client}, name, holder, ttl)
+}
+
+// NewLeaseLock creates a lock that is stored in a coordination.k8s.io lease. The name of the lease is the name of the
+// lock with the prefix "k8s-registry-lock-".
+// Leases only support TTLs in whole seconds, so the TTL is rounded up.
+// GenericError if the TTL is not positive
+func NewLeaseLock(client LeaseClient, name string, holder string, ttl time.Duration) (*Lock, error) {
+	return newLock(leaseLockClient{client}, name, holder, ttl)
+}
+
+func newLock(client lockClient, name string, holder string, ttl time.Duration) (*Lock, error) {
+	// a lock without TTL would be expired right away and could be acquired by every other holder
+	if ttl <= 0 {
+		return nil, errors.NewGenericError(fmt.Errorf("ttl of lock %s must be positive, got %s", name, ttl))
+	}
+
+	return &Lock{
+		name:       name,
+		objectName: lockObjectNamePrefix + name,
+		holder:     holder,
+		ttl:        ttl,
+		client:     client,
+		now:        time.Now,
+	}, nil
+}
+
+// LockStatus describes the current state of a lock.
+// Holder, AcquiredAt, RenewedAt and ExpiresAt are only set if the lock is held.
+type LockStatus struct {
+	Held       bool
+	Holder     string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// IsExpired returns true if the lock is held and its TTL has expired at the given time.
+// Expired locks are still held until they are acquired by another holder or released.
+func (ls LockStatus) IsExpired(now time.Time) bool {
+	return ls.Held && !now.Before(ls.ExpiresAt)
+}
+
+// Get returns the current status of the lock.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (l *Lock) Get(ctx context.Context) (LockStatus, error) {
+	record, err := l.client.Get(ctx, l.objectName)
+	if errors.IsNotFoundError(err) {
+		return LockStatus{}, nil
+	}
+	if err != nil {
+		return LockStatus{}, fmt.Errorf("could not get lock %s: %w", l.name, err)
+	}
+
+	if record.holder == "" {
+		return LockStatus{}, nil
+	}
+
+	return LockStatus{
+		Held:       true,
+		Holder:     record.holder,
+		AcquiredAt: record.acquireTime,
+		RenewedAt:  record.renewTime,
+		ExpiresAt:  record.expiresAt(),
+	}, nil
+}
+
+// TryAcquire acquires the lock if it is free, expired or already held by this holder, and returns false if another
+// holder holds it. Acquiring a lock that is already held by this holder renews it.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (l *Lock) TryAcquire(ctx context.Context) (bool, error) {
+	_, acquired, err := l.tryAcquire(ctx)
+
+	return acquired, err
+}
+
+// Acquire blocks until the lock is acquired or the context is done. Instead of polling, it watches the lock and tries
+// again as soon as the lock is changed, released or expired. The error of the context is returned if it is done.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (l *Lock) Acquire(ctx context.Context) error {
+	watchBackoff := newLockWatchBackoff()
+	for {
+		record, acquired, err := l.tryAcquire(ctx)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+
+		err = l.waitForChange(ctx, record, &watchBackoff)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// tryAcquire returns the current record of the lock if it is held by another holder.
+func (l *Lock) tryAcquire(ctx context.Context) (lockRecord, bool, error) {
+	var current lockRecord
+	acquired := false
+
+	err := retry.OnError(retry.DefaultRetry, isLockUpdateConflict, func() error {
+		var err error
+		current, acquired, err = l.tryAcquireOnce(ctx)
+
+		return err
+	})
+	if err != nil {
+		return lockRecord{}, false, fmt.Errorf("could not acquire lock %s for holder %s: %w", l.name, l.holder, err)
+	}
+
+	return current, acquired, nil
+}
+
+func (l *Lock) tryAcquireOnce(ctx context.Context) (lockRecord, bool, error) {
+	now := l.currentTime()
+
+	current, err := l.client.Get(ctx, l.objectName)
+	if errors.IsNotFoundError(err) {
+		_, err = l.client.Create(ctx, l.objectName, l.newRecord(now))
+		if err != nil {
+			return lockRecord{}, false, err
+		}
+
+		return lockRecord{}, true, nil
+	}
+	if err != nil {
+		return lockRecord{}, false, err
+	}
+
+	if current.holder != "" && current.holder != l.holder && !current.isExpired(now) {
+		return current, false, nil
+	}
+
+	updated := l.newRecord(now)
+	updated.resourceVersion = current.resourceVersion
+	if current.holder == l.holder {
+		updated.acquireTime = current.acquireTime
+	}
+
+	_, err = l.client.Update(ctx, l.objectName, updated)
+	if err != nil {
+		return lockRecord{}, false, err
+	}
+
+	return lockRecord{}, true, nil
+}
+
+func (l *Lock) newRecord(now time.Time) lockRecord {
+	return lockRecord{
+		holder:        l.holder,
+		acquireTime:   now,
+		renewTime:     now,
+		leaseDuration: l.ttl,
+	}
+}
+
+// isLockUpdateConflict returns true if another holder changed the lock concurrently, so that the lock has to be
+// checked again.
+func isLockUpdateConflict(err error) bool {
+	return errors.IsConflictError(err) || errors.IsAlreadyExistsError(err)
+}
+
+// newLockWatchBackoff returns the delays between the attempts to acquire a lock whose watch failed.
+func newLockWatchBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: 100 * time.Millisecond,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    10,
+		Cap:      10 * time.Second,
+	}
+}
+
+// waitForChange blocks until the lock is changed or deleted, the record expires or the context is done.
+// If the watch fails, e.g. because its resource version is too old, it waits for the next delay of the backoff
+// instead, so that the lock is not checked again right away.
+func (l *Lock) waitForChange(ctx context.Context, record lockRecord, watchBackoff *wait.Backoff) error {
+	expiryTimer := time.NewTimer(record.expiresAt().Sub(l.currentTime()))
+	defer expiryTimer.Stop()
+
+	var changes <-chan watch.Event
+	if record.resourceVersion != "" {
+		nameSelector := func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", l.objectName).String()
+		}
+
+		watcher, err := createRetryWatcher(ctx, l.client, l.objectName, record.resourceVersion, nameSelector)
+		if err != nil {
+			return fmt.Errorf("could not wait for lock %s: %w", l.name, err)
+		}
+		defer watcher.Stop()
+
+		changes = watcher.ResultChan()
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for lock %s: %w", l.name, ctx.Err())
+	case <-expiryTimer.C:
+		return nil
+	case event, ok := <-changes:
+		if ok && event.Type != watch.Error {
+			return nil
+		}
+	}
+
+	retryTimer := time.NewTimer(watchBackoff.Step())
+	defer retryTimer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for lock %s: %w", l.name, ctx.Err())
+	case <-expiryTimer.C:
+	case <-retryTimer.C:
+	}
+
+	return nil
+}
+
+// Renew extends the TTL of the lock. The lock can be renewed after its TTL expired, as long as no other holder
+// acquired it in the meantime.
+// NotFoundError if the lock is not held
+// ConflictError if another holder holds the lock
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (l *Lock) Renew(ctx context.Context) error {
+	var holder string
+	err := retry.OnError(retry.DefaultRetry, errors.IsConflictError, func() error {
+		var err error
+		holder, err = l.tryRenew(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("could not renew lock %s: %w", l.name, err)
+	}
+
+	if holder == "" {
+		return errors.NewNotFoundError(fmt.Errorf("lock %s is not held", l.name))
+	}
+	if holder != l.holder {
+		return errors.NewConflictError(fmt.Errorf("lock %s is held by holder: %s", l.name, holder))
+	}
+
+	return nil
+}
+
+// tryRenew renews the lock if it is held by this holder and returns the current holder of the lock.
+func (l *Lock) tryRenew(ctx context.Context) (string, error) {
+	current, err := l.client.Get(ctx, l.objectName)
+	if errors.IsNotFoundError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if current.holder != l.holder {
+		return current.holder, nil
+	}
+
+	current.renewTime = l.currentTime()
+	current.leaseDuration = l.ttl
+
+	_, err = l.client.Update(ctx, l.objectName, current)
+	if err != nil {
+		return "", err
+	}
+
+	return current.holder, nil
+}
+
+// Release frees the lock, so that other holders can acquire it. Releasing a lock that is not held does nothing.
+// ConflictError if another holder holds the lock
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (l *Lock) Release(ctx context.Context) error {
+	current, err := l.client.Get(ctx, l.objectName)
+	if errors.IsNotFoundError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get lock %s for release: %w", l.name, err)
+	}
+
+	if current.holder == "" {
+		return nil
+	}
+	if current.holder != l.holder {
+		return errors.NewConflictError(fmt.Errorf("lock %s is held by holder: %s", l.name, current.holder))
+	}
+
+	err = l.client.Delete(ctx, l.objectName, current.resourceVersion)
+	if err != nil && !errors.IsNotFoundError(err) {
+		return fmt.Errorf("could not release lock %s: %w", l.name, err)
+	}
+
+	return nil
+}
+
+func (l *Lock) currentTime() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+
+	return l.now()
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+const (
+	lockTypeLabelValue = "lock"
+
+	lockHolderKey        = "holder"
+	lockAcquireTimeKey   = "acquireTime"
+	lockRenewTimeKey     = "renewTime"
+	lockLeaseDurationKey = "leaseDuration"
+)
+
+// lockRecord is the state of a lock, independent of the object it is stored in.
+type lockRecord struct {
+	holder          string
+	acquireTime     time.Time
+	renewTime       time.Time
+	leaseDuration   time.Duration
+	resourceVersion string
+}
+
+func (lr lockRecord) expiresAt() time.Time {
+	return lr.renewTime.Add(lr.leaseDuration)
+}
+
+func (lr lockRecord) isExpired(now time.Time) bool {
+	return !now.Before(lr.expiresAt())
+}
+
+// lockClient stores lock records with optimistic concurrency. Update and Delete fail with a ConflictError if the
+// resource version of the record is outdated.
+type lockClient interface {
+	clientWatcher
+	Get(ctx context.Context, name string) (lockRecord, error)
+	Create(ctx context.Context, name string, record lockRecord) (lockRecord, error)
+	Update(ctx context.Context, name string, record lockRecord) (lockRecord, error)
+	Delete(ctx context.Context, name string, resourceVersion string) error
+}
+
+type configMapLockClient struct {
+	ConfigMapClient
+}
+
+var _ lockClient = configMapLockClient{}
+
+func (cmlc configMapLockClient) Get(ctx context.Context, name string) (lockRecord, error) {
+	cm, err := cmlc.ConfigMapClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("unable to get lock config-map from cluster: %w", handleError(err))
+	}
+
+	return parseLockConfigMap(cm)
+}
+
+func (cmlc configMapLockClient) Create(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	cm, err := cmlc.ConfigMapClient.Create(ctx, createLockConfigMap(name, record), metav1.CreateOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("could not create lock config-map in cluster: %w", handleError(err))
+	}
+
+	return parseLockConfigMap(cm)
+}
+
+func (cmlc configMapLockClient) Update(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	cm, err := cmlc.ConfigMapClient.Update(ctx, createLockConfigMap(name, record), metav1.UpdateOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("could not update lock config-map in cluster: %w", handleError(err))
+	}
+
+	return parseLockConfigMap(cm)
+}
+
+func (cmlc configMapLockClient) Delete(ctx context.Context, name string, resourceVersion string) error {
+	err := cmlc.ConfigMapClient.Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion}})
+	if err != nil {
+		return fmt.Errorf("could not delete lock config-map in cluster: %w", handleError(err))
+	}
+
+	return nil
+}
+
+func createLockConfigMap(name string, record lockRecord) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: record.resourceVersion,
+			Labels: map[string]string{
+				appLabelKey:  appLabelValueCes,
+				typeLabelKey: lockTypeLabelValue,
+			},
+		},
+		Data: map[string]string{
+			lockHolderKey:        record.holder,
+			lockAcquireTimeKey:   record.acquireTime.UTC().Format(time.RFC3339Nano),
+			lockRenewTimeKey:     record.renewTime.UTC().Format(time.RFC3339Nano),
+			lockLeaseDurationKey: record.leaseDuration.String(),
+		},
+	}
+}
+
+func parseLockConfigMap(cm *v1.ConfigMap) (lockRecord, error) {
+	acquireTime, err := time.Parse(time.RFC3339Nano, cm.Data[lockAcquireTimeKey])
+	if err != nil {
+		return lockRecord{}, errors.NewGenericError(fmt.Errorf("failed to parse acquire time of lock %s: %w", cm.Name, err))
+	}
+
+	renewTime, err := time.Parse(time.RFC3339Nano, cm.Data[lockRenewTimeKey])
+	if err != nil {
+		return lockRecord{}, errors.NewGenericError(fmt.Errorf("failed to parse renew time of lock %s: %w", cm.Name, err))
+	}
+
+	leaseDuration, err := time.ParseDuration(cm.Data[lockLeaseDurationKey])
+	if err != nil {
+		return lockRecord{}, errors.NewGenericError(fmt.Errorf("failed to parse lease duration of lock %s: %w", cm.Name, err))
+	}
+
+	return lockRecord{
+		holder:          cm.Data[lockHolderKey],
+		acquireTime:     acquireTime,
+		renewTime:       renewTime,
+		leaseDuration:   leaseDuration,
+		resourceVersion: cm.ResourceVersion,
+	}, nil
+}
+
+type LeaseClient interface {
+	coordinationv1client.LeaseInterface
+}
+
+type leaseLockClient struct {
+	LeaseClient
+}
+
+var _ lockClient = leaseLockClient{}
+
+func (llc leaseLockClient) Get(ctx context.Context, name string) (lockRecord, error) {
+	lease, err := llc.LeaseClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("unable to get lease from cluster: %w", handleError(err))
+	}
+
+	return parseLease(lease), nil
+}
+
+func (llc leaseLockClient) Create(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	lease, err := llc.LeaseClient.Create(ctx, createLease(name, record), metav1.CreateOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("could not create lease in cluster: %w", handleError(err))
+	}
+
+	return parseLease(lease), nil
+}
+
+func (llc leaseLockClient) Update(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	lease, err := llc.LeaseClient.Update(ctx, createLease(name, record), metav1.UpdateOptions{})
+	if err != nil {
+		return lockRecord{}, fmt.Errorf("could not update lease in cluster: %w", handleError(err))
+	}
+
+	return parseLease(lease), nil
+}
+
+func (llc leaseLockClient) Delete(ctx context.Context, name string, resourceVersion string) error {
+	err := llc.LeaseClient.Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion}})
+	if err != nil {
+		return fmt.Errorf("could not delete lease in cluster: %w", handleError(err))
+	}
+
+	return nil
+}
+
+// createLease stores the record in the spec of a lease. Leases only support lease durations in whole seconds, so the
+// duration is rounded up.
+func createLease(name string, record lockRecord) *coordinationv1.Lease {
+	holder := record.holder
+	leaseDurationSeconds := int32(math.Ceil(record.leaseDuration.Seconds()))
+	acquireTime := metav1.NewMicroTime(record.acquireTime)
+	renewTime := metav1.NewMicroTime(record.renewTime)
+
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: record.resourceVersion,
+			Labels: map[string]string{
+				appLabelKey:  appLabelValueCes,
+				typeLabelKey: lockTypeLabelValue,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			AcquireTime:          &acquireTime,
+			RenewTime:            &renewTime,
+		},
+	}
+}
+
+func parseLease(lease *coordinationv1.Lease) lockRecord {
+	record := lockRecord{resourceVersion: lease.ResourceVersion}
+	if lease.Spec.HolderIdentity != nil {
+		record.holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		record.leaseDuration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	if lease.Spec.AcquireTime != nil {
+		record.acquireTime = lease.Spec.AcquireTime.Time
+	}
+	if lease.Spec.RenewTime != nil {
+		record.renewTime = lease.Spec.RenewTime.Time
+	}
+
+	return record
+}
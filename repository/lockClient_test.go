@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+func Test_configMapLockClient(t *testing.T) {
+	t.Run("should store lock record in config map", func(t *testing.T) {
+		// given
+		now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+		client := newOptimisticLockingLockClientSet().CoreV1().ConfigMaps("ecosystem")
+		sut := configMapLockClient{client}
+		record := lockRecord{holder: "k8s-dogu-operator", acquireTime: now, renewTime: now, leaseDuration: 1500 * time.Millisecond}
+
+		// when
+		created, err := sut.Create(testCtx, "dogu-upgrade", record)
+
+		// then
+		require.NoError(t, err)
+		record.resourceVersion = "1"
+		assert.Equal(t, record, created)
+
+		cm, err := client.Get(testCtx, "dogu-upgrade", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			lockHolderKey:        "k8s-dogu-operator",
+			lockAcquireTimeKey:   "2024-10-19T02:00:00Z",
+			lockRenewTimeKey:     "2024-10-19T02:00:00Z",
+			lockLeaseDurationKey: "1.5s",
+		}, cm.Data)
+		assert.Equal(t, lockTypeLabelValue, cm.Labels[typeLabelKey])
+	})
+
+	t.Run("should fail to update outdated lock record", func(t *testing.T) {
+		// given
+		sut := configMapLockClient{newOptimisticLockingLockClientSet().CoreV1().ConfigMaps("ecosystem")}
+		record := lockRecord{holder: "k8s-dogu-operator"}
+		_, err := sut.Create(testCtx, "dogu-upgrade", record)
+		require.NoError(t, err)
+
+		// when
+		_, err = sut.Update(testCtx, "dogu-upgrade", record)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsConflictError(err))
+	})
+
+	t.Run("should fail to parse invalid lock record", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			data    map[string]string
+			wantErr string
+		}{
+			{name: "acquire time", data: map[string]string{}, wantErr: "failed to parse acquire time of lock dogu-upgrade"},
+			{name: "renew time", data: map[string]string{lockAcquireTimeKey: "2024-10-19T02:00:00Z"}, wantErr: "failed to parse renew time of lock dogu-upgrade"},
+			{
+				name:    "lease duration",
+				data:    map[string]string{lockAcquireTimeKey: "2024-10-19T02:00:00Z", lockRenewTimeKey: "2024-10-19T02:00:00Z"},
+				wantErr: "failed to parse lease duration of lock dogu-upgrade",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := parseLockConfigMap(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "dogu-upgrade"}, Data: tt.data})
+
+				require.Error(t, err)
+				assert.True(t, errors.IsGenericError(err))
+				assert.ErrorContains(t, err, tt.wantErr)
+			})
+		}
+	})
+}
+
+func Test_leaseLockClient(t *testing.T) {
+	t.Run("should store lock record in lease", func(t *testing.T) {
+		// given
+		now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+		client := newOptimisticLockingLockClientSet().CoordinationV1().Leases("ecosystem")
+		sut := leaseLockClient{client}
+
+		// when
+		created, err := sut.Create(testCtx, "dogu-upgrade", lockRecord{holder: "k8s-dogu-operator", acquireTime: now, renewTime: now, leaseDuration: 1500 * time.Millisecond})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, lockRecord{holder: "k8s-dogu-operator", acquireTime: now, renewTime: now, leaseDuration: 2 * time.Second, resourceVersion: "1"}, created)
+
+		lease, err := client.Get(testCtx, "dogu-upgrade", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "k8s-dogu-operator", *lease.Spec.HolderIdentity)
+		assert.Equal(t, int32(2), *lease.Spec.LeaseDurationSeconds)
+	})
+
+	t.Run("should parse lease without holder", func(t *testing.T) {
+		record := parseLease(&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "dogu-upgrade", ResourceVersion: "3"}})
+
+		assert.Equal(t, lockRecord{resourceVersion: "3"}, record)
+	})
+}
@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+// newOptimisticLockingLockClientSet creates a fake clientset that sets resource versions on creation and rejects
+// outdated updates of config maps and leases.
+func newOptimisticLockingLockClientSet() *fake.Clientset {
+	clientSet := fake.NewSimpleClientset()
+
+	for _, gvr := range []schema.GroupVersionResource{
+		v1.SchemeGroupVersion.WithResource("configmaps"),
+		coordinationv1.SchemeGroupVersion.WithResource("leases"),
+	} {
+		clientSet.PrependReactor("create", gvr.Resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			created := action.(k8stesting.CreateAction).GetObject().DeepCopyObject()
+			accessor, _ := meta.Accessor(created)
+			accessor.SetNamespace(action.GetNamespace())
+			accessor.SetResourceVersion("1")
+
+			return true, created, clientSet.Tracker().Create(gvr, created, action.GetNamespace())
+		})
+
+		clientSet.PrependReactor("update", gvr.Resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updated := action.(k8stesting.UpdateAction).GetObject().DeepCopyObject()
+			accessor, _ := meta.Accessor(updated)
+			accessor.SetNamespace(action.GetNamespace())
+
+			current, err := clientSet.Tracker().Get(gvr, action.GetNamespace(), accessor.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+
+			currentAccessor, _ := meta.Accessor(current)
+			if accessor.GetResourceVersion() != currentAccessor.GetResourceVersion() {
+				return true, nil, k8serrors.NewConflict(gvr.GroupResource(), accessor.GetName(), fmt.Errorf("resource version %s is outdated", accessor.GetResourceVersion()))
+			}
+
+			version, _ := strconv.Atoi(currentAccessor.GetResourceVersion())
+			accessor.SetResourceVersion(strconv.Itoa(version + 1))
+
+			return true, updated, clientSet.Tracker().Update(gvr, updated, action.GetNamespace())
+		})
+	}
+
+	return clientSet
+}
+
+type lockFactory func(t *testing.T, clientSet *fake.Clientset, holder string, ttl time.Duration) *Lock
+
+var lockFactories = map[string]lockFactory{
+	"config map": func(t *testing.T, clientSet *fake.Clientset, holder string, ttl time.Duration) *Lock {
+		lock, err := NewConfigMapLock(clientSet.CoreV1().ConfigMaps("ecosystem"), "dogu-upgrade", holder, ttl)
+		require.NoError(t, err)
+
+		return lock
+	},
+	"lease": func(t *testing.T, clientSet *fake.Clientset, holder string, ttl time.Duration) *Lock {
+		lock, err := NewLeaseLock(clientSet.CoordinationV1().Leases("ecosystem"), "dogu-upgrade", holder, ttl)
+		require.NoError(t, err)
+
+		return lock
+	},
+}
+
+func TestNewLock(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		_, err := NewConfigMapLock(clientSet.CoreV1().ConfigMaps("ecosystem"), "dogu-upgrade", "k8s-dogu-operator", ttl)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "ttl of lock dogu-upgrade must be positive")
+
+		_, err = NewLeaseLock(clientSet.CoordinationV1().Leases("ecosystem"), "dogu-upgrade", "k8s-dogu-operator", ttl)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "ttl of lock dogu-upgrade must be positive")
+	}
+}
+
+func TestLock_TryAcquire(t *testing.T) {
+	for backend, newLock := range lockFactories {
+		t.Run(backend, func(t *testing.T) {
+			t.Run("should acquire free lock only once", func(t *testing.T) {
+				// given
+				now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+				clientSet := newOptimisticLockingLockClientSet()
+				upgrade := newLock(t, clientSet, "k8s-dogu-operator", time.Minute)
+				upgrade.now = func() time.Time { return now }
+				backup := newLock(t, clientSet, "k8s-backup-operator", time.Minute)
+				backup.now = func() time.Time { return now }
+
+				// when
+				upgradeAcquired, upgradeErr := upgrade.TryAcquire(testCtx)
+				backupAcquired, backupErr := backup.TryAcquire(testCtx)
+
+				// then
+				require.NoError(t, upgradeErr)
+				require.NoError(t, backupErr)
+				assert.True(t, upgradeAcquired)
+				assert.False(t, backupAcquired)
+
+				status, err := backup.Get(testCtx)
+				require.NoError(t, err)
+				assert.Equal(t, LockStatus{
+					Held:       true,
+					Holder:     "k8s-dogu-operator",
+					AcquiredAt: now,
+					RenewedAt:  now,
+					ExpiresAt:  now.Add(time.Minute),
+				}, status)
+				assert.False(t, status.IsExpired(now))
+			})
+
+			t.Run("should renew lock on acquiring it again", func(t *testing.T) {
+				// given
+				now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+				sut := newLock(t, newOptimisticLockingLockClientSet(), "k8s-dogu-operator", time.Minute)
+				sut.now = func() time.Time { return now }
+				_, err := sut.TryAcquire(testCtx)
+				require.NoError(t, err)
+				acquiredAt := now
+
+				// when
+				now = now.Add(30 * time.Second)
+				acquired, err := sut.TryAcquire(testCtx)
+
+				// then
+				require.NoError(t, err)
+				assert.True(t, acquired)
+				status, err := sut.Get(testCtx)
+				require.NoError(t, err)
+				assert.Equal(t, acquiredAt, status.AcquiredAt)
+				assert.Equal(t, now.Add(time.Minute), status.ExpiresAt)
+			})
+
+			t.Run("should acquire expired lock of other holder", func(t *testing.T) {
+				// given
+				now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+				clientSet := newOptimisticLockingLockClientSet()
+				upgrade := newLock(t, clientSet, "k8s-dogu-operator", time.Minute)
+				upgrade.now = func() time.Time { return now }
+				backup := newLock(t, clientSet, "k8s-backup-operator", time.Minute)
+				backup.now = func() time.Time { return now }
+				_, err := upgrade.TryAcquire(testCtx)
+				require.NoError(t, err)
+
+				// when
+				now = now.Add(time.Minute)
+				acquired, err := backup.TryAcquire(testCtx)
+
+				// then
+				require.NoError(t, err)
+				assert.True(t, acquired)
+				status, err := backup.Get(testCtx)
+				require.NoError(t, err)
+				assert.Equal(t, "k8s-backup-operator", status.Holder)
+				assert.Equal(t, now, status.AcquiredAt)
+
+				renewErr := upgrade.Renew(testCtx)
+				assert.True(t, errors.IsConflictError(renewErr))
+			})
+		})
+	}
+}
+
+func TestLock_Renew(t *testing.T) {
+	for backend, newLock := range lockFactories {
+		t.Run(backend, func(t *testing.T) {
+			// given
+			now := time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+			clientSet := newOptimisticLockingLockClientSet()
+			sut := newLock(t, clientSet, "k8s-dogu-operator", time.Minute)
+			sut.now = func() time.Time { return now }
+			other := newLock(t, clientSet, "k8s-backup-operator", time.Minute)
+
+			// when not acquired
+			err := sut.Renew(testCtx)
+
+			// then
+			assert.True(t, errors.IsNotFoundError(err))
+
+			// given
+			_, err = sut.TryAcquire(testCtx)
+			require.NoError(t, err)
+
+			// when
+			now = now.Add(2 * time.Minute)
+			err = sut.Renew(testCtx)
+
+			// then
+			require.NoError(t, err)
+			status, err := sut.Get(testCtx)
+			require.NoError(t, err)
+			assert.Equal(t, now.Add(time.Minute), status.ExpiresAt)
+
+			// when renewed by other holder
+			err = other.Renew(testCtx)
+
+			// then
+			require.Error(t, err)
+			assert.True(t, errors.IsConflictError(err))
+			assert.ErrorContains(t, err, "lock dogu-upgrade is held by holder: k8s-dogu-operator")
+		})
+	}
+
+	for backend, newLock := range lockFactories {
+		t.Run(backend+" should retry on concurrent update", func(t *testing.T) {
+			// given
+			clientSet := newOptimisticLockingLockClientSet()
+			sut := newLock(t, clientSet, "k8s-dogu-operator", time.Minute)
+			_, err := sut.TryAcquire(testCtx)
+			require.NoError(t, err)
+
+			conflicts := 0
+			clientSet.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if conflicts >= 2 {
+					return false, nil, nil
+				}
+
+				conflicts++
+				return true, nil, k8serrors.NewConflict(action.GetResource().GroupResource(), "k8s-registry-lock-dogu-upgrade", assert.AnError)
+			})
+
+			// when
+			err = sut.Renew(testCtx)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 2, conflicts)
+		})
+	}
+}
+
+func TestLock_objectName(t *testing.T) {
+	clientSet := newOptimisticLockingLockClientSet()
+	for _, newLock := range lockFactories {
+		_, err := newLock(t, clientSet, "k8s-dogu-operator", time.Minute).TryAcquire(testCtx)
+		require.NoError(t, err)
+	}
+
+	_, err := clientSet.CoreV1().ConfigMaps("ecosystem").Get(testCtx, "k8s-registry-lock-dogu-upgrade", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = clientSet.CoordinationV1().Leases("ecosystem").Get(testCtx, "k8s-registry-lock-dogu-upgrade", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestLock_Release(t *testing.T) {
+	for backend, newLock := range lockFactories {
+		t.Run(backend, func(t *testing.T) {
+			// given
+			clientSet := newOptimisticLockingLockClientSet()
+			sut := newLock(t, clientSet, "k8s-dogu-operator", time.Minute)
+			other := newLock(t, clientSet, "k8s-backup-operator", time.Minute)
+
+			// when not acquired
+			err := sut.Release(testCtx)
+
+			// then
+			require.NoError(t, err)
+
+			// given
+			_, err = sut.TryAcquire(testCtx)
+			require.NoError(t, err)
+
+			// when released by other holder
+			err = other.Release(testCtx)
+
+			// then
+			assert.True(t, errors.IsConflictError(err))
+
+			// when
+			err = sut.Release(testCtx)
+
+			// then
+			require.NoError(t, err)
+			status, err := sut.Get(testCtx)
+			require.NoError(t, err)
+			assert.False(t, status.Held)
+
+			acquired, err := other.TryAcquire(testCtx)
+			require.NoError(t, err)
+			assert.True(t, acquired)
+		})
+	}
+}
+
+func TestLock_Acquire(t *testing.T) {
+	for backend, newLock := range lockFactories {
+		t.Run(backend, func(t *testing.T) {
+			t.Run("should acquire lock after release", func(t *testing.T) {
+				// given
+				clientSet := newOptimisticLockingLockClientSet()
+				upgrade := newLock(t, clientSet, "k8s-dogu-operator", time.Hour)
+				backup := newLock(t, clientSet, "k8s-backup-operator", time.Hour)
+				require.NoError(t, upgrade.Acquire(testCtx))
+
+				acquireErr := make(chan error)
+				go func() {
+					acquireErr <- backup.Acquire(testCtx)
+				}()
+
+				// when the backup waits for the lock
+				assert.Eventually(t, func() bool {
+					for _, action := range clientSet.Actions() {
+						if action.GetVerb() == "watch" {
+							return true
+						}
+					}
+					return false
+				}, 5*time.Second, 10*time.Millisecond)
+				require.NoError(t, upgrade.Release(testCtx))
+
+				// then
+				select {
+				case err := <-acquireErr:
+					require.NoError(t, err)
+				case <-time.After(5 * time.Second):
+					t.Fatal("lock was not acquired after release")
+				}
+				status, err := backup.Get(testCtx)
+				require.NoError(t, err)
+				assert.Equal(t, "k8s-backup-operator", status.Holder)
+			})
+
+			t.Run("should acquire lock after expiry", func(t *testing.T) {
+				// given
+				clientSet := newOptimisticLockingLockClientSet()
+				require.NoError(t, newLock(t, clientSet, "k8s-dogu-operator", time.Second).Acquire(testCtx))
+				sut := newLock(t, clientSet, "k8s-backup-operator", time.Minute)
+
+				// when
+				err := sut.Acquire(testCtx)
+
+				// then
+				require.NoError(t, err)
+				status, err := sut.Get(testCtx)
+				require.NoError(t, err)
+				assert.Equal(t, "k8s-backup-operator", status.Holder)
+			})
+
+			t.Run("should stop waiting when context is done", func(t *testing.T) {
+				// given
+				clientSet := newOptimisticLockingLockClientSet()
+				require.NoError(t, newLock(t, clientSet, "k8s-dogu-operator", time.Hour).Acquire(testCtx))
+				ctx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+				defer cancel()
+
+				// when
+				err := newLock(t, clientSet, "k8s-backup-operator", time.Hour).Acquire(ctx)
+
+				// then
+				require.Error(t, err)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			})
+
+			t.Run("should back off if watch is closed", func(t *testing.T) {
+				// given
+				clientSet := newOptimisticLockingLockClientSet()
+				require.NoError(t, newLock(t, clientSet, "k8s-dogu-operator", time.Hour).Acquire(testCtx))
+				clientSet.PrependWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+					// the retry watcher closes after this error, as the watch cannot be resumed
+					watcher := watch.NewFakeWithChanSize(1, false)
+					watcher.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonGone})
+
+					return true, watcher, nil
+				})
+				ctx, cancel := context.WithTimeout(testCtx, time.Second)
+				defer cancel()
+
+				// when
+				err := newLock(t, clientSet, "k8s-backup-operator", time.Hour).Acquire(ctx)
+
+				// then
+				require.Error(t, err)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				watches := 0
+				for _, action := range clientSet.Actions() {
+					if action.GetVerb() == "watch" {
+						watches++
+					}
+				}
+				assert.GreaterOrEqual(t, watches, 2)
+				assert.LessOrEqual(t, watches, 5)
+			})
+
+			t.Run("should serialize concurrent holders", func(t *testing.T) {
+				// given
+				clientSet := newOptimisticLockingLockClientSet()
+				var holding atomic.Int32
+				var overlapped atomic.Bool
+				var wg sync.WaitGroup
+
+				// when
+				for i := 0; i < 5; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						sut := newLock(t, clientSet, fmt.Sprintf("holder-%d", i), 2*time.Second)
+						assert.NoError(t, sut.Acquire(testCtx))
+
+						if holding.Add(1) > 1 {
+							overlapped.Store(true)
+						}
+						time.Sleep(10 * time.Millisecond)
+						holding.Add(-1)
+
+						assert.NoError(t, sut.Release(testCtx))
+					}(i)
+				}
+				wg.Wait()
+
+				// then
+				assert.False(t, overlapped.Load())
+			})
+		})
+	}
+}
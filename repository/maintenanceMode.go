@@ -3,22 +3,125 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
 	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/cloudogu/k8s-registry-lib/errors"
 )
 
-const registryKeyMaintenance = "maintenance"
+const (
+	registryKeyMaintenance      = "maintenance"
+	registryKeyMaintenanceAudit = "maintenance_audit"
+)
 
 // MaintenanceModeDescription contains data that gets displayed when the maintenance mode is active.
+// Title and Text are the default, which is also displayed by readers without support for localization. Localized
+// titles and texts are stored in Translations, keyed by language tags like "de" or "en-US".
 type MaintenanceModeDescription struct {
-	Title string
-	Text  string
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	// FallbackLanguage is the language tag of the translation that is used if no translation matches the requested
+	// language. The default title and text are used if it is empty.
+	FallbackLanguage string                                         `json:"fallbackLanguage,omitempty"`
+	Translations     map[string]LocalizedMaintenanceModeDescription `json:"translations,omitempty"`
+}
+
+// LocalizedMaintenanceModeDescription contains the title and text of a maintenance mode description in a single language.
+type LocalizedMaintenanceModeDescription struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// Get returns the title and text that match the given language tag best. The translations are checked in this order:
+//   - the translation for the exact language tag, e.g. "de-AT"
+//   - the translation for the base language, e.g. "de"
+//   - the first translation with the same base language, e.g. "de-DE"
+//   - the translation for the fallback language
+//
+// If none of them exists, the default title and text are returned. Language tags are compared case-insensitively.
+func (mmd MaintenanceModeDescription) Get(lang string) LocalizedMaintenanceModeDescription {
+	if localized, ok := mmd.findTranslation(lang); ok {
+		return localized
+	}
+
+	if localized, ok := mmd.findTranslation(mmd.FallbackLanguage); ok {
+		return localized
+	}
+
+	return LocalizedMaintenanceModeDescription{Title: mmd.Title, Text: mmd.Text}
+}
+
+func (mmd MaintenanceModeDescription) findTranslation(lang string) (LocalizedMaintenanceModeDescription, bool) {
+	lang = normalizeLanguageTag(lang)
+	if lang == "" || len(mmd.Translations) == 0 {
+		return LocalizedMaintenanceModeDescription{}, false
+	}
+
+	tags := make([]string, 0, len(mmd.Translations))
+	for tag := range mmd.Translations {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	baseLang := baseLanguage(lang)
+	candidates := []func(tag string) bool{
+		func(tag string) bool { return tag == lang },
+		func(tag string) bool { return tag == baseLang },
+		func(tag string) bool { return baseLanguage(tag) == baseLang },
+	}
+	for _, matches := range candidates {
+		for _, tag := range tags {
+			if matches(normalizeLanguageTag(tag)) {
+				return mmd.Translations[tag], true
+			}
+		}
+	}
+
+	return LocalizedMaintenanceModeDescription{}, false
+}
+
+// withDefault fills the default title and text from the translations if both are empty, so that readers without
+// support for localization still display a description.
+func (mmd MaintenanceModeDescription) withDefault() MaintenanceModeDescription {
+	if mmd.Title != "" || mmd.Text != "" || len(mmd.Translations) == 0 {
+		return mmd
+	}
+
+	localized, ok := mmd.findTranslation(mmd.FallbackLanguage)
+	if !ok {
+		tags := make([]string, 0, len(mmd.Translations))
+		for tag := range mmd.Translations {
+			tags = append(tags, tag)
+		}
+		localized = mmd.Translations[slices.Min(tags)]
+	}
+
+	mmd.Title, mmd.Text = localized.Title, localized.Text
+
+	return mmd
+}
+
+func normalizeLanguageTag(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return base
 }
 
 type MaintenanceModeAdapter struct {
 	owner            string
 	globalConfigRepo *GlobalConfigRepository
+	// now returns the current time to check the expiry of leases. time.Now is used if it is not set.
+	now func() time.Time
 }
 
 // NewMaintenanceModeAdapter creates a new adapter to handel the maintenance mode
@@ -26,43 +129,214 @@ func NewMaintenanceModeAdapter(owner string, client ConfigMapClient) *Maintenanc
 	return &MaintenanceModeAdapter{
 		owner:            owner,
 		globalConfigRepo: NewGlobalConfigRepository(client),
+		now:              time.Now,
+	}
+}
+
+// NewCachedMaintenanceModeAdapter creates a new adapter to handle the maintenance mode, which reads the global config
+// from the given config map cache.
+func NewCachedMaintenanceModeAdapter(owner string, client ConfigMapClient, configCache *ConfigCache) *MaintenanceModeAdapter {
+	return &MaintenanceModeAdapter{
+		owner:            owner,
+		globalConfigRepo: NewCachedGlobalConfigRepository(client, configCache).GlobalConfigRepository,
+		now:              time.Now,
 	}
 }
 
 type maintenanceConfig struct {
-	Title  string `json:"title"`
-	Text   string `json:"text"`
+	MaintenanceModeDescription
 	Holder string `json:"holder,omitempty"`
+	// ExpiresAt and LeaseDuration are only set if the maintenance mode was activated with a TTL.
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	LeaseDuration string     `json:"leaseDuration,omitempty"`
+	// Audit is only set if the maintenance mode was taken over with TakeOver.
+	Audit *MaintenanceModeAudit `json:"audit,omitempty"`
+	// Shared and Holders are only set if the maintenance mode was activated with ActivateShared. Holder is empty then
+	// and the description is taken from the first holder.
+	Shared  bool                      `json:"shared,omitempty"`
+	Holders []sharedMaintenanceHolder `json:"holders,omitempty"`
+}
+
+type sharedMaintenanceHolder struct {
+	MaintenanceModeDescription
+	Holder string `json:"holder"`
+}
+
+// addHolder registers the holder with its description or replaces the description of an already registered holder.
+func (mc *maintenanceConfig) addHolder(holder string, description MaintenanceModeDescription) {
+	sharedHolder := sharedMaintenanceHolder{MaintenanceModeDescription: description.withDefault(), Holder: holder}
+
+	replaced := false
+	for i := range mc.Holders {
+		if mc.Holders[i].Holder == holder {
+			mc.Holders[i] = sharedHolder
+			replaced = true
+		}
+	}
+	if !replaced {
+		mc.Holders = append(mc.Holders, sharedHolder)
+	}
+
+	mc.MaintenanceModeDescription = mc.Holders[0].MaintenanceModeDescription
+}
+
+// removeHolder removes the holder and returns false if it was not registered.
+func (mc *maintenanceConfig) removeHolder(holder string) bool {
+	for i := range mc.Holders {
+		if mc.Holders[i].Holder == holder {
+			mc.Holders = append(mc.Holders[:i], mc.Holders[i+1:]...)
+			if len(mc.Holders) > 0 {
+				mc.MaintenanceModeDescription = mc.Holders[0].MaintenanceModeDescription
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// holderNames returns the names of all holders of the maintenance mode, separated by commas.
+func (mc *maintenanceConfig) holderNames() string {
+	if !mc.Shared {
+		return mc.Holder
+	}
+
+	names := make([]string, 0, len(mc.Holders))
+	for _, holder := range mc.Holders {
+		names = append(names, holder.Holder)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// MaintenanceModeAction is an administrative action on the maintenance mode that is recorded in a MaintenanceModeAudit.
+type MaintenanceModeAction string
+
+const (
+	// MaintenanceModeTakeOver is recorded if a component took over the maintenance mode with TakeOver.
+	MaintenanceModeTakeOver MaintenanceModeAction = "TakeOver"
+	// MaintenanceModeForceDeactivate is recorded if a component deactivated the maintenance mode with ForceDeactivate.
+	MaintenanceModeForceDeactivate MaintenanceModeAction = "ForceDeactivate"
+)
+
+// MaintenanceModeAudit records who forced a change of the maintenance mode, when and why.
+// NewHolder is empty for forced deactivations.
+type MaintenanceModeAudit struct {
+	Action         MaintenanceModeAction `json:"action"`
+	Reason         string                `json:"reason"`
+	PreviousHolder string                `json:"previousHolder"`
+	NewHolder      string                `json:"newHolder,omitempty"`
+	PerformedBy    string                `json:"performedBy"`
+	Timestamp      time.Time             `json:"timestamp"`
 }
 
 func newMaintenanceConfig(owner string, description MaintenanceModeDescription) *maintenanceConfig {
 	return &maintenanceConfig{
-		Title:  description.Title,
-		Text:   description.Text,
-		Holder: owner,
+		MaintenanceModeDescription: description.withDefault(),
+		Holder:                     owner,
 	}
 }
 
+func (mc *maintenanceConfig) setLease(now time.Time, ttl time.Duration) {
+	expiresAt := now.Add(ttl).UTC()
+	mc.ExpiresAt = &expiresAt
+	mc.LeaseDuration = ttl.String()
+}
+
+func (mc *maintenanceConfig) isExpired(now time.Time) bool {
+	return mc.ExpiresAt != nil && !now.Before(*mc.ExpiresAt)
+}
+
+func parseMaintenanceConfig(rawValue config.Value) (*maintenanceConfig, error) {
+	var value maintenanceConfig
+	err := json.Unmarshal([]byte(rawValue), &value)
+	if err != nil {
+		return nil, errors.NewGenericError(fmt.Errorf("failed to parse json of maintenance mode config: %w", err))
+	}
+
+	return &value, nil
+}
+
+// Owner returns the name of the component the adapter activates and deactivates the maintenance mode for.
+func (mma *MaintenanceModeAdapter) Owner() string {
+	return mma.owner
+}
+
+func (mma *MaintenanceModeAdapter) currentTime() time.Time {
+	if mma.now == nil {
+		return time.Now()
+	}
+
+	return mma.now()
+}
+
 // Activate enables the maintenance mode and blocks the execution until the maintenance mode is activated.
 // You can set timeouts via the go context.
+// An expired lease of another component is taken over.
+// If the component already holds the maintenance mode, its description is replaced and its lease is removed.
 // ConflictError if another component already activated the maintenance mode
 // ConnectionError at any connection issues
 // Generic Error at any other issue
 func (mma *MaintenanceModeAdapter) Activate(ctx context.Context, content MaintenanceModeDescription) error {
+	return mma.activate(ctx, content, 0)
+}
+
+// ActivateWithTTL works like Activate but the activation expires after the given TTL unless it is renewed with Renew.
+// Expired activations can be taken over by other components or cleared with Deactivate or ClearExpired.
+// If the component already holds the maintenance mode, its description and lease are replaced.
+// ConflictError if another component already activated the maintenance mode
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) ActivateWithTTL(ctx context.Context, content MaintenanceModeDescription, ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.NewGenericError(fmt.Errorf("ttl of maintenance mode must be positive, got %s", ttl))
+	}
+
+	return mma.activate(ctx, content, ttl)
+}
+
+func (mma *MaintenanceModeAdapter) activate(ctx context.Context, content MaintenanceModeDescription, ttl time.Duration) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryActivate(ctx, content, ttl)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryActivate(ctx context.Context, content MaintenanceModeDescription, ttl time.Duration) error {
 	globalConfig, err := mma.globalConfigRepo.Get(ctx)
 	if err != nil {
 		return fmt.Errorf("could not get contents of global config-map for activating maintenance mode: %w", handleError(err))
 	}
 
-	if rawValue, isActive := globalConfig.Get(registryKeyMaintenance); !isActive {
-		return mma.setMaintenanceModeInConfig(ctx, globalConfig, content)
-	} else {
-		return mma.checkForConflict(rawValue)
+	maintenanceConf := newMaintenanceConfig(mma.owner, content)
+	if ttl > 0 {
+		maintenanceConf.setLease(mma.currentTime(), ttl)
+	}
+
+	if rawValue, isActive := globalConfig.Get(registryKeyMaintenance); isActive {
+		value, err := mma.checkForConflict(rawValue)
+		if err != nil {
+			return err
+		}
+
+		// a lease is always renewed, while an activation without lease is only written if it changes the stored one
+		if ttl == 0 && isSameActivation(value, maintenanceConf) {
+			return nil
+		}
 	}
+
+	return mma.setMaintenanceModeInConfig(ctx, globalConfig, maintenanceConf)
 }
 
-func (mma *MaintenanceModeAdapter) setMaintenanceModeInConfig(ctx context.Context, globalConfig config.GlobalConfig, content MaintenanceModeDescription) error {
-	maintenanceConf := newMaintenanceConfig(mma.owner, content)
+// isSameActivation returns true if the stored maintenance mode is held by the same holder without lease and with the
+// same description as the requested one.
+func isSameActivation(stored *maintenanceConfig, requested *maintenanceConfig) bool {
+	return stored.Holder == requested.Holder &&
+		stored.ExpiresAt == nil && requested.ExpiresAt == nil &&
+		reflect.DeepEqual(stored.MaintenanceModeDescription, requested.MaintenanceModeDescription)
+}
+
+func (mma *MaintenanceModeAdapter) setMaintenanceModeInConfig(ctx context.Context, globalConfig config.GlobalConfig, maintenanceConf *maintenanceConfig) error {
 	jsonBytes, err := json.Marshal(maintenanceConf)
 	if err != nil {
 		return errors.NewGenericError(fmt.Errorf("failed to serialize maintenance mode config: %w", err))
@@ -74,46 +348,573 @@ func (mma *MaintenanceModeAdapter) setMaintenanceModeInConfig(ctx context.Contex
 	}
 
 	_, err = mma.globalConfigRepo.Update(ctx, config.GlobalConfig{Config: updatedConfig})
+	if errors.IsConflictError(err) {
+		return &globalConfigUpdateConflict{cause: err}
+	}
 	if err != nil {
 		return fmt.Errorf("could not update global config-map for activating maintenance mode: %w", handleError(err))
 	}
 	return nil
 }
 
-func (mma *MaintenanceModeAdapter) checkForConflict(rawValue config.Value) error {
-	var value maintenanceConfig
-	err := json.Unmarshal([]byte(rawValue), &value)
+// globalConfigUpdateConflict marks a failed update of the global config caused by a concurrent modification.
+// Such an update is retried with the current global config, because the modification may have concerned other keys.
+type globalConfigUpdateConflict struct {
+	cause error
+}
+
+func (gcuc *globalConfigUpdateConflict) Error() string {
+	return gcuc.cause.Error()
+}
+
+// newUpdateConflictBackoff returns the delays between the attempts to update the global config after concurrent
+// modifications. It allows enough attempts for many components that write the global config at the same time.
+func newUpdateConflictBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: 10 * time.Millisecond,
+		Factor:   1.5,
+		Jitter:   1,
+		Steps:    15,
+		Cap:      500 * time.Millisecond,
+	}
+}
+
+// retryOnUpdateConflict reads and updates the global config with the given function again as long as the update
+// fails because of a concurrent modification, until the attempts of newUpdateConflictBackoff are used up or the
+// context is done. A ConflictError of the function, e.g. because another component got hold of the maintenance mode
+// in the meantime, is not retried.
+// Retries read from the API server, because a cache may not contain the concurrent modification yet.
+func retryOnUpdateConflict(ctx context.Context, fn func(ctx context.Context) error) error {
+	attemptCtx := ctx
+	var conflict *globalConfigUpdateConflict
+	err := wait.ExponentialBackoffWithContext(ctx, newUpdateConflictBackoff(), func(context.Context) (bool, error) {
+		err := fn(attemptCtx)
+		attemptCtx = withAPIServerReads(ctx)
+
+		conflict = nil
+		if goerrors.As(err, &conflict) {
+			return false, nil
+		}
+
+		return true, err
+	})
+
+	if conflict != nil {
+		return errors.NewGenericError(fmt.Errorf("could not update global config-map because of repeated concurrent modifications: %w", conflict.cause))
+	}
+
+	return err
+}
+
+// checkForConflict returns a conflict error if the maintenance mode is held by another owner and not expired.
+func (mma *MaintenanceModeAdapter) checkForConflict(rawValue config.Value) (*maintenanceConfig, error) {
+	value, err := parseMaintenanceConfig(rawValue)
 	if err != nil {
-		return errors.NewGenericError(fmt.Errorf("failed to parse json of maintenance mode config: %w", err))
+		return nil, err
 	}
-	if value.Holder != mma.owner {
-		return errors.NewConflictError(fmt.Errorf("maintenance mode %s is already activated by another owner: %s", rawValue, value.Holder))
+	if value.Shared {
+		return nil, errors.NewConflictError(fmt.Errorf("maintenance mode %s is shared by the holders: %s", rawValue, value.holderNames()))
 	}
-	return nil
+	if value.Holder != mma.owner && !value.isExpired(mma.currentTime()) {
+		return nil, errors.NewConflictError(fmt.Errorf("maintenance mode %s is already activated by another owner: %s", rawValue, value.Holder))
+	}
+	return value, nil
+}
+
+// ActivateShared registers this component as one of possibly several holders of a shared maintenance mode. Each
+// holder has its own description. The description of the first holder is displayed. If the component is already
+// registered, its description is replaced. The maintenance mode stays active until the last holder has called
+// DeactivateShared.
+// ConflictError if another component activated the maintenance mode exclusively
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) ActivateShared(ctx context.Context, content MaintenanceModeDescription) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryActivateShared(ctx, content)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryActivateShared(ctx context.Context, content MaintenanceModeDescription) error {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get contents of global config-map for activating shared maintenance mode: %w", handleError(err))
+	}
+
+	maintenanceConf := &maintenanceConfig{Shared: true}
+	if rawValue, isActive := globalConfig.Get(registryKeyMaintenance); isActive {
+		value, err := parseMaintenanceConfig(rawValue)
+		if err != nil {
+			return err
+		}
+
+		if value.Shared {
+			maintenanceConf = value
+		} else if !value.isExpired(mma.currentTime()) {
+			return errors.NewConflictError(fmt.Errorf("maintenance mode %s is exclusively activated by owner: %s", rawValue, value.Holder))
+		}
+	}
+
+	maintenanceConf.addHolder(mma.owner, content)
+
+	return mma.setMaintenanceModeInConfig(ctx, globalConfig, maintenanceConf)
+}
+
+// DeactivateShared unregisters this component from the shared maintenance mode. The maintenance mode is deactivated
+// when the last holder is unregistered. Nothing happens if the component is not registered.
+// ConflictError if the maintenance mode was activated exclusively
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) DeactivateShared(ctx context.Context) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryDeactivateShared(ctx)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryDeactivateShared(ctx context.Context) error {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get contents of global config-map for deactivating shared maintenance mode: %w", handleError(err))
+	}
+
+	rawValue, isActive := globalConfig.Get(registryKeyMaintenance)
+	if !isActive {
+		return nil
+	}
+
+	value, err := parseMaintenanceConfig(rawValue)
+	if err != nil {
+		return err
+	}
+	if !value.Shared {
+		return errors.NewConflictError(fmt.Errorf("maintenance mode %s is exclusively activated by owner: %s", rawValue, value.Holder))
+	}
+
+	if !value.removeHolder(mma.owner) {
+		return nil
+	}
+	if len(value.Holders) == 0 {
+		return mma.deleteMaintenanceModeFromConfig(ctx, globalConfig)
+	}
+
+	return mma.setMaintenanceModeInConfig(ctx, globalConfig, value)
 }
 
 // Deactivate disables the maintenance mode if it is active.
+// An expired activation of another component is cleared as well.
 // ConflictError if another component activated the maintenance mode
 // ConnectionError at any connection issues
 // Generic Error at any other issue
 func (mma *MaintenanceModeAdapter) Deactivate(ctx context.Context) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryDeactivate(ctx)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryDeactivate(ctx context.Context) error {
 	globalConfig, err := mma.globalConfigRepo.Get(ctx)
 	if err != nil {
 		return fmt.Errorf("could not get contents of global config-map for deactivating maintenance mode: %w", handleError(err))
 	}
 
 	if rawValue, isActive := globalConfig.Get(registryKeyMaintenance); isActive {
-		err = mma.checkForConflict(rawValue)
+		_, err = mma.checkForConflict(rawValue)
 		if err != nil {
 			return err
 		}
 
-		updatedConfig := globalConfig.Delete(registryKeyMaintenance)
-		_, err = mma.globalConfigRepo.Update(ctx, config.GlobalConfig{Config: updatedConfig})
-		if err != nil {
-			return fmt.Errorf("could not update global config-map for activating maintenance mode: %w", handleError(err))
-		}
+		return mma.deleteMaintenanceModeFromConfig(ctx, globalConfig)
+	}
+
+	return nil
+}
+
+func (mma *MaintenanceModeAdapter) deleteMaintenanceModeFromConfig(ctx context.Context, globalConfig config.GlobalConfig) error {
+	updatedConfig := globalConfig.Delete(registryKeyMaintenance)
+	_, err := mma.globalConfigRepo.Update(ctx, config.GlobalConfig{Config: updatedConfig})
+	if errors.IsConflictError(err) {
+		return &globalConfigUpdateConflict{cause: err}
+	}
+	if err != nil {
+		return fmt.Errorf("could not update global config-map for activating maintenance mode: %w", handleError(err))
 	}
 
 	return nil
 }
+
+// Renew extends the lease of a maintenance mode activated with ActivateWithTTL by its TTL. Activations without TTL
+// are left unchanged. An expired lease can be renewed as long as it has not been taken over by another component.
+// NotFoundError if the maintenance mode is not active
+// ConflictError if another component holds the maintenance mode
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) Renew(ctx context.Context) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryRenew(ctx)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryRenew(ctx context.Context) error {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get contents of global config-map for renewing maintenance mode: %w", handleError(err))
+	}
+
+	rawValue, isActive := globalConfig.Get(registryKeyMaintenance)
+	if !isActive {
+		return errors.NewNotFoundError(fmt.Errorf("could not renew maintenance mode, because it is not active"))
+	}
+
+	value, err := parseMaintenanceConfig(rawValue)
+	if err != nil {
+		return err
+	}
+	if value.Shared {
+		return nil
+	}
+	if value.Holder != mma.owner {
+		return errors.NewConflictError(fmt.Errorf("could not renew maintenance mode held by another owner: %s", value.Holder))
+	}
+	if value.LeaseDuration == "" {
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(value.LeaseDuration)
+	if err != nil {
+		return errors.NewGenericError(fmt.Errorf("failed to parse lease duration of maintenance mode: %w", err))
+	}
+
+	value.setLease(mma.currentTime(), ttl)
+
+	return mma.setMaintenanceModeInConfig(ctx, globalConfig, value)
+}
+
+// ClearExpired deactivates the maintenance mode if its lease has expired, regardless of its holder.
+// It returns true if an expired maintenance mode was cleared.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) ClearExpired(ctx context.Context) (bool, error) {
+	var cleared bool
+	err := retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		var err error
+		cleared, err = mma.tryClearExpired(ctx)
+		return err
+	})
+
+	return cleared, err
+}
+
+func (mma *MaintenanceModeAdapter) tryClearExpired(ctx context.Context) (bool, error) {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not get contents of global config-map for clearing maintenance mode: %w", handleError(err))
+	}
+
+	rawValue, isActive := globalConfig.Get(registryKeyMaintenance)
+	if !isActive {
+		return false, nil
+	}
+
+	value, err := parseMaintenanceConfig(rawValue)
+	if err != nil {
+		return false, err
+	}
+	if !value.isExpired(mma.currentTime()) {
+		return false, nil
+	}
+
+	err = mma.deleteMaintenanceModeFromConfig(ctx, globalConfig)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// TakeOver activates the maintenance mode for this component, even if another component holds it. The reason, the
+// previous holder, the new holder and the time are recorded in the maintenance mode.
+// This is meant for administrative tooling to recover from stuck holders.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) TakeOver(ctx context.Context, content MaintenanceModeDescription, reason string) error {
+	if reason == "" {
+		return errors.NewGenericError(fmt.Errorf("a reason is required to take over the maintenance mode"))
+	}
+
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryTakeOver(ctx, content, reason)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryTakeOver(ctx context.Context, content MaintenanceModeDescription, reason string) error {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get contents of global config-map for taking over maintenance mode: %w", handleError(err))
+	}
+
+	previousHolder := getMaintenanceModeHolder(globalConfig)
+
+	maintenanceConf := newMaintenanceConfig(mma.owner, content)
+	maintenanceConf.Audit = &MaintenanceModeAudit{
+		Action:         MaintenanceModeTakeOver,
+		Reason:         reason,
+		PreviousHolder: previousHolder,
+		NewHolder:      mma.owner,
+		PerformedBy:    mma.owner,
+		Timestamp:      mma.currentTime().UTC(),
+	}
+
+	return mma.setMaintenanceModeInConfig(ctx, globalConfig, maintenanceConf)
+}
+
+// ForceDeactivate disables the maintenance mode, even if another component holds it. The reason, the previous holder
+// and the time are recorded in the global config and can be read with LastForceDeactivation.
+// This is meant for administrative tooling to recover from stuck holders.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) ForceDeactivate(ctx context.Context, reason string) error {
+	if reason == "" {
+		return errors.NewGenericError(fmt.Errorf("a reason is required to force the deactivation of the maintenance mode"))
+	}
+
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		return mma.tryForceDeactivate(ctx, reason)
+	})
+}
+
+func (mma *MaintenanceModeAdapter) tryForceDeactivate(ctx context.Context, reason string) error {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get contents of global config-map for force-deactivating maintenance mode: %w", handleError(err))
+	}
+
+	if _, isActive := globalConfig.Get(registryKeyMaintenance); !isActive {
+		return nil
+	}
+
+	previousHolder := getMaintenanceModeHolder(globalConfig)
+
+	audit := MaintenanceModeAudit{
+		Action:         MaintenanceModeForceDeactivate,
+		Reason:         reason,
+		PreviousHolder: previousHolder,
+		PerformedBy:    mma.owner,
+		Timestamp:      mma.currentTime().UTC(),
+	}
+
+	jsonBytes, err := json.Marshal(audit)
+	if err != nil {
+		return errors.NewGenericError(fmt.Errorf("failed to serialize maintenance mode audit: %w", err))
+	}
+
+	updatedConfig, err := globalConfig.Set(registryKeyMaintenanceAudit, config.Value(jsonBytes))
+	if err != nil {
+		return errors.NewGenericError(fmt.Errorf("failed to set maintenance mode audit registry key: %w", err))
+	}
+
+	return mma.deleteMaintenanceModeFromConfig(ctx, config.GlobalConfig{Config: updatedConfig})
+}
+
+// LastForceDeactivation returns the audit of the last forced deactivation of the maintenance mode. It returns false if
+// the maintenance mode has never been force-deactivated.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) LastForceDeactivation(ctx context.Context) (MaintenanceModeAudit, bool, error) {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return MaintenanceModeAudit{}, false, fmt.Errorf("could not get contents of global config-map for reading maintenance mode audit: %w", handleError(err))
+	}
+
+	rawValue, ok := globalConfig.Get(registryKeyMaintenanceAudit)
+	if !ok {
+		return MaintenanceModeAudit{}, false, nil
+	}
+
+	var audit MaintenanceModeAudit
+	err = json.Unmarshal([]byte(rawValue), &audit)
+	if err != nil {
+		return MaintenanceModeAudit{}, false, errors.NewGenericError(fmt.Errorf("failed to parse json of maintenance mode audit: %w", err))
+	}
+
+	return audit, true, nil
+}
+
+// getMaintenanceModeHolder returns the holder of the maintenance mode. An unreadable maintenance mode has no holder,
+// so that it can still be recovered by administrative actions.
+func getMaintenanceModeHolder(globalConfig config.GlobalConfig) string {
+	rawValue, isActive := globalConfig.Get(registryKeyMaintenance)
+	if !isActive {
+		return ""
+	}
+
+	value, err := parseMaintenanceConfig(rawValue)
+	if err != nil {
+		return ""
+	}
+
+	return value.holderNames()
+}
+
+// MaintenanceModeStatus describes the current state of the maintenance mode.
+// Holder, Description and Holders are only set if the maintenance mode is active. Holder and Description belong to
+// the first of all Holders, whose description is displayed.
+// ExpiresAt is only set if the maintenance mode was activated with a TTL, Audit only if it was taken over.
+type MaintenanceModeStatus struct {
+	Active      bool
+	Shared      bool
+	Holder      string
+	Description MaintenanceModeDescription
+	Holders     []MaintenanceModeHolder
+	ExpiresAt   time.Time
+	Audit       MaintenanceModeAudit
+}
+
+// MaintenanceModeHolder is a component holding the maintenance mode with its own description.
+type MaintenanceModeHolder struct {
+	Holder      string
+	Description MaintenanceModeDescription
+}
+
+// IsExpired returns true if the maintenance mode is active and its lease has expired at the given time.
+// Expired activations are still active until they are taken over or cleared.
+func (mms MaintenanceModeStatus) IsExpired(now time.Time) bool {
+	return mms.Active && !mms.ExpiresAt.IsZero() && !now.Before(mms.ExpiresAt)
+}
+
+// IsActive returns true if the maintenance mode is active.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) IsActive(ctx context.Context) (bool, error) {
+	status, err := mma.Get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Active, nil
+}
+
+// Get returns the current status of the maintenance mode including the holder and the displayed description.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) Get(ctx context.Context) (MaintenanceModeStatus, error) {
+	globalConfig, err := mma.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return MaintenanceModeStatus{}, fmt.Errorf("could not get contents of global config-map for reading maintenance mode: %w", handleError(err))
+	}
+
+	return parseMaintenanceModeStatus(globalConfig)
+}
+
+func parseMaintenanceModeStatus(globalConfig config.GlobalConfig) (MaintenanceModeStatus, error) {
+	rawValue, isActive := globalConfig.Get(registryKeyMaintenance)
+	if !isActive {
+		return MaintenanceModeStatus{}, nil
+	}
+
+	value, err := parseMaintenanceConfig(rawValue)
+	if err != nil {
+		return MaintenanceModeStatus{}, err
+	}
+
+	status := MaintenanceModeStatus{
+		Active:      true,
+		Shared:      value.Shared,
+		Holder:      value.Holder,
+		Description: value.MaintenanceModeDescription,
+	}
+	if value.Shared {
+		for _, holder := range value.Holders {
+			status.Holders = append(status.Holders, MaintenanceModeHolder{
+				Holder:      holder.Holder,
+				Description: holder.MaintenanceModeDescription,
+			})
+		}
+		if len(status.Holders) > 0 {
+			status.Holder = status.Holders[0].Holder
+		}
+	} else {
+		status.Holders = []MaintenanceModeHolder{{Holder: status.Holder, Description: status.Description}}
+	}
+	if value.ExpiresAt != nil {
+		status.ExpiresAt = *value.ExpiresAt
+	}
+	if value.Audit != nil {
+		status.Audit = *value.Audit
+	}
+
+	return status, nil
+}
+
+// MaintenanceModeEventType describes how the maintenance mode has changed.
+type MaintenanceModeEventType string
+
+const (
+	// MaintenanceModeActivated is the type of events for an activated maintenance mode.
+	MaintenanceModeActivated MaintenanceModeEventType = "Activated"
+	// MaintenanceModeDeactivated is the type of events for a deactivated maintenance mode.
+	MaintenanceModeDeactivated MaintenanceModeEventType = "Deactivated"
+	// MaintenanceModeChanged is the type of events for an active maintenance mode whose holder, description or lease has
+	// changed.
+	MaintenanceModeChanged MaintenanceModeEventType = "Changed"
+)
+
+// MaintenanceModeEvent is the result of a maintenance mode watch. If Err is set, all other fields are empty.
+type MaintenanceModeEvent struct {
+	Type       MaintenanceModeEventType
+	PrevStatus MaintenanceModeStatus
+	NewStatus  MaintenanceModeStatus
+	Err        error
+}
+
+// Watch notifies about every activation, deactivation and change of the maintenance mode until the context is done.
+// The deletion of the global config is reported as deactivation.
+// ConnectionError at any connection issues while starting the watch
+// Generic Error at any other issue
+func (mma *MaintenanceModeAdapter) Watch(ctx context.Context) (<-chan MaintenanceModeEvent, error) {
+	cfgWatch, err := mma.globalConfigRepo.Watch(ctx, config.KeyFilter(registryKeyMaintenance))
+	if err != nil {
+		return nil, fmt.Errorf("could not start watch of maintenance mode: %w", handleError(err))
+	}
+
+	eventChan := make(chan MaintenanceModeEvent)
+
+	go func() {
+		defer close(eventChan)
+		for result := range cfgWatch {
+			event := createMaintenanceModeEvent(result)
+			// a changed value can still result in the same status, e.g. if only the formatting of the json has changed
+			if event.Err == nil && reflect.DeepEqual(event.PrevStatus, event.NewStatus) {
+				continue
+			}
+
+			eventChan <- event
+		}
+	}()
+
+	return eventChan, nil
+}
+
+func createMaintenanceModeEvent(result GlobalConfigWatchResult) MaintenanceModeEvent {
+	if result.Err != nil {
+		return MaintenanceModeEvent{Err: fmt.Errorf("error while watching maintenance mode: %w", result.Err)}
+	}
+
+	prevStatus, err := parseMaintenanceModeStatus(result.PrevState)
+	if err != nil {
+		return MaintenanceModeEvent{Err: fmt.Errorf("could not read previous maintenance mode: %w", err)}
+	}
+
+	newStatus, err := parseMaintenanceModeStatus(result.NewState)
+	if err != nil {
+		return MaintenanceModeEvent{Err: fmt.Errorf("could not read new maintenance mode: %w", err)}
+	}
+
+	eventType := MaintenanceModeChanged
+	switch {
+	case !prevStatus.Active && newStatus.Active:
+		eventType = MaintenanceModeActivated
+	case prevStatus.Active && !newStatus.Active:
+		eventType = MaintenanceModeDeactivated
+	}
+
+	return MaintenanceModeEvent{Type: eventType, PrevStatus: prevStatus, NewStatus: newStatus}
+}
@@ -2,12 +2,24 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/cloudogu/k8s-registry-lib/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/retry"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 var testCtx = context.Background()
@@ -151,7 +163,7 @@ func Test_defaultSwitcher_Activate(t *testing.T) {
 		assert.True(t, errors.IsGenericError(err))
 	})
 
-	t.Run("should return nil and do nothing if a component already holds the maintenance mode", func(t *testing.T) {
+	t.Run("should return nil and do nothing if a component already holds the maintenance mode with the same description", func(t *testing.T) {
 		// given
 		mConfigRepo := newMockGeneralConfigRepository(t)
 		globalConfig := config.CreateConfig(config.Entries{"maintenance": "{\"title\": \"title\", \"text\": \"text\", \"holder\": \"k8s-blueprint-operator\"}"})
@@ -168,8 +180,8 @@ func Test_defaultSwitcher_Activate(t *testing.T) {
 
 		// when
 		err := sut.Activate(testCtx, MaintenanceModeDescription{
-			Title: "myTitle",
-			Text:  "myText",
+			Title: "title",
+			Text:  "text",
 		})
 
 		// then
@@ -304,5 +316,1007 @@ func TestNewMaintenanceModeAdapter(t *testing.T) {
 
 		// then
 		assert.Equal(t, owner, adapter.owner)
+		assert.Equal(t, owner, adapter.Owner())
+	})
+}
+
+func TestMaintenanceModeAdapter_Get(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    config.Entries
+		getErr     error
+		wantStatus MaintenanceModeStatus
+		wantErr    string
+	}{
+		{
+			name:    "should return active maintenance mode",
+			entries: config.Entries{"maintenance": `{"title":"myTitle","text":"myText","holder":"k8s-backup-operator"}`},
+			wantStatus: MaintenanceModeStatus{
+				Active:      true,
+				Holder:      "k8s-backup-operator",
+				Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"},
+				Holders: []MaintenanceModeHolder{
+					{Holder: "k8s-backup-operator", Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"}},
+				},
+			},
+		},
+		{
+			name:       "should return inactive maintenance mode",
+			entries:    config.Entries{"fqdn": "example.com"},
+			wantStatus: MaintenanceModeStatus{},
+		},
+		{
+			name:    "should fail to parse maintenance mode",
+			entries: config.Entries{"maintenance": "{"},
+			wantErr: "failed to parse json of maintenance mode config",
+		},
+		{
+			name:    "should fail to get global config",
+			getErr:  assert.AnError,
+			wantErr: "could not get contents of global config-map for reading maintenance mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mConfigRepo := newMockGeneralConfigRepository(t)
+			mConfigRepo.EXPECT().get(mock.Anything, createConfigName(_SimpleGlobalConfigName)).Return(config.CreateConfig(tt.entries), tt.getErr)
+
+			sut := &MaintenanceModeAdapter{
+				owner:            "k8s-blueprint-operator",
+				globalConfigRepo: &GlobalConfigRepository{generalConfigRepository: mConfigRepo},
+			}
+
+			// when
+			status, err := sut.Get(testCtx)
+			isActive, activeErr := sut.IsActive(testCtx)
+
+			// then
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.True(t, errors.IsGenericError(err))
+				assert.Error(t, activeErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, activeErr)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantStatus.Active, isActive)
+		})
+	}
+}
+
+func TestMaintenanceModeAdapter_Watch(t *testing.T) {
+	activeValue := config.Value(`{"title":"myTitle","text":"myText","holder":"k8s-backup-operator"}`)
+	activeStatus := MaintenanceModeStatus{
+		Active:      true,
+		Holder:      "k8s-backup-operator",
+		Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"},
+		Holders: []MaintenanceModeHolder{
+			{Holder: "k8s-backup-operator", Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"}},
+		},
+	}
+
+	t.Run("should emit typed events", func(t *testing.T) {
+		// given
+		mockResultChan := make(chan configWatchResult)
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().watch(testCtx, createConfigName(_SimpleGlobalConfigName), mock.AnythingOfType("config.WatchFilter")).Return(mockResultChan, nil)
+
+		sut := &MaintenanceModeAdapter{
+			owner:            "k8s-blueprint-operator",
+			globalConfigRepo: &GlobalConfigRepository{generalConfigRepository: mConfigRepo},
+		}
+
+		// when
+		eventChan, err := sut.Watch(testCtx)
+		require.NoError(t, err)
+
+		go func() {
+			inactive := config.CreateConfig(config.Entries{})
+			active := config.CreateConfig(config.Entries{"maintenance": activeValue})
+			reformatted := config.CreateConfig(config.Entries{"maintenance": `{"holder":"k8s-backup-operator","title":"myTitle","text":"myText"}`})
+			changed := config.CreateConfig(config.Entries{"maintenance": `{"title":"other","text":"myText","holder":"k8s-backup-operator"}`})
+
+			mockResultChan <- configWatchResult{prevState: inactive, newState: active}
+			mockResultChan <- configWatchResult{prevState: active, newState: reformatted}
+			mockResultChan <- configWatchResult{prevState: reformatted, newState: changed}
+			mockResultChan <- configWatchResult{eventKind: config.EventDeleted, prevState: changed, newState: inactive}
+			mockResultChan <- configWatchResult{err: assert.AnError}
+			mockResultChan <- configWatchResult{prevState: inactive, newState: config.CreateConfig(config.Entries{"maintenance": "{"})}
+			close(mockResultChan)
+		}()
+
+		var events []MaintenanceModeEvent
+		for event := range eventChan {
+			events = append(events, event)
+		}
+
+		// then
+		require.Len(t, events, 5)
+		assert.Equal(t, MaintenanceModeEvent{Type: MaintenanceModeActivated, NewStatus: activeStatus}, events[0])
+
+		changedStatus := activeStatus
+		changedStatus.Description.Title = "other"
+		changedStatus.Holders = []MaintenanceModeHolder{
+			{Holder: "k8s-backup-operator", Description: MaintenanceModeDescription{Title: "other", Text: "myText"}},
+		}
+		assert.Equal(t, MaintenanceModeEvent{Type: MaintenanceModeChanged, PrevStatus: activeStatus, NewStatus: changedStatus}, events[1])
+		assert.Equal(t, MaintenanceModeEvent{Type: MaintenanceModeDeactivated, PrevStatus: changedStatus}, events[2])
+
+		assert.ErrorIs(t, events[3].Err, assert.AnError)
+		assert.ErrorContains(t, events[3].Err, "error while watching maintenance mode")
+
+		assert.ErrorContains(t, events[4].Err, "could not read new maintenance mode")
+	})
+
+	t.Run("should fail to start watch", func(t *testing.T) {
+		// given
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().watch(testCtx, createConfigName(_SimpleGlobalConfigName), mock.AnythingOfType("config.WatchFilter")).Return(nil, assert.AnError)
+
+		sut := &MaintenanceModeAdapter{
+			owner:            "k8s-blueprint-operator",
+			globalConfigRepo: &GlobalConfigRepository{generalConfigRepository: mConfigRepo},
+		}
+
+		// when
+		_, err := sut.Watch(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "could not start watch of maintenance mode")
+	})
+}
+
+func TestMaintenanceModeAdapter_Lease(t *testing.T) {
+	now := time.Date(2024, 10, 17, 12, 0, 0, 0, time.UTC)
+	expiredLease := config.Value(`{"title":"title","text":"text","holder":"k8s-backup-operator","expiresAt":"2024-10-17T11:59:00Z","leaseDuration":"5m0s"}`)
+	validLease := config.Value(`{"title":"title","text":"text","holder":"k8s-backup-operator","expiresAt":"2024-10-17T12:01:00Z","leaseDuration":"5m0s"}`)
+	ownLease := config.Value(`{"title":"title","text":"text","holder":"k8s-blueprint-operator","expiresAt":"2024-10-17T11:59:00Z","leaseDuration":"5m0s"}`)
+	ownValidLease := config.Value(`{"title":"title","text":"text","holder":"k8s-blueprint-operator","expiresAt":"2024-10-17T12:01:00Z","leaseDuration":"5m0s"}`)
+
+	newAdapter := func(t *testing.T, entries config.Entries) (*MaintenanceModeAdapter, *mockGeneralConfigRepository) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().get(mock.Anything, createConfigName(_SimpleGlobalConfigName)).Return(config.CreateConfig(entries), nil)
+
+		return &MaintenanceModeAdapter{
+			owner:            "k8s-blueprint-operator",
+			globalConfigRepo: &GlobalConfigRepository{generalConfigRepository: mConfigRepo},
+			now:              func() time.Time { return now },
+		}, mConfigRepo
+	}
+
+	expectUpdate := func(mConfigRepo *mockGeneralConfigRepository) *config.Config {
+		var updated config.Config
+		mConfigRepo.EXPECT().update(testCtx, configName("global-config"), config.SimpleDoguName(""), mock.Anything).
+			RunAndReturn(func(_ context.Context, _ configName, _ config.SimpleDoguName, cfg config.Config) (config.Config, error) {
+				updated = cfg
+				return cfg, nil
+			})
+
+		return &updated
+	}
+
+	t.Run("should activate with ttl", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.ActivateWithTTL(testCtx, MaintenanceModeDescription{Title: "myTitle", Text: "myText"}, 5*time.Minute)
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.Equal(t, `{"title":"myTitle","text":"myText","holder":"k8s-blueprint-operator","expiresAt":"2024-10-17T12:05:00Z","leaseDuration":"5m0s"}`, value.String())
+	})
+
+	t.Run("should fail to activate with non-positive ttl", func(t *testing.T) {
+		sut := &MaintenanceModeAdapter{owner: "k8s-blueprint-operator"}
+
+		err := sut.ActivateWithTTL(testCtx, MaintenanceModeDescription{}, 0)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "ttl of maintenance mode must be positive")
+	})
+
+	t.Run("should take over expired lease", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": expiredLease})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.Activate(testCtx, MaintenanceModeDescription{Title: "myTitle", Text: "myText"})
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.Equal(t, `{"title":"myTitle","text":"myText","holder":"k8s-blueprint-operator"}`, value.String())
+	})
+
+	t.Run("should replace own lease on activation without ttl", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": ownValidLease})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.Activate(testCtx, MaintenanceModeDescription{Title: "title", Text: "text"})
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.Equal(t, `{"title":"title","text":"text","holder":"k8s-blueprint-operator"}`, value.String())
+	})
+
+	t.Run("should replace own description on activation without ttl", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": `{"title":"title","text":"text","holder":"k8s-blueprint-operator"}`})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.Activate(testCtx, MaintenanceModeDescription{Title: "myTitle", Text: "myText"})
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.Equal(t, `{"title":"myTitle","text":"myText","holder":"k8s-blueprint-operator"}`, value.String())
+	})
+
+	t.Run("should not take over valid lease", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance": validLease})
+
+		err := sut.ActivateWithTTL(testCtx, MaintenanceModeDescription{}, time.Minute)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsConflictError(err))
+	})
+
+	t.Run("should clear expired lease of other owner on deactivate", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": expiredLease})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.Deactivate(testCtx)
+
+		// then
+		require.NoError(t, err)
+		_, isActive := updated.Get("maintenance")
+		assert.False(t, isActive)
+	})
+
+	t.Run("should renew own lease", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": ownLease})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.Renew(testCtx)
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.Equal(t, `{"title":"title","text":"text","holder":"k8s-blueprint-operator","expiresAt":"2024-10-17T12:05:00Z","leaseDuration":"5m0s"}`, value.String())
+	})
+
+	t.Run("should not renew activation without lease", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance": `{"title":"title","text":"text","holder":"k8s-blueprint-operator"}`})
+
+		err := sut.Renew(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail to renew lease of other owner", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance": expiredLease})
+
+		err := sut.Renew(testCtx)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsConflictError(err))
+		assert.ErrorContains(t, err, "could not renew maintenance mode held by another owner: k8s-backup-operator")
+	})
+
+	t.Run("should fail to renew inactive maintenance mode", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{})
+
+		err := sut.Renew(testCtx)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsNotFoundError(err))
+	})
+
+	t.Run("should clear expired lease", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": expiredLease})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		cleared, err := sut.ClearExpired(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, cleared)
+		_, isActive := updated.Get("maintenance")
+		assert.False(t, isActive)
+	})
+
+	t.Run("should not clear valid lease", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance": validLease})
+
+		cleared, err := sut.ClearExpired(testCtx)
+
+		require.NoError(t, err)
+		assert.False(t, cleared)
+	})
+
+	t.Run("should not clear inactive maintenance mode", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{})
+
+		cleared, err := sut.ClearExpired(testCtx)
+
+		require.NoError(t, err)
+		assert.False(t, cleared)
+	})
+
+	t.Run("should return expiry in status", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance": expiredLease})
+
+		status, err := sut.Get(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 10, 17, 11, 59, 0, 0, time.UTC), status.ExpiresAt)
+		assert.True(t, status.IsExpired(now))
+		assert.False(t, status.IsExpired(now.Add(-2*time.Minute)))
+		assert.False(t, MaintenanceModeStatus{Active: true}.IsExpired(now))
+	})
+}
+
+func TestMaintenanceModeAdapter_ForcedActions(t *testing.T) {
+	now := time.Date(2024, 10, 17, 12, 0, 0, 0, time.UTC)
+	otherHolder := config.Value(`{"title":"title","text":"text","holder":"k8s-backup-operator"}`)
+
+	newAdapter := func(t *testing.T, entries config.Entries) (*MaintenanceModeAdapter, *mockGeneralConfigRepository) {
+		mConfigRepo := newMockGeneralConfigRepository(t)
+		mConfigRepo.EXPECT().get(mock.Anything, createConfigName(_SimpleGlobalConfigName)).Return(config.CreateConfig(entries), nil)
+
+		return &MaintenanceModeAdapter{
+			owner:            "admin-tool",
+			globalConfigRepo: &GlobalConfigRepository{generalConfigRepository: mConfigRepo},
+			now:              func() time.Time { return now },
+		}, mConfigRepo
+	}
+
+	expectUpdate := func(mConfigRepo *mockGeneralConfigRepository) *config.Config {
+		var updated config.Config
+		mConfigRepo.EXPECT().update(testCtx, configName("global-config"), config.SimpleDoguName(""), mock.Anything).
+			RunAndReturn(func(_ context.Context, _ configName, _ config.SimpleDoguName, cfg config.Config) (config.Config, error) {
+				updated = cfg
+				return cfg, nil
+			})
+
+		return &updated
+	}
+
+	t.Run("should take over maintenance mode of other holder", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": otherHolder})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.TakeOver(testCtx, MaintenanceModeDescription{Title: "myTitle", Text: "myText"}, "backup operator crashed")
+
+		// then
+		require.NoError(t, err)
+		value, _ := updated.Get("maintenance")
+		assert.JSONEq(t, `{"title":"myTitle","text":"myText","holder":"admin-tool","audit":{"action":"TakeOver","reason":"backup operator crashed","previousHolder":"k8s-backup-operator","newHolder":"admin-tool","performedBy":"admin-tool","timestamp":"2024-10-17T12:00:00Z"}}`, value.String())
+
+		status, err := parseMaintenanceModeStatus(config.GlobalConfig{Config: *updated})
+		require.NoError(t, err)
+		assert.Equal(t, MaintenanceModeStatus{
+			Active:      true,
+			Holder:      "admin-tool",
+			Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"},
+			Holders: []MaintenanceModeHolder{
+				{Holder: "admin-tool", Description: MaintenanceModeDescription{Title: "myTitle", Text: "myText"}},
+			},
+			Audit: MaintenanceModeAudit{
+				Action:         MaintenanceModeTakeOver,
+				Reason:         "backup operator crashed",
+				PreviousHolder: "k8s-backup-operator",
+				NewHolder:      "admin-tool",
+				PerformedBy:    "admin-tool",
+				Timestamp:      now,
+			},
+		}, status)
+	})
+
+	t.Run("should take over unreadable maintenance mode", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": "{"})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.TakeOver(testCtx, MaintenanceModeDescription{}, "broken")
+
+		// then
+		require.NoError(t, err)
+		status, err := parseMaintenanceModeStatus(config.GlobalConfig{Config: *updated})
+		require.NoError(t, err)
+		assert.Equal(t, "admin-tool", status.Holder)
+		assert.Empty(t, status.Audit.PreviousHolder)
+	})
+
+	t.Run("should require reason to take over", func(t *testing.T) {
+		sut := &MaintenanceModeAdapter{owner: "admin-tool"}
+
+		err := sut.TakeOver(testCtx, MaintenanceModeDescription{}, "")
+
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "a reason is required to take over the maintenance mode")
+	})
+
+	t.Run("should force deactivation of maintenance mode of other holder", func(t *testing.T) {
+		// given
+		sut, mConfigRepo := newAdapter(t, config.Entries{"maintenance": otherHolder})
+		updated := expectUpdate(mConfigRepo)
+
+		// when
+		err := sut.ForceDeactivate(testCtx, "backup operator crashed")
+
+		// then
+		require.NoError(t, err)
+		_, isActive := updated.Get("maintenance")
+		assert.False(t, isActive)
+		audit, _ := updated.Get("maintenance_audit")
+		assert.JSONEq(t, `{"action":"ForceDeactivate","reason":"backup operator crashed","previousHolder":"k8s-backup-operator","performedBy":"admin-tool","timestamp":"2024-10-17T12:00:00Z"}`, audit.String())
+	})
+
+	t.Run("should not force deactivation of inactive maintenance mode", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{})
+
+		err := sut.ForceDeactivate(testCtx, "cleanup")
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should require reason to force deactivation", func(t *testing.T) {
+		sut := &MaintenanceModeAdapter{owner: "admin-tool"}
+
+		err := sut.ForceDeactivate(testCtx, "")
+
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+	})
+
+	t.Run("should return last forced deactivation", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance_audit": `{"action":"ForceDeactivate","reason":"crashed","previousHolder":"k8s-backup-operator","performedBy":"admin-tool","timestamp":"2024-10-17T12:00:00Z"}`})
+
+		audit, ok, err := sut.LastForceDeactivation(testCtx)
+
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, MaintenanceModeAudit{
+			Action:         MaintenanceModeForceDeactivate,
+			Reason:         "crashed",
+			PreviousHolder: "k8s-backup-operator",
+			PerformedBy:    "admin-tool",
+			Timestamp:      now,
+		}, audit)
+	})
+
+	t.Run("should return no forced deactivation", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{})
+
+		_, ok, err := sut.LastForceDeactivation(testCtx)
+
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should fail to parse last forced deactivation", func(t *testing.T) {
+		sut, _ := newAdapter(t, config.Entries{"maintenance_audit": "{"})
+
+		_, _, err := sut.LastForceDeactivation(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse json of maintenance mode audit")
+	})
+}
+
+// newOptimisticLockingClientSet returns a fake clientset that rejects updates of config maps with an outdated resource
+// version like the API server does.
+func newOptimisticLockingClientSet(objects ...runtime.Object) *fake.Clientset {
+	clientSet := fake.NewSimpleClientset(objects...)
+	gvr := v1.SchemeGroupVersion.WithResource("configmaps")
+
+	clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updated := action.(k8stesting.UpdateAction).GetObject().(*v1.ConfigMap).DeepCopy()
+		updated.Namespace = action.GetNamespace()
+
+		current, err := clientSet.Tracker().Get(gvr, updated.Namespace, updated.Name)
+		if err != nil {
+			return true, nil, err
+		}
+
+		currentVersion := current.(*v1.ConfigMap).ResourceVersion
+		if updated.ResourceVersion != currentVersion {
+			return true, nil, k8serrors.NewConflict(gvr.GroupResource(), updated.Name, fmt.Errorf("resource version %s is outdated", updated.ResourceVersion))
+		}
+
+		version, _ := strconv.Atoi(currentVersion)
+		updated.ResourceVersion = strconv.Itoa(version + 1)
+
+		return true, updated, clientSet.Tracker().Update(gvr, updated, updated.Namespace)
+	})
+
+	return clientSet
+}
+
+func newGlobalConfigMap(entries string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "global-config",
+			Namespace:       "ecosystem",
+			ResourceVersion: "1",
+			Labels:          map[string]string{appLabelKey: appLabelValueCes, typeLabelKey: globalConfigType.String()},
+		},
+		Data: map[string]string{dataKeyName: entries},
+	}
+}
+
+// slowReadConfigMapClient delays every read to widen the window for concurrent modifications.
+type slowReadConfigMapClient struct {
+	ConfigMapClient
+	delay time.Duration
+}
+
+func (c *slowReadConfigMapClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ConfigMap, error) {
+	configMap, err := c.ConfigMapClient.Get(ctx, name, opts)
+	time.Sleep(c.delay)
+	return configMap, err
+}
+
+func (c *slowReadConfigMapClient) List(ctx context.Context, opts metav1.ListOptions) (*v1.ConfigMapList, error) {
+	list, err := c.ConfigMapClient.List(ctx, opts)
+	time.Sleep(c.delay)
+	return list, err
+}
+
+func TestMaintenanceModeAdapter_RetryOnConflict(t *testing.T) {
+	t.Run("should retry activation on conflict of unrelated key", func(t *testing.T) {
+		// given
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		client := clientSet.CoreV1().ConfigMaps("ecosystem")
+
+		unrelatedWriteDone := false
+		clientSet.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if unrelatedWriteDone {
+				return false, nil, nil
+			}
+
+			// modify another key right after the adapter has read the global config
+			unrelatedWriteDone = true
+			gvr := v1.SchemeGroupVersion.WithResource("configmaps")
+			obj, err := clientSet.Tracker().Get(gvr, "ecosystem", "global-config")
+			require.NoError(t, err)
+			current := obj.(*v1.ConfigMap)
+			modified := current.DeepCopy()
+			modified.Data[dataKeyName] = "fqdn: other.example.com\n"
+			modified.ResourceVersion = "2"
+			require.NoError(t, clientSet.Tracker().Update(gvr, modified, "ecosystem"))
+
+			return true, &v1.ConfigMapList{Items: []v1.ConfigMap{*current}}, nil
+		})
+
+		sut := NewMaintenanceModeAdapter("k8s-blueprint-operator", client)
+
+		// when
+		err := sut.Activate(testCtx, MaintenanceModeDescription{Title: "title", Text: "text"})
+
+		// then
+		require.NoError(t, err)
+		cm, err := client.Get(testCtx, "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "3", cm.ResourceVersion)
+		assert.Equal(t, "fqdn: other.example.com\nmaintenance: '{\"title\":\"title\",\"text\":\"text\",\"holder\":\"k8s-blueprint-operator\"}'\n", cm.Data[dataKeyName])
+	})
+
+	t.Run("should retry until all of many competing writers succeeded", func(t *testing.T) {
+		// given
+		const competitors = 20
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		// delay reads, so that all writers read the same version of the global config before anyone updates it
+		client := &slowReadConfigMapClient{ConfigMapClient: clientSet.CoreV1().ConfigMaps("ecosystem"), delay: 5 * time.Millisecond}
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		errs := make([]error, competitors)
+		for i := 0; i < competitors; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				adapter := NewMaintenanceModeAdapter(fmt.Sprintf("component-%d", i), client)
+
+				<-start
+				errs[i] = adapter.ActivateShared(testCtx, MaintenanceModeDescription{Title: "title", Text: fmt.Sprintf("component-%d", i)})
+			}(i)
+		}
+
+		// when
+		close(start)
+		wg.Wait()
+
+		// then
+		for i, err := range errs {
+			assert.NoError(t, err, "component-%d", i)
+		}
+		status, err := NewMaintenanceModeAdapter("reader", client).Get(testCtx)
+		require.NoError(t, err)
+		assert.Len(t, status.Holders, competitors)
+	})
+
+	t.Run("should fail with generic error after repeated conflicts", func(t *testing.T) {
+		// given
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewConflict(v1.Resource("configmaps"), "global-config", assert.AnError)
+		})
+
+		sut := NewMaintenanceModeAdapter("k8s-blueprint-operator", clientSet.CoreV1().ConfigMaps("ecosystem"))
+
+		// when
+		err := sut.Deactivate(testCtx)
+		require.NoError(t, err)
+		err = sut.Activate(testCtx, MaintenanceModeDescription{Title: "title", Text: "text"})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.False(t, errors.IsConflictError(err))
+		assert.ErrorContains(t, err, "could not update global config-map because of repeated concurrent modifications")
+	})
+}
+
+func TestMaintenanceModeAdapter_Concurrency(t *testing.T) {
+	const competitors = 20
+
+	clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+	client := clientSet.CoreV1().ConfigMaps("ecosystem")
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make([]error, competitors)
+
+	for i := 0; i < competitors; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			adapter := NewMaintenanceModeAdapter(fmt.Sprintf("component-%d", i), client)
+
+			<-start
+			errs[i] = adapter.Activate(testCtx, MaintenanceModeDescription{Title: "title", Text: fmt.Sprintf("component-%d", i)})
+		}(i)
+	}
+
+	// unrelated writes to the global config must not let any activation fail
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		repo := NewGlobalConfigRepository(client)
+
+		<-start
+		for i := 0; i < 5; i++ {
+			_ = retry.OnError(retry.DefaultRetry, errors.IsConflictError, func() error {
+				globalConfig, err := repo.Get(testCtx)
+				if err != nil {
+					return err
+				}
+
+				updatedConfig, err := globalConfig.Set("counter", config.Value(strconv.Itoa(i)))
+				if err != nil {
+					return err
+				}
+
+				_, err = repo.Update(testCtx, config.GlobalConfig{Config: updatedConfig})
+				return err
+			})
+		}
+	}()
+
+	close(start)
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			assert.Equal(t, -1, winner, "only one component must activate the maintenance mode")
+			winner = i
+			continue
+		}
+
+		assert.True(t, errors.IsConflictError(err), "component-%d failed with unexpected error: %v", i, err)
+	}
+	require.NotEqual(t, -1, winner, "one component must activate the maintenance mode")
+
+	status, err := NewMaintenanceModeAdapter("reader", client).Get(testCtx)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("component-%d", winner), status.Holder)
+	assert.Equal(t, fmt.Sprintf("component-%d", winner), status.Description.Text)
+}
+
+func TestMaintenanceModeAdapter_Shared(t *testing.T) {
+	t.Run("should stay active until last holder released shared maintenance mode", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		doguOperator := NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+		backupOperator := NewMaintenanceModeAdapter("k8s-backup-operator", client)
+		doguDescription := MaintenanceModeDescription{Title: "Upgrade", Text: "Dogus are upgraded"}
+		backupDescription := MaintenanceModeDescription{Title: "Backup", Text: "A backup is created"}
+
+		// when
+		require.NoError(t, doguOperator.ActivateShared(testCtx, doguDescription))
+		require.NoError(t, backupOperator.ActivateShared(testCtx, backupDescription))
+
+		// then
+		status, err := doguOperator.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, MaintenanceModeStatus{
+			Active:      true,
+			Shared:      true,
+			Holder:      "k8s-dogu-operator",
+			Description: doguDescription,
+			Holders: []MaintenanceModeHolder{
+				{Holder: "k8s-dogu-operator", Description: doguDescription},
+				{Holder: "k8s-backup-operator", Description: backupDescription},
+			},
+		}, status)
+
+		// when
+		require.NoError(t, doguOperator.DeactivateShared(testCtx))
+
+		// then
+		status, err = doguOperator.Get(testCtx)
+		require.NoError(t, err)
+		assert.True(t, status.Active)
+		assert.Equal(t, "k8s-backup-operator", status.Holder)
+		assert.Equal(t, backupDescription, status.Description)
+		assert.Len(t, status.Holders, 1)
+
+		// when
+		require.NoError(t, backupOperator.DeactivateShared(testCtx))
+
+		// then
+		isActive, err := doguOperator.IsActive(testCtx)
+		require.NoError(t, err)
+		assert.False(t, isActive)
+	})
+
+	t.Run("should replace description of registered holder", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		sut := NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+		require.NoError(t, sut.ActivateShared(testCtx, MaintenanceModeDescription{Title: "title", Text: "text"}))
+
+		// when
+		err := sut.ActivateShared(testCtx, MaintenanceModeDescription{Title: "other", Text: "text"})
+
+		// then
+		require.NoError(t, err)
+		status, err := sut.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []MaintenanceModeHolder{{Holder: "k8s-dogu-operator", Description: MaintenanceModeDescription{Title: "other", Text: "text"}}}, status.Holders)
+	})
+
+	t.Run("should not mix exclusive and shared maintenance mode", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		exclusive := NewMaintenanceModeAdapter("k8s-blueprint-operator", client)
+		shared := NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+		require.NoError(t, exclusive.Activate(testCtx, MaintenanceModeDescription{Title: "title", Text: "text"}))
+
+		// when
+		activateErr := shared.ActivateShared(testCtx, MaintenanceModeDescription{})
+		deactivateErr := shared.DeactivateShared(testCtx)
+
+		// then
+		assert.True(t, errors.IsConflictError(activateErr))
+		assert.ErrorContains(t, activateErr, "is exclusively activated by owner: k8s-blueprint-operator")
+		assert.True(t, errors.IsConflictError(deactivateErr))
+
+		// given
+		require.NoError(t, exclusive.Deactivate(testCtx))
+		require.NoError(t, shared.ActivateShared(testCtx, MaintenanceModeDescription{}))
+
+		// when
+		activateErr = exclusive.Activate(testCtx, MaintenanceModeDescription{})
+		deactivateErr = exclusive.Deactivate(testCtx)
+
+		// then
+		assert.True(t, errors.IsConflictError(activateErr))
+		assert.ErrorContains(t, activateErr, "is shared by the holders: k8s-dogu-operator")
+		assert.True(t, errors.IsConflictError(deactivateErr))
+	})
+
+	t.Run("should list shared holders with the same separator in conflicts and audits", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		require.NoError(t, NewMaintenanceModeAdapter("k8s-dogu-operator", client).ActivateShared(testCtx, MaintenanceModeDescription{}))
+		require.NoError(t, NewMaintenanceModeAdapter("k8s-backup-operator", client).ActivateShared(testCtx, MaintenanceModeDescription{}))
+		admin := NewMaintenanceModeAdapter("admin-tool", client)
+
+		// when
+		activateErr := admin.Activate(testCtx, MaintenanceModeDescription{})
+		deactivateErr := admin.ForceDeactivate(testCtx, "cleanup")
+
+		// then
+		assert.ErrorContains(t, activateErr, "is shared by the holders: k8s-dogu-operator, k8s-backup-operator")
+		require.NoError(t, deactivateErr)
+		audit, ok, err := admin.LastForceDeactivation(testCtx)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "k8s-dogu-operator, k8s-backup-operator", audit.PreviousHolder)
+	})
+
+	t.Run("should ignore release of unregistered holder", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		require.NoError(t, NewMaintenanceModeAdapter("k8s-dogu-operator", client).ActivateShared(testCtx, MaintenanceModeDescription{}))
+
+		// when
+		err := NewMaintenanceModeAdapter("k8s-backup-operator", client).DeactivateShared(testCtx)
+
+		// then
+		require.NoError(t, err)
+		isActive, err := NewMaintenanceModeAdapter("reader", client).IsActive(testCtx)
+		require.NoError(t, err)
+		assert.True(t, isActive)
+	})
+
+	t.Run("should register all concurrent holders", func(t *testing.T) {
+		// given
+		const holders = 10
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+
+		// when
+		var wg sync.WaitGroup
+		errs := make([]error, holders)
+		for i := 0; i < holders; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = NewMaintenanceModeAdapter(fmt.Sprintf("component-%d", i), client).ActivateShared(testCtx, MaintenanceModeDescription{})
+			}(i)
+		}
+		wg.Wait()
+
+		// then
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		status, err := NewMaintenanceModeAdapter("reader", client).Get(testCtx)
+		require.NoError(t, err)
+		assert.Len(t, status.Holders, holders)
+	})
+}
+
+func TestMaintenanceModeDescription_Get(t *testing.T) {
+	description := MaintenanceModeDescription{
+		Title:            "Maintenance",
+		Text:             "The ecosystem is in maintenance",
+		FallbackLanguage: "en",
+		Translations: map[string]LocalizedMaintenanceModeDescription{
+			"de":    {Title: "Wartung", Text: "Das Ecosystem wird gewartet"},
+			"de-AT": {Title: "Wartung (AT)", Text: "Das Ecosystem wird gewartet (AT)"},
+			"en":    {Title: "Maintenance (en)", Text: "The ecosystem is in maintenance (en)"},
+			"fr-CA": {Title: "Maintenance (CA)", Text: "L'écosystème est en maintenance"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		description MaintenanceModeDescription
+		lang        string
+		want        LocalizedMaintenanceModeDescription
+	}{
+		{name: "exact language tag", description: description, lang: "de-AT", want: description.Translations["de-AT"]},
+		{name: "case-insensitive language tag", description: description, lang: "de_at", want: description.Translations["de-AT"]},
+		{name: "base language", description: description, lang: "de-CH", want: description.Translations["de"]},
+		{name: "same base language", description: description, lang: "fr", want: description.Translations["fr-CA"]},
+		{name: "fallback language", description: description, lang: "it", want: description.Translations["en"]},
+		{name: "fallback language for empty language tag", description: description, lang: "", want: description.Translations["en"]},
+		{
+			name:        "default without fallback language",
+			description: MaintenanceModeDescription{Title: "title", Text: "text", Translations: description.Translations},
+			lang:        "it",
+			want:        LocalizedMaintenanceModeDescription{Title: "title", Text: "text"},
+		},
+		{
+			name:        "default without translations",
+			description: MaintenanceModeDescription{Title: "title", Text: "text"},
+			lang:        "de",
+			want:        LocalizedMaintenanceModeDescription{Title: "title", Text: "text"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.description.Get(tt.lang))
+		})
+	}
+}
+
+func TestMaintenanceModeAdapter_Localized(t *testing.T) {
+	translations := map[string]LocalizedMaintenanceModeDescription{
+		"de": {Title: "Wartung", Text: "Das Ecosystem wird gewartet"},
+		"en": {Title: "Maintenance", Text: "The ecosystem is in maintenance"},
+	}
+
+	t.Run("should store localized description backward-compatible", func(t *testing.T) {
+		// given
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		client := clientSet.CoreV1().ConfigMaps("ecosystem")
+		sut := NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+
+		// when
+		err := sut.Activate(testCtx, MaintenanceModeDescription{FallbackLanguage: "en", Translations: translations})
+
+		// then
+		require.NoError(t, err)
+
+		status, err := sut.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, translations["de"], status.Description.Get("de-DE"))
+		assert.Equal(t, translations["en"], status.Description.Get("it"))
+
+		globalConfig, err := NewGlobalConfigRepository(client).Get(testCtx)
+		require.NoError(t, err)
+		rawValue, ok := globalConfig.Get(registryKeyMaintenance)
+		require.True(t, ok)
+		assert.Contains(t, string(rawValue), `"title":"Maintenance","text":"The ecosystem is in maintenance"`)
+
+		// a reader without support for localization only knows title and text
+		var legacyDescription struct {
+			Title string `json:"title"`
+			Text  string `json:"text"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(rawValue), &legacyDescription))
+		assert.Equal(t, "Maintenance", legacyDescription.Title)
+		assert.Equal(t, "The ecosystem is in maintenance", legacyDescription.Text)
+	})
+
+	t.Run("should use first translation as default without fallback language", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		sut := NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+
+		// when
+		err := sut.ActivateShared(testCtx, MaintenanceModeDescription{Translations: translations})
+
+		// then
+		require.NoError(t, err)
+		status, err := sut.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "Wartung", status.Description.Title)
+		assert.Equal(t, translations, status.Holders[0].Description.Translations)
+	})
+
+	t.Run("should read description written by older versions", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap(
+			"maintenance: '{\"title\":\"title\",\"text\":\"text\",\"holder\":\"k8s-dogu-operator\"}'\n",
+		)).CoreV1().ConfigMaps("ecosystem")
+
+		// when
+		status, err := NewMaintenanceModeAdapter("reader", client).Get(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, MaintenanceModeDescription{Title: "title", Text: "text"}, status.Description)
+		assert.Equal(t, LocalizedMaintenanceModeDescription{Title: "title", Text: "text"}, status.Description.Get("de"))
 	})
 }
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+const registryKeyMaintenanceWindows = "maintenance_windows"
+
+// MaintenanceWindow is a scheduled period of time in which the maintenance mode is active.
+// The window starts at Start (inclusive) and ends at End (exclusive).
+type MaintenanceWindow struct {
+	ID          string                     `json:"id"`
+	Start       time.Time                  `json:"start"`
+	End         time.Time                  `json:"end"`
+	Description MaintenanceModeDescription `json:"description"`
+}
+
+// IsActiveAt returns true if the given time lies within the window.
+func (mw MaintenanceWindow) IsActiveAt(now time.Time) bool {
+	return !now.Before(mw.Start) && now.Before(mw.End)
+}
+
+func (mw MaintenanceWindow) hasEndedAt(now time.Time) bool {
+	return !now.Before(mw.End)
+}
+
+func (mw MaintenanceWindow) validate() error {
+	if mw.ID == "" {
+		return fmt.Errorf("maintenance window must have an id")
+	}
+	if !mw.End.After(mw.Start) {
+		return fmt.Errorf("end %s of maintenance window %s must be after its start %s", mw.End, mw.ID, mw.Start)
+	}
+
+	return nil
+}
+
+// MaintenanceWindowRepository stores the scheduled maintenance windows in the global config, so that they can be
+// announced ahead of time.
+type MaintenanceWindowRepository struct {
+	globalConfigRepo *GlobalConfigRepository
+	// now returns the current time to detect ended windows. time.Now is used if it is not set.
+	now func() time.Time
+}
+
+// NewMaintenanceWindowRepository creates a new repository for the scheduled maintenance windows.
+func NewMaintenanceWindowRepository(client ConfigMapClient) *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{
+		globalConfigRepo: NewGlobalConfigRepository(client),
+		now:              time.Now,
+	}
+}
+
+// List returns all maintenance windows ordered by their start.
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mwr *MaintenanceWindowRepository) List(ctx context.Context) ([]MaintenanceWindow, error) {
+	globalConfig, err := mwr.globalConfigRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get contents of global config-map for reading maintenance windows: %w", handleError(err))
+	}
+
+	return parseMaintenanceWindows(globalConfig)
+}
+
+// Add schedules a new maintenance window. Windows that have already ended are removed, so that they do not pile up
+// in the global config.
+// AlreadyExistsError if a window with the same id exists
+// ConnectionError at any connection issues
+// Generic Error at any other issue, e.g. if the window has already ended
+func (mwr *MaintenanceWindowRepository) Add(ctx context.Context, window MaintenanceWindow) error {
+	if err := window.validate(); err != nil {
+		return errors.NewGenericError(err)
+	}
+
+	now := mwr.currentTime()
+	if window.hasEndedAt(now) {
+		return errors.NewGenericError(fmt.Errorf("maintenance window %s has already ended at %s", window.ID, window.End))
+	}
+
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		globalConfig, err := mwr.globalConfigRepo.Get(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get contents of global config-map for adding maintenance window: %w", handleError(err))
+		}
+
+		windows, err := parseMaintenanceWindows(globalConfig)
+		if err != nil {
+			return err
+		}
+
+		windows = slices.DeleteFunc(windows, func(w MaintenanceWindow) bool { return w.hasEndedAt(now) })
+		if slices.ContainsFunc(windows, func(w MaintenanceWindow) bool { return w.ID == window.ID }) {
+			return errors.NewAlreadyExistsError(fmt.Errorf("maintenance window %s already exists", window.ID))
+		}
+
+		return mwr.saveMaintenanceWindows(ctx, globalConfig, append(windows, window))
+	})
+}
+
+// Remove deletes the maintenance window with the given id.
+// NotFoundError if no window with the id exists
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mwr *MaintenanceWindowRepository) Remove(ctx context.Context, id string) error {
+	return retryOnUpdateConflict(ctx, func(ctx context.Context) error {
+		globalConfig, err := mwr.globalConfigRepo.Get(ctx)
+		if err != nil {
+			return fmt.Errorf("could not get contents of global config-map for removing maintenance window: %w", handleError(err))
+		}
+
+		windows, err := parseMaintenanceWindows(globalConfig)
+		if err != nil {
+			return err
+		}
+
+		remaining := slices.DeleteFunc(windows, func(w MaintenanceWindow) bool { return w.ID == id })
+		if len(remaining) == len(windows) {
+			return errors.NewNotFoundError(fmt.Errorf("maintenance window %s does not exist", id))
+		}
+
+		return mwr.saveMaintenanceWindows(ctx, globalConfig, remaining)
+	})
+}
+
+func (mwr *MaintenanceWindowRepository) saveMaintenanceWindows(ctx context.Context, globalConfig config.GlobalConfig, windows []MaintenanceWindow) error {
+	var updatedConfig config.Config
+	if len(windows) == 0 {
+		updatedConfig = globalConfig.Delete(registryKeyMaintenanceWindows)
+	} else {
+		sortMaintenanceWindows(windows)
+
+		jsonBytes, err := json.Marshal(windows)
+		if err != nil {
+			return errors.NewGenericError(fmt.Errorf("failed to serialize maintenance windows: %w", err))
+		}
+
+		updatedConfig, err = globalConfig.Set(registryKeyMaintenanceWindows, config.Value(jsonBytes))
+		if err != nil {
+			return errors.NewGenericError(fmt.Errorf("failed to set maintenance windows registry key: %w", err))
+		}
+	}
+
+	_, err := mwr.globalConfigRepo.Update(ctx, config.GlobalConfig{Config: updatedConfig})
+	if errors.IsConflictError(err) {
+		return &globalConfigUpdateConflict{cause: err}
+	}
+	if err != nil {
+		return fmt.Errorf("could not update global config-map for saving maintenance windows: %w", handleError(err))
+	}
+
+	return nil
+}
+
+func (mwr *MaintenanceWindowRepository) currentTime() time.Time {
+	if mwr.now == nil {
+		return time.Now()
+	}
+
+	return mwr.now()
+}
+
+func parseMaintenanceWindows(globalConfig config.GlobalConfig) ([]MaintenanceWindow, error) {
+	rawValue, ok := globalConfig.Get(registryKeyMaintenanceWindows)
+	if !ok {
+		return nil, nil
+	}
+
+	var windows []MaintenanceWindow
+	err := json.Unmarshal([]byte(rawValue), &windows)
+	if err != nil {
+		return nil, errors.NewGenericError(fmt.Errorf("failed to parse json of maintenance windows: %w", err))
+	}
+
+	sortMaintenanceWindows(windows)
+
+	return windows, nil
+}
+
+func sortMaintenanceWindows(windows []MaintenanceWindow) {
+	slices.SortStableFunc(windows, func(a, b MaintenanceWindow) int {
+		return a.Start.Compare(b.Start)
+	})
+}
+
+// MaintenanceWindowScheduler activates the maintenance mode at the start of each maintenance window and deactivates
+// it at its end. The maintenance mode is activated with a TTL until the end of the window, so that it is released
+// even if the scheduler is not running anymore. A maintenance mode held by another component is never changed.
+type MaintenanceWindowScheduler struct {
+	windows  *MaintenanceWindowRepository
+	adapter  *MaintenanceModeAdapter
+	interval time.Duration
+	// now returns the current time. time.Now is used if it is not set.
+	now func() time.Time
+}
+
+// NewMaintenanceWindowScheduler creates a scheduler that checks the maintenance windows in the given interval and
+// switches the maintenance mode with the given adapter. The owner of the adapter should be used for the scheduler only.
+func NewMaintenanceWindowScheduler(windows *MaintenanceWindowRepository, adapter *MaintenanceModeAdapter, interval time.Duration) *MaintenanceWindowScheduler {
+	return &MaintenanceWindowScheduler{
+		windows:  windows,
+		adapter:  adapter,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run checks the maintenance windows in the interval of the scheduler until the context is done.
+// Errors are logged and the check is repeated in the next interval.
+func (mws *MaintenanceWindowScheduler) Run(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("MaintenanceWindowScheduler.Run")
+
+	ticker := time.NewTicker(mws.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := mws.Reconcile(ctx); err != nil {
+			logger.Error(err, "failed to switch maintenance mode for maintenance windows")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile activates the maintenance mode if a maintenance window is active and deactivates it if the scheduler
+// holds it without an active window. If several windows are active, the one that started first is used.
+// ConflictError if another component holds the maintenance mode during an active window
+// ConnectionError at any connection issues
+// Generic Error at any other issue
+func (mws *MaintenanceWindowScheduler) Reconcile(ctx context.Context) error {
+	now := mws.currentTime()
+
+	windows, err := mws.windows.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	status, err := mws.adapter.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	activeIndex := slices.IndexFunc(windows, func(w MaintenanceWindow) bool { return w.IsActiveAt(now) })
+	if activeIndex < 0 {
+		if status.Active && !status.Shared && status.Holder == mws.adapter.Owner() {
+			return mws.adapter.Deactivate(ctx)
+		}
+
+		return nil
+	}
+
+	window := windows[activeIndex]
+	// the lease is calculated from the time of the activation, so it may differ slightly from the end of the window
+	isActivatedForWindow := status.Active && !status.Shared && status.Holder == mws.adapter.Owner() &&
+		reflect.DeepEqual(status.Description, window.Description.withDefault()) && status.ExpiresAt.Sub(window.End).Abs() < time.Second
+	if isActivatedForWindow {
+		return nil
+	}
+
+	return mws.adapter.ActivateWithTTL(ctx, window.Description, window.End.Sub(now))
+}
+
+func (mws *MaintenanceWindowScheduler) currentTime() time.Time {
+	if mws.now == nil {
+		return time.Now()
+	}
+
+	return mws.now()
+}
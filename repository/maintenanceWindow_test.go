@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-registry-lib/errors"
+)
+
+var (
+	testWindowStart = time.Date(2024, 10, 19, 2, 0, 0, 0, time.UTC)
+	testWindow      = MaintenanceWindow{
+		ID:          "upgrade",
+		Start:       testWindowStart,
+		End:         testWindowStart.Add(2 * time.Hour),
+		Description: MaintenanceModeDescription{Title: "Upgrade", Text: "The ecosystem is upgraded"},
+	}
+)
+
+// newTestMaintenanceWindowRepository creates a repository whose clock is set to a day before the earliest test window.
+func newTestMaintenanceWindowRepository(client ConfigMapClient) *MaintenanceWindowRepository {
+	sut := NewMaintenanceWindowRepository(client)
+	sut.now = func() time.Time { return testWindowStart.Add(-48 * time.Hour) }
+
+	return sut
+}
+
+func TestMaintenanceWindow_IsActiveAt(t *testing.T) {
+	assert.False(t, testWindow.IsActiveAt(testWindowStart.Add(-time.Nanosecond)))
+	assert.True(t, testWindow.IsActiveAt(testWindowStart))
+	assert.True(t, testWindow.IsActiveAt(testWindow.End.Add(-time.Nanosecond)))
+	assert.False(t, testWindow.IsActiveAt(testWindow.End))
+}
+
+func TestMaintenanceWindowRepository(t *testing.T) {
+	t.Run("should add, list and remove maintenance windows", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		sut := newTestMaintenanceWindowRepository(client)
+		earlierWindow := MaintenanceWindow{ID: "backup", Start: testWindowStart.Add(-24 * time.Hour), End: testWindowStart.Add(-23 * time.Hour)}
+
+		// when
+		require.NoError(t, sut.Add(testCtx, testWindow))
+		require.NoError(t, sut.Add(testCtx, earlierWindow))
+
+		// then
+		windows, err := sut.List(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []MaintenanceWindow{earlierWindow, testWindow}, windows)
+
+		cm, err := client.Get(testCtx, "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, cm.Data[dataKeyName], `"description":{"title":"Upgrade","text":"The ecosystem is upgraded"}`)
+
+		// when
+		require.NoError(t, sut.Remove(testCtx, "backup"))
+		require.NoError(t, sut.Remove(testCtx, "upgrade"))
+
+		// then
+		windows, err = sut.List(testCtx)
+		require.NoError(t, err)
+		assert.Empty(t, windows)
+
+		cm, err = client.Get(testCtx, "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "fqdn: example.com\n", cm.Data[dataKeyName])
+	})
+
+	t.Run("should fail to add existing maintenance window", func(t *testing.T) {
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		sut := newTestMaintenanceWindowRepository(client)
+		require.NoError(t, sut.Add(testCtx, testWindow))
+
+		err := sut.Add(testCtx, testWindow)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsAlreadyExistsError(err))
+	})
+
+	t.Run("should remove ended maintenance windows on add", func(t *testing.T) {
+		// given
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+		sut := newTestMaintenanceWindowRepository(client)
+		require.NoError(t, sut.Add(testCtx, testWindow))
+		sut.now = func() time.Time { return testWindow.End }
+		laterWindow := MaintenanceWindow{ID: "backup", Start: testWindow.End.Add(time.Hour), End: testWindow.End.Add(2 * time.Hour)}
+
+		// when
+		err := sut.Add(testCtx, laterWindow)
+
+		// then
+		require.NoError(t, err)
+		windows, err := sut.List(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []MaintenanceWindow{laterWindow}, windows)
+	})
+
+	t.Run("should fail to add ended maintenance window", func(t *testing.T) {
+		sut := NewMaintenanceWindowRepository(nil)
+		sut.now = func() time.Time { return testWindow.End }
+
+		err := sut.Add(testCtx, testWindow)
+
+		require.Error(t, err)
+		assert.True(t, errors.IsGenericError(err))
+		assert.ErrorContains(t, err, "maintenance window upgrade has already ended")
+	})
+
+	t.Run("should fail to add invalid maintenance window", func(t *testing.T) {
+		sut := NewMaintenanceWindowRepository(nil)
+
+		errWithoutId := sut.Add(testCtx, MaintenanceWindow{Start: testWindowStart, End: testWindow.End})
+		errWithInvalidEnd := sut.Add(testCtx, MaintenanceWindow{ID: "invalid", Start: testWindowStart, End: testWindowStart})
+
+		assert.ErrorContains(t, errWithoutId, "maintenance window must have an id")
+		assert.ErrorContains(t, errWithInvalidEnd, "of maintenance window invalid must be after its start")
+		assert.True(t, errors.IsGenericError(errWithInvalidEnd))
+	})
+
+	t.Run("should fail to remove missing maintenance window", func(t *testing.T) {
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n")).CoreV1().ConfigMaps("ecosystem")
+
+		err := NewMaintenanceWindowRepository(client).Remove(testCtx, "missing")
+
+		require.Error(t, err)
+		assert.True(t, errors.IsNotFoundError(err))
+	})
+
+	t.Run("should fail to parse maintenance windows", func(t *testing.T) {
+		client := newOptimisticLockingClientSet(newGlobalConfigMap("maintenance_windows: '['\n")).CoreV1().ConfigMaps("ecosystem")
+
+		_, err := NewMaintenanceWindowRepository(client).List(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse json of maintenance windows")
+	})
+}
+
+func TestMaintenanceWindowScheduler_Reconcile(t *testing.T) {
+	newScheduler := func(t *testing.T, now *time.Time) (*MaintenanceWindowScheduler, *MaintenanceModeAdapter) {
+		clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+		client := clientSet.CoreV1().ConfigMaps("ecosystem")
+
+		windows := newTestMaintenanceWindowRepository(client)
+		require.NoError(t, windows.Add(testCtx, testWindow))
+
+		adapter := NewMaintenanceModeAdapter("maintenance-window-scheduler", client)
+		adapter.now = func() time.Time { return *now }
+
+		scheduler := NewMaintenanceWindowScheduler(windows, adapter, time.Minute)
+		scheduler.now = func() time.Time { return *now }
+
+		return scheduler, NewMaintenanceModeAdapter("k8s-dogu-operator", client)
+	}
+
+	t.Run("should switch maintenance mode at window boundaries", func(t *testing.T) {
+		// given
+		now := testWindowStart.Add(-time.Minute)
+		sut, reader := newScheduler(t, &now)
+
+		// when before window
+		require.NoError(t, sut.Reconcile(testCtx))
+
+		// then
+		status, err := reader.Get(testCtx)
+		require.NoError(t, err)
+		assert.False(t, status.Active)
+
+		// when in window
+		now = testWindowStart
+		require.NoError(t, sut.Reconcile(testCtx))
+
+		// then
+		status, err = reader.Get(testCtx)
+		require.NoError(t, err)
+		assert.True(t, status.Active)
+		assert.Equal(t, "maintenance-window-scheduler", status.Holder)
+		assert.Equal(t, testWindow.Description, status.Description)
+		assert.Equal(t, testWindow.End, status.ExpiresAt)
+
+		// when still in window
+		now = testWindowStart.Add(time.Hour)
+		require.NoError(t, sut.Reconcile(testCtx))
+
+		// then the lease is not changed
+		status, err = reader.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, testWindow.End, status.ExpiresAt)
+
+		// when after window
+		now = testWindow.End
+		require.NoError(t, sut.Reconcile(testCtx))
+
+		// then
+		status, err = reader.Get(testCtx)
+		require.NoError(t, err)
+		assert.False(t, status.Active)
+	})
+
+	t.Run("should not change maintenance mode of other holder", func(t *testing.T) {
+		// given
+		now := testWindowStart.Add(-time.Minute)
+		sut, other := newScheduler(t, &now)
+		require.NoError(t, other.Activate(testCtx, MaintenanceModeDescription{Title: "Dogu upgrade"}))
+
+		// when before window
+		err := sut.Reconcile(testCtx)
+
+		// then
+		require.NoError(t, err)
+
+		// when in window
+		now = testWindowStart
+		err = sut.Reconcile(testCtx)
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.IsConflictError(err))
+		status, err := other.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "k8s-dogu-operator", status.Holder)
+	})
+}
+
+func TestMaintenanceWindowScheduler_Run(t *testing.T) {
+	// given
+	clientSet := newOptimisticLockingClientSet(newGlobalConfigMap("fqdn: example.com\n"))
+	client := clientSet.CoreV1().ConfigMaps("ecosystem")
+	windows := NewMaintenanceWindowRepository(client)
+	require.NoError(t, windows.Add(testCtx, MaintenanceWindow{ID: "now", Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)}))
+
+	sut := NewMaintenanceWindowScheduler(windows, NewMaintenanceModeAdapter("maintenance-window-scheduler", client), 10*time.Millisecond)
+	reader := NewMaintenanceModeAdapter("reader", client)
+
+	ctx, cancel := context.WithCancel(testCtx)
+	done := make(chan struct{})
+
+	// when
+	go func() {
+		sut.Run(ctx)
+		close(done)
+	}()
+
+	// then
+	assert.Eventually(t, func() bool {
+		isActive, err := reader.IsActive(testCtx)
+		return err == nil && isActive
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("scheduler did not stop after the context was done")
+	}
+}
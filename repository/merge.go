@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+)
+
+// MergeStrategy defines how SaveOrMerge resolves keys that were changed locally and remotely since the config was read.
+type MergeStrategy int
+
+const (
+	// LocalWins applies all local changes to the remote config, even if a key was changed remotely in the meantime.
+	// This is the default strategy.
+	LocalWins MergeStrategy = iota
+	// RemoteWins applies local changes only to keys that were not changed remotely in the meantime.
+	RemoteWins
+	// FailOnConflict applies local changes only if none of the changed keys was changed differently in the remote
+	// config. Otherwise, a *MergeConflictError listing all conflicting keys is returned and nothing is written.
+	FailOnConflict
+)
+
+// String returns the string representation of the MergeStrategy.
+func (ms MergeStrategy) String() string {
+	switch ms {
+	case LocalWins:
+		return "LocalWins"
+	case RemoteWins:
+		return "RemoteWins"
+	case FailOnConflict:
+		return "FailOnConflict"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(ms))
+	}
+}
+
+// KeyConflict describes a key that was changed locally and remotely to different values.
+// Base contains the value the local config was read with.
+type KeyConflict struct {
+	Key    config.Key
+	Base   config.OptionalValue
+	Local  config.OptionalValue
+	Remote config.OptionalValue
+}
+
+// String returns a human-readable representation of the KeyConflict.
+func (kc KeyConflict) String() string {
+	return fmt.Sprintf("key %q (base: %s, local: %s, remote: %s)", kc.Key, formatOptionalValue(kc.Base), formatOptionalValue(kc.Local), formatOptionalValue(kc.Remote))
+}
+
+func formatOptionalValue(value config.OptionalValue) string {
+	if !value.Exists {
+		return "<none>"
+	}
+
+	return fmt.Sprintf("%q", value.String)
+}
+
+// MergeConflictError is returned by SaveOrMerge with the strategy FailOnConflict if local changes conflict with
+// remote changes.
+type MergeConflictError struct {
+	Conflicts []KeyConflict
+}
+
+// Error returns all conflicting keys as a single string.
+func (mce *MergeConflictError) Error() string {
+	conflicts := make([]string, 0, len(mce.Conflicts))
+	for _, c := range mce.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+
+	return fmt.Sprintf("merge conflict on %d key(s): %s", len(mce.Conflicts), strings.Join(conflicts, "; "))
+}
+
+// mergeConfigData applies the changes of the local config to the remote config data. The base of the local config is
+// used as common ancestor to detect remote changes of the locally changed keys.
+func mergeConfigData(remoteCfgData config.Entries, localCfg config.Config, strategy MergeStrategy) (config.Entries, error) {
+	if strategy < LocalWins || strategy > FailOnConflict {
+		return nil, fmt.Errorf("unsupported merge strategy %s", strategy)
+	}
+
+	baseCfgData := localCfg.GetBase()
+
+	var conflicts []KeyConflict
+	for _, key := range changedKeys(localCfg) {
+		localValue, localExists := localCfg.Get(key)
+		local := config.OptionalValue{String: localValue.String(), Exists: localExists}
+		base := lookupOptionalValue(baseCfgData, key)
+		// keys that were set to their base value again are not changed locally, so the remote value is kept
+		if local == base {
+			continue
+		}
+
+		remote := lookupOptionalValue(remoteCfgData, key)
+
+		remoteChanged := remote != base
+		if remoteChanged && remote != local {
+			switch strategy {
+			case RemoteWins:
+				continue
+			case FailOnConflict:
+				conflicts = append(conflicts, KeyConflict{Key: key, Base: base, Local: local, Remote: remote})
+				continue
+			}
+		}
+
+		if local.Exists {
+			remoteCfgData[key] = localValue
+		} else {
+			delete(remoteCfgData, key)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &MergeConflictError{Conflicts: conflicts}
+	}
+
+	return remoteCfgData, nil
+}
+
+// changedKeys returns every key of the change history once, in the order of their first change.
+func changedKeys(cfg config.Config) []config.Key {
+	seen := make(map[config.Key]struct{})
+	var keys []config.Key
+	for _, c := range cfg.GetChangeHistory() {
+		if _, ok := seen[c.KeyPath]; ok {
+			continue
+		}
+
+		seen[c.KeyPath] = struct{}{}
+		keys = append(keys, c.KeyPath)
+	}
+
+	return keys
+}
+
+func lookupOptionalValue(entries config.Entries, key config.Key) config.OptionalValue {
+	value, ok := entries[key]
+	return config.OptionalValue{String: value.String(), Exists: ok}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeStrategy_String(t *testing.T) {
+	assert.Equal(t, "LocalWins", LocalWins.String())
+	assert.Equal(t, "RemoteWins", RemoteWins.String())
+	assert.Equal(t, "FailOnConflict", FailOnConflict.String())
+	assert.Equal(t, "MergeStrategy(42)", MergeStrategy(42).String())
+}
+
+func TestMergeConflictError_Error(t *testing.T) {
+	err := &MergeConflictError{Conflicts: []KeyConflict{
+		{
+			Key:    "key1",
+			Base:   config.OptionalValue{String: "base", Exists: true},
+			Local:  config.OptionalValue{String: "local", Exists: true},
+			Remote: config.OptionalValue{String: "remote", Exists: true},
+		},
+		{
+			Key:    "key2",
+			Local:  config.OptionalValue{String: "local", Exists: true},
+			Remote: config.OptionalValue{Exists: false},
+		},
+	}}
+
+	assert.Equal(t, `merge conflict on 2 key(s): key "key1" (base: "base", local: "local", remote: "remote"); key "key2" (base: <none>, local: "local", remote: <none>)`, err.Error())
+}
+
+func Test_mergeConfigData_strategies(t *testing.T) {
+	// base: key1=base1, key2=base2, key3=base3, key4=base4
+	// local: key1 changed, key2 changed, key3 deleted, key5 added
+	// remote: key1 changed differently, key2 unchanged, key3 changed, key4 changed, key5 added with the same value
+	createLocalConfig := func(t *testing.T) config.Config {
+		cfg := config.CreateConfig(config.Entries{"key1": "base1", "key2": "base2", "key3": "base3", "key4": "base4"})
+		cfg, err := cfg.Set("key1", "local1")
+		require.NoError(t, err)
+		cfg, err = cfg.Set("key2", "local2")
+		require.NoError(t, err)
+		cfg = cfg.Delete("key3")
+		cfg, err = cfg.Set("key5", "new5")
+		require.NoError(t, err)
+
+		return cfg
+	}
+	createRemoteData := func() config.Entries {
+		return config.Entries{"key1": "remote1", "key2": "base2", "key3": "remote3", "key4": "remote4", "key5": "new5"}
+	}
+
+	t.Run("local wins", func(t *testing.T) {
+		result, err := mergeConfigData(createRemoteData(), createLocalConfig(t), LocalWins)
+
+		require.NoError(t, err)
+		assert.Equal(t, config.Entries{"key1": "local1", "key2": "local2", "key4": "remote4", "key5": "new5"}, result)
+	})
+
+	t.Run("remote wins", func(t *testing.T) {
+		result, err := mergeConfigData(createRemoteData(), createLocalConfig(t), RemoteWins)
+
+		require.NoError(t, err)
+		assert.Equal(t, config.Entries{"key1": "remote1", "key2": "local2", "key3": "remote3", "key4": "remote4", "key5": "new5"}, result)
+	})
+
+	t.Run("fail on conflict", func(t *testing.T) {
+		_, err := mergeConfigData(createRemoteData(), createLocalConfig(t), FailOnConflict)
+
+		var conflictErr *MergeConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, []KeyConflict{
+			{
+				Key:    "key1",
+				Base:   config.OptionalValue{String: "base1", Exists: true},
+				Local:  config.OptionalValue{String: "local1", Exists: true},
+				Remote: config.OptionalValue{String: "remote1", Exists: true},
+			},
+			{
+				Key:    "key3",
+				Base:   config.OptionalValue{String: "base3", Exists: true},
+				Local:  config.OptionalValue{Exists: false},
+				Remote: config.OptionalValue{String: "remote3", Exists: true},
+			},
+		}, conflictErr.Conflicts)
+	})
+
+	t.Run("fail on conflict without conflicts", func(t *testing.T) {
+		remoteData := config.Entries{"key1": "base1", "key2": "base2", "key3": "base3", "key4": "remote4"}
+
+		result, err := mergeConfigData(remoteData, createLocalConfig(t), FailOnConflict)
+
+		require.NoError(t, err)
+		assert.Equal(t, config.Entries{"key1": "local1", "key2": "local2", "key4": "remote4", "key5": "new5"}, result)
+	})
+
+	t.Run("key set and deleted locally", func(t *testing.T) {
+		cfg, err := config.CreateConfig(config.Entries{}).Set("key", "value")
+		require.NoError(t, err)
+		cfg = cfg.Delete("key")
+
+		result, err := mergeConfigData(config.Entries{"other": "value"}, cfg, FailOnConflict)
+
+		require.NoError(t, err)
+		assert.Equal(t, config.Entries{"other": "value"}, result)
+	})
+
+	for _, strategy := range []MergeStrategy{LocalWins, RemoteWins, FailOnConflict} {
+		t.Run("key set to base value with "+strategy.String(), func(t *testing.T) {
+			cfg, err := config.CreateConfig(config.Entries{"key": "base"}).Set("key", "base")
+			require.NoError(t, err)
+
+			result, err := mergeConfigData(config.Entries{"key": "remote"}, cfg, strategy)
+
+			require.NoError(t, err)
+			assert.Equal(t, config.Entries{"key": "remote"}, result)
+		})
+
+		t.Run("key set and reverted with "+strategy.String(), func(t *testing.T) {
+			cfg, err := config.CreateConfig(config.Entries{"key": "base"}).Set("key", "local")
+			require.NoError(t, err)
+			cfg, err = cfg.Set("key", "base")
+			require.NoError(t, err)
+
+			result, err := mergeConfigData(config.Entries{"key": "remote"}, cfg, strategy)
+
+			require.NoError(t, err)
+			assert.Equal(t, config.Entries{"key": "remote"}, result)
+		})
+	}
+
+	t.Run("unsupported strategy", func(t *testing.T) {
+		_, err := mergeConfigData(createRemoteData(), createLocalConfig(t), MergeStrategy(42))
+
+		assert.ErrorContains(t, err, "unsupported merge strategy MergeStrategy(42)")
+	})
+}
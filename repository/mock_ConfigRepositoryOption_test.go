@@ -0,0 +1,65 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockConfigRepositoryOption is an autogenerated mock type for the ConfigRepositoryOption type
+type MockConfigRepositoryOption struct {
+	mock.Mock
+}
+
+type MockConfigRepositoryOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockConfigRepositoryOption) EXPECT() *MockConfigRepositoryOption_Expecter {
+	return &MockConfigRepositoryOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *MockConfigRepositoryOption) Execute(options *configRepositoryOptions) {
+	_m.Called(options)
+}
+
+// MockConfigRepositoryOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockConfigRepositoryOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *configRepositoryOptions
+func (_e *MockConfigRepositoryOption_Expecter) Execute(options interface{}) *MockConfigRepositoryOption_Execute_Call {
+	return &MockConfigRepositoryOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *MockConfigRepositoryOption_Execute_Call) Run(run func(options *configRepositoryOptions)) *MockConfigRepositoryOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*configRepositoryOptions))
+	})
+	return _c
+}
+
+func (_c *MockConfigRepositoryOption_Execute_Call) Return() *MockConfigRepositoryOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockConfigRepositoryOption_Execute_Call) RunAndReturn(run func(*configRepositoryOptions)) *MockConfigRepositoryOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockConfigRepositoryOption creates a new instance of MockConfigRepositoryOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockConfigRepositoryOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockConfigRepositoryOption {
+	mock := &MockConfigRepositoryOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
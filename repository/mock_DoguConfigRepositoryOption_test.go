@@ -0,0 +1,65 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockDoguConfigRepositoryOption is an autogenerated mock type for the DoguConfigRepositoryOption type
+type MockDoguConfigRepositoryOption struct {
+	mock.Mock
+}
+
+type MockDoguConfigRepositoryOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockDoguConfigRepositoryOption) EXPECT() *MockDoguConfigRepositoryOption_Expecter {
+	return &MockDoguConfigRepositoryOption_Expecter{mock: &_m.Mock}
+}
+
+// applyToDoguConfigRepository provides a mock function with given fields: options
+func (_m *MockDoguConfigRepositoryOption) applyToDoguConfigRepository(options *doguConfigRepositoryOptions) {
+	_m.Called(options)
+}
+
+// MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'applyToDoguConfigRepository'
+type MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call struct {
+	*mock.Call
+}
+
+// applyToDoguConfigRepository is a helper method to define mock.On call
+//   - options *doguConfigRepositoryOptions
+func (_e *MockDoguConfigRepositoryOption_Expecter) applyToDoguConfigRepository(options interface{}) *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call {
+	return &MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call{Call: _e.mock.On("applyToDoguConfigRepository", options)}
+}
+
+func (_c *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call) Run(run func(options *doguConfigRepositoryOptions)) *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*doguConfigRepositoryOptions))
+	})
+	return _c
+}
+
+func (_c *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call) Return() *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call) RunAndReturn(run func(*doguConfigRepositoryOptions)) *MockDoguConfigRepositoryOption_applyToDoguConfigRepository_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockDoguConfigRepositoryOption creates a new instance of MockDoguConfigRepositoryOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDoguConfigRepositoryOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDoguConfigRepositoryOption {
+	mock := &MockDoguConfigRepositoryOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,576 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "k8s.io/apimachinery/pkg/types"
+
+	v1 "k8s.io/client-go/applyconfigurations/coordination/v1"
+
+	watch "k8s.io/apimachinery/pkg/watch"
+)
+
+// MockLeaseClient is an autogenerated mock type for the LeaseClient type
+type MockLeaseClient struct {
+	mock.Mock
+}
+
+type MockLeaseClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLeaseClient) EXPECT() *MockLeaseClient_Expecter {
+	return &MockLeaseClient_Expecter{mock: &_m.Mock}
+}
+
+// Apply provides a mock function with given fields: ctx, lease, opts
+func (_m *MockLeaseClient) Apply(ctx context.Context, lease *v1.LeaseApplyConfiguration, opts metav1.ApplyOptions) (*coordinationv1.Lease, error) {
+	ret := _m.Called(ctx, lease, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Apply")
+	}
+
+	var r0 *coordinationv1.Lease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.LeaseApplyConfiguration, metav1.ApplyOptions) (*coordinationv1.Lease, error)); ok {
+		return rf(ctx, lease, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.LeaseApplyConfiguration, metav1.ApplyOptions) *coordinationv1.Lease); ok {
+		r0 = rf(ctx, lease, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.Lease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.LeaseApplyConfiguration, metav1.ApplyOptions) error); ok {
+		r1 = rf(ctx, lease, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Apply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Apply'
+type MockLeaseClient_Apply_Call struct {
+	*mock.Call
+}
+
+// Apply is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lease *v1.LeaseApplyConfiguration
+//   - opts metav1.ApplyOptions
+func (_e *MockLeaseClient_Expecter) Apply(ctx interface{}, lease interface{}, opts interface{}) *MockLeaseClient_Apply_Call {
+	return &MockLeaseClient_Apply_Call{Call: _e.mock.On("Apply", ctx, lease, opts)}
+}
+
+func (_c *MockLeaseClient_Apply_Call) Run(run func(ctx context.Context, lease *v1.LeaseApplyConfiguration, opts metav1.ApplyOptions)) *MockLeaseClient_Apply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1.LeaseApplyConfiguration), args[2].(metav1.ApplyOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Apply_Call) Return(result *coordinationv1.Lease, err error) *MockLeaseClient_Apply_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockLeaseClient_Apply_Call) RunAndReturn(run func(context.Context, *v1.LeaseApplyConfiguration, metav1.ApplyOptions) (*coordinationv1.Lease, error)) *MockLeaseClient_Apply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: ctx, lease, opts
+func (_m *MockLeaseClient) Create(ctx context.Context, lease *coordinationv1.Lease, opts metav1.CreateOptions) (*coordinationv1.Lease, error) {
+	ret := _m.Called(ctx, lease, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *coordinationv1.Lease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *coordinationv1.Lease, metav1.CreateOptions) (*coordinationv1.Lease, error)); ok {
+		return rf(ctx, lease, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *coordinationv1.Lease, metav1.CreateOptions) *coordinationv1.Lease); ok {
+		r0 = rf(ctx, lease, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.Lease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *coordinationv1.Lease, metav1.CreateOptions) error); ok {
+		r1 = rf(ctx, lease, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockLeaseClient_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lease *coordinationv1.Lease
+//   - opts metav1.CreateOptions
+func (_e *MockLeaseClient_Expecter) Create(ctx interface{}, lease interface{}, opts interface{}) *MockLeaseClient_Create_Call {
+	return &MockLeaseClient_Create_Call{Call: _e.mock.On("Create", ctx, lease, opts)}
+}
+
+func (_c *MockLeaseClient_Create_Call) Run(run func(ctx context.Context, lease *coordinationv1.Lease, opts metav1.CreateOptions)) *MockLeaseClient_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*coordinationv1.Lease), args[2].(metav1.CreateOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Create_Call) Return(_a0 *coordinationv1.Lease, _a1 error) *MockLeaseClient_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLeaseClient_Create_Call) RunAndReturn(run func(context.Context, *coordinationv1.Lease, metav1.CreateOptions) (*coordinationv1.Lease, error)) *MockLeaseClient_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, name, opts
+func (_m *MockLeaseClient) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	ret := _m.Called(ctx, name, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.DeleteOptions) error); ok {
+		r0 = rf(ctx, name, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLeaseClient_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockLeaseClient_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - opts metav1.DeleteOptions
+func (_e *MockLeaseClient_Expecter) Delete(ctx interface{}, name interface{}, opts interface{}) *MockLeaseClient_Delete_Call {
+	return &MockLeaseClient_Delete_Call{Call: _e.mock.On("Delete", ctx, name, opts)}
+}
+
+func (_c *MockLeaseClient_Delete_Call) Run(run func(ctx context.Context, name string, opts metav1.DeleteOptions)) *MockLeaseClient_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(metav1.DeleteOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Delete_Call) Return(_a0 error) *MockLeaseClient_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLeaseClient_Delete_Call) RunAndReturn(run func(context.Context, string, metav1.DeleteOptions) error) *MockLeaseClient_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCollection provides a mock function with given fields: ctx, opts, listOpts
+func (_m *MockLeaseClient) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	ret := _m.Called(ctx, opts, listOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCollection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, metav1.DeleteOptions, metav1.ListOptions) error); ok {
+		r0 = rf(ctx, opts, listOpts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLeaseClient_DeleteCollection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCollection'
+type MockLeaseClient_DeleteCollection_Call struct {
+	*mock.Call
+}
+
+// DeleteCollection is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts metav1.DeleteOptions
+//   - listOpts metav1.ListOptions
+func (_e *MockLeaseClient_Expecter) DeleteCollection(ctx interface{}, opts interface{}, listOpts interface{}) *MockLeaseClient_DeleteCollection_Call {
+	return &MockLeaseClient_DeleteCollection_Call{Call: _e.mock.On("DeleteCollection", ctx, opts, listOpts)}
+}
+
+func (_c *MockLeaseClient_DeleteCollection_Call) Run(run func(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions)) *MockLeaseClient_DeleteCollection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(metav1.DeleteOptions), args[2].(metav1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_DeleteCollection_Call) Return(_a0 error) *MockLeaseClient_DeleteCollection_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLeaseClient_DeleteCollection_Call) RunAndReturn(run func(context.Context, metav1.DeleteOptions, metav1.ListOptions) error) *MockLeaseClient_DeleteCollection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, name, opts
+func (_m *MockLeaseClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*coordinationv1.Lease, error) {
+	ret := _m.Called(ctx, name, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *coordinationv1.Lease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.GetOptions) (*coordinationv1.Lease, error)); ok {
+		return rf(ctx, name, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.GetOptions) *coordinationv1.Lease); ok {
+		r0 = rf(ctx, name, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.Lease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.GetOptions) error); ok {
+		r1 = rf(ctx, name, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockLeaseClient_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - opts metav1.GetOptions
+func (_e *MockLeaseClient_Expecter) Get(ctx interface{}, name interface{}, opts interface{}) *MockLeaseClient_Get_Call {
+	return &MockLeaseClient_Get_Call{Call: _e.mock.On("Get", ctx, name, opts)}
+}
+
+func (_c *MockLeaseClient_Get_Call) Run(run func(ctx context.Context, name string, opts metav1.GetOptions)) *MockLeaseClient_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(metav1.GetOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Get_Call) Return(_a0 *coordinationv1.Lease, _a1 error) *MockLeaseClient_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLeaseClient_Get_Call) RunAndReturn(run func(context.Context, string, metav1.GetOptions) (*coordinationv1.Lease, error)) *MockLeaseClient_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, opts
+func (_m *MockLeaseClient) List(ctx context.Context, opts metav1.ListOptions) (*coordinationv1.LeaseList, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *coordinationv1.LeaseList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, metav1.ListOptions) (*coordinationv1.LeaseList, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, metav1.ListOptions) *coordinationv1.LeaseList); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.LeaseList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockLeaseClient_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts metav1.ListOptions
+func (_e *MockLeaseClient_Expecter) List(ctx interface{}, opts interface{}) *MockLeaseClient_List_Call {
+	return &MockLeaseClient_List_Call{Call: _e.mock.On("List", ctx, opts)}
+}
+
+func (_c *MockLeaseClient_List_Call) Run(run func(ctx context.Context, opts metav1.ListOptions)) *MockLeaseClient_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(metav1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_List_Call) Return(_a0 *coordinationv1.LeaseList, _a1 error) *MockLeaseClient_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLeaseClient_List_Call) RunAndReturn(run func(context.Context, metav1.ListOptions) (*coordinationv1.LeaseList, error)) *MockLeaseClient_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Patch provides a mock function with given fields: ctx, name, pt, data, opts, subresources
+func (_m *MockLeaseClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*coordinationv1.Lease, error) {
+	_va := make([]interface{}, len(subresources))
+	for _i := range subresources {
+		_va[_i] = subresources[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name, pt, data, opts)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 *coordinationv1.Lease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) (*coordinationv1.Lease, error)); ok {
+		return rf(ctx, name, pt, data, opts, subresources...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) *coordinationv1.Lease); ok {
+		r0 = rf(ctx, name, pt, data, opts, subresources...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.Lease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) error); ok {
+		r1 = rf(ctx, name, pt, data, opts, subresources...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type MockLeaseClient_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - pt types.PatchType
+//   - data []byte
+//   - opts metav1.PatchOptions
+//   - subresources ...string
+func (_e *MockLeaseClient_Expecter) Patch(ctx interface{}, name interface{}, pt interface{}, data interface{}, opts interface{}, subresources ...interface{}) *MockLeaseClient_Patch_Call {
+	return &MockLeaseClient_Patch_Call{Call: _e.mock.On("Patch",
+		append([]interface{}{ctx, name, pt, data, opts}, subresources...)...)}
+}
+
+func (_c *MockLeaseClient_Patch_Call) Run(run func(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string)) *MockLeaseClient_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-5)
+		for i, a := range args[5:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(types.PatchType), args[3].([]byte), args[4].(metav1.PatchOptions), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Patch_Call) Return(result *coordinationv1.Lease, err error) *MockLeaseClient_Patch_Call {
+	_c.Call.Return(result, err)
+	return _c
+}
+
+func (_c *MockLeaseClient_Patch_Call) RunAndReturn(run func(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) (*coordinationv1.Lease, error)) *MockLeaseClient_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, lease, opts
+func (_m *MockLeaseClient) Update(ctx context.Context, lease *coordinationv1.Lease, opts metav1.UpdateOptions) (*coordinationv1.Lease, error) {
+	ret := _m.Called(ctx, lease, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *coordinationv1.Lease
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *coordinationv1.Lease, metav1.UpdateOptions) (*coordinationv1.Lease, error)); ok {
+		return rf(ctx, lease, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *coordinationv1.Lease, metav1.UpdateOptions) *coordinationv1.Lease); ok {
+		r0 = rf(ctx, lease, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coordinationv1.Lease)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *coordinationv1.Lease, metav1.UpdateOptions) error); ok {
+		r1 = rf(ctx, lease, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockLeaseClient_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lease *coordinationv1.Lease
+//   - opts metav1.UpdateOptions
+func (_e *MockLeaseClient_Expecter) Update(ctx interface{}, lease interface{}, opts interface{}) *MockLeaseClient_Update_Call {
+	return &MockLeaseClient_Update_Call{Call: _e.mock.On("Update", ctx, lease, opts)}
+}
+
+func (_c *MockLeaseClient_Update_Call) Run(run func(ctx context.Context, lease *coordinationv1.Lease, opts metav1.UpdateOptions)) *MockLeaseClient_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*coordinationv1.Lease), args[2].(metav1.UpdateOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Update_Call) Return(_a0 *coordinationv1.Lease, _a1 error) *MockLeaseClient_Update_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLeaseClient_Update_Call) RunAndReturn(run func(context.Context, *coordinationv1.Lease, metav1.UpdateOptions) (*coordinationv1.Lease, error)) *MockLeaseClient_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Watch provides a mock function with given fields: ctx, opts
+func (_m *MockLeaseClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, metav1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, metav1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, metav1.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLeaseClient_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
+type MockLeaseClient_Watch_Call struct {
+	*mock.Call
+}
+
+// Watch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts metav1.ListOptions
+func (_e *MockLeaseClient_Expecter) Watch(ctx interface{}, opts interface{}) *MockLeaseClient_Watch_Call {
+	return &MockLeaseClient_Watch_Call{Call: _e.mock.On("Watch", ctx, opts)}
+}
+
+func (_c *MockLeaseClient_Watch_Call) Run(run func(ctx context.Context, opts metav1.ListOptions)) *MockLeaseClient_Watch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(metav1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *MockLeaseClient_Watch_Call) Return(_a0 watch.Interface, _a1 error) *MockLeaseClient_Watch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLeaseClient_Watch_Call) RunAndReturn(run func(context.Context, metav1.ListOptions) (watch.Interface, error)) *MockLeaseClient_Watch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLeaseClient creates a new instance of MockLeaseClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLeaseClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLeaseClient {
+	mock := &MockLeaseClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
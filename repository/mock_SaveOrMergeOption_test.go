@@ -0,0 +1,65 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockSaveOrMergeOption is an autogenerated mock type for the SaveOrMergeOption type
+type MockSaveOrMergeOption struct {
+	mock.Mock
+}
+
+type MockSaveOrMergeOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSaveOrMergeOption) EXPECT() *MockSaveOrMergeOption_Expecter {
+	return &MockSaveOrMergeOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *MockSaveOrMergeOption) Execute(options *saveOrMergeOptions) {
+	_m.Called(options)
+}
+
+// MockSaveOrMergeOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockSaveOrMergeOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *saveOrMergeOptions
+func (_e *MockSaveOrMergeOption_Expecter) Execute(options interface{}) *MockSaveOrMergeOption_Execute_Call {
+	return &MockSaveOrMergeOption_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *MockSaveOrMergeOption_Execute_Call) Run(run func(options *saveOrMergeOptions)) *MockSaveOrMergeOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*saveOrMergeOptions))
+	})
+	return _c
+}
+
+func (_c *MockSaveOrMergeOption_Execute_Call) Return() *MockSaveOrMergeOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockSaveOrMergeOption_Execute_Call) RunAndReturn(run func(*saveOrMergeOptions)) *MockSaveOrMergeOption_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSaveOrMergeOption creates a new instance of MockSaveOrMergeOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSaveOrMergeOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSaveOrMergeOption {
+	mock := &MockSaveOrMergeOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
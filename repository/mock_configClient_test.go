@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+	labels "k8s.io/apimachinery/pkg/labels"
 )
 
 // mockConfigClient is an autogenerated mock type for the configClient type
@@ -250,6 +251,72 @@ func (_c *mockConfigClient_GetWithListResourceVersion_Call) RunAndReturn(run fun
 	return _c
 }
 
+// List provides a mock function with given fields: ctx, selector
+func (_m *mockConfigClient) List(ctx context.Context, selector labels.Selector) ([]clientData, string, error) {
+	ret := _m.Called(ctx, selector)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []clientData
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector) ([]clientData, string, error)); ok {
+		return rf(ctx, selector)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector) []clientData); ok {
+		r0 = rf(ctx, selector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]clientData)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, labels.Selector) string); ok {
+		r1 = rf(ctx, selector)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, labels.Selector) error); ok {
+		r2 = rf(ctx, selector)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// mockConfigClient_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockConfigClient_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selector labels.Selector
+func (_e *mockConfigClient_Expecter) List(ctx interface{}, selector interface{}) *mockConfigClient_List_Call {
+	return &mockConfigClient_List_Call{Call: _e.mock.On("List", ctx, selector)}
+}
+
+func (_c *mockConfigClient_List_Call) Run(run func(ctx context.Context, selector labels.Selector)) *mockConfigClient_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(labels.Selector))
+	})
+	return _c
+}
+
+func (_c *mockConfigClient_List_Call) Return(_a0 []clientData, _a1 string, _a2 error) *mockConfigClient_List_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *mockConfigClient_List_Call) RunAndReturn(run func(context.Context, labels.Selector) ([]clientData, string, error)) *mockConfigClient_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function with given fields: ctx, pCtx, name, doguName, dataStr
 func (_m *mockConfigClient) Update(ctx context.Context, pCtx string, name string, doguName string, dataStr string) (resourceVersionGetter, error) {
 	ret := _m.Called(ctx, pCtx, name, doguName, dataStr)
@@ -431,6 +498,66 @@ func (_c *mockConfigClient_Watch_Call) RunAndReturn(run func(context.Context, st
 	return _c
 }
 
+// WatchAll provides a mock function with given fields: ctx, selector, resourceVersion
+func (_m *mockConfigClient) WatchAll(ctx context.Context, selector labels.Selector, resourceVersion string) (<-chan clientWatchResult, error) {
+	ret := _m.Called(ctx, selector, resourceVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchAll")
+	}
+
+	var r0 <-chan clientWatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector, string) (<-chan clientWatchResult, error)); ok {
+		return rf(ctx, selector, resourceVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector, string) <-chan clientWatchResult); ok {
+		r0 = rf(ctx, selector, resourceVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan clientWatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, labels.Selector, string) error); ok {
+		r1 = rf(ctx, selector, resourceVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockConfigClient_WatchAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WatchAll'
+type mockConfigClient_WatchAll_Call struct {
+	*mock.Call
+}
+
+// WatchAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selector labels.Selector
+//   - resourceVersion string
+func (_e *mockConfigClient_Expecter) WatchAll(ctx interface{}, selector interface{}, resourceVersion interface{}) *mockConfigClient_WatchAll_Call {
+	return &mockConfigClient_WatchAll_Call{Call: _e.mock.On("WatchAll", ctx, selector, resourceVersion)}
+}
+
+func (_c *mockConfigClient_WatchAll_Call) Run(run func(ctx context.Context, selector labels.Selector, resourceVersion string)) *mockConfigClient_WatchAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(labels.Selector), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockConfigClient_WatchAll_Call) Return(_a0 <-chan clientWatchResult, _a1 error) *mockConfigClient_WatchAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockConfigClient_WatchAll_Call) RunAndReturn(run func(context.Context, labels.Selector, string) (<-chan clientWatchResult, error)) *mockConfigClient_WatchAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockConfigClient creates a new instance of mockConfigClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockConfigClient(t interface {
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import mock "github.com/stretchr/testify/mock"
+
+// mockDoguConfigRepositoryOptionFunc is an autogenerated mock type for the doguConfigRepositoryOptionFunc type
+type mockDoguConfigRepositoryOptionFunc struct {
+	mock.Mock
+}
+
+type mockDoguConfigRepositoryOptionFunc_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDoguConfigRepositoryOptionFunc) EXPECT() *mockDoguConfigRepositoryOptionFunc_Expecter {
+	return &mockDoguConfigRepositoryOptionFunc_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: options
+func (_m *mockDoguConfigRepositoryOptionFunc) Execute(options *doguConfigRepositoryOptions) {
+	_m.Called(options)
+}
+
+// mockDoguConfigRepositoryOptionFunc_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type mockDoguConfigRepositoryOptionFunc_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - options *doguConfigRepositoryOptions
+func (_e *mockDoguConfigRepositoryOptionFunc_Expecter) Execute(options interface{}) *mockDoguConfigRepositoryOptionFunc_Execute_Call {
+	return &mockDoguConfigRepositoryOptionFunc_Execute_Call{Call: _e.mock.On("Execute", options)}
+}
+
+func (_c *mockDoguConfigRepositoryOptionFunc_Execute_Call) Run(run func(options *doguConfigRepositoryOptions)) *mockDoguConfigRepositoryOptionFunc_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*doguConfigRepositoryOptions))
+	})
+	return _c
+}
+
+func (_c *mockDoguConfigRepositoryOptionFunc_Execute_Call) Return() *mockDoguConfigRepositoryOptionFunc_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *mockDoguConfigRepositoryOptionFunc_Execute_Call) RunAndReturn(run func(*doguConfigRepositoryOptions)) *mockDoguConfigRepositoryOptionFunc_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDoguConfigRepositoryOptionFunc creates a new instance of mockDoguConfigRepositoryOptionFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDoguConfigRepositoryOptionFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDoguConfigRepositoryOptionFunc {
+	mock := &mockDoguConfigRepositoryOptionFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	config "github.com/cloudogu/k8s-registry-lib/config"
 
+	labels "k8s.io/apimachinery/pkg/labels"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -23,6 +25,80 @@ func (_m *mockGeneralConfigRepository) EXPECT() *mockGeneralConfigRepository_Exp
 	return &mockGeneralConfigRepository_Expecter{mock: &_m.Mock}
 }
 
+// awaitAndWatch provides a mock function with given fields: ctx, name, filters
+func (_m *mockGeneralConfigRepository) awaitAndWatch(ctx context.Context, name configName, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for awaitAndWatch")
+	}
+
+	var r0 <-chan configWatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, configName, ...config.WatchFilter) (<-chan configWatchResult, error)); ok {
+		return rf(ctx, name, filters...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, configName, ...config.WatchFilter) <-chan configWatchResult); ok {
+		r0 = rf(ctx, name, filters...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan configWatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, configName, ...config.WatchFilter) error); ok {
+		r1 = rf(ctx, name, filters...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockGeneralConfigRepository_awaitAndWatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'awaitAndWatch'
+type mockGeneralConfigRepository_awaitAndWatch_Call struct {
+	*mock.Call
+}
+
+// awaitAndWatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name configName
+//   - filters ...config.WatchFilter
+func (_e *mockGeneralConfigRepository_Expecter) awaitAndWatch(ctx interface{}, name interface{}, filters ...interface{}) *mockGeneralConfigRepository_awaitAndWatch_Call {
+	return &mockGeneralConfigRepository_awaitAndWatch_Call{Call: _e.mock.On("awaitAndWatch",
+		append([]interface{}{ctx, name}, filters...)...)}
+}
+
+func (_c *mockGeneralConfigRepository_awaitAndWatch_Call) Run(run func(ctx context.Context, name configName, filters ...config.WatchFilter)) *mockGeneralConfigRepository_awaitAndWatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]config.WatchFilter, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(config.WatchFilter)
+			}
+		}
+		run(args[0].(context.Context), args[1].(configName), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_awaitAndWatch_Call) Return(_a0 <-chan configWatchResult, _a1 error) *mockGeneralConfigRepository_awaitAndWatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_awaitAndWatch_Call) RunAndReturn(run func(context.Context, configName, ...config.WatchFilter) (<-chan configWatchResult, error)) *mockGeneralConfigRepository_awaitAndWatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // create provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *mockGeneralConfigRepository) create(_a0 context.Context, _a1 configName, _a2 config.SimpleDoguName, _a3 config.Config) (config.Config, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -186,9 +262,68 @@ func (_c *mockGeneralConfigRepository_get_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
-// saveOrMerge provides a mock function with given fields: _a0, _a1, _a2
-func (_m *mockGeneralConfigRepository) saveOrMerge(_a0 context.Context, _a1 configName, _a2 config.Config) (config.Config, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// list provides a mock function with given fields: _a0, _a1
+func (_m *mockGeneralConfigRepository) list(_a0 context.Context, _a1 labels.Selector) (map[config.SimpleDoguName]config.Config, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for list")
+	}
+
+	var r0 map[config.SimpleDoguName]config.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector) (map[config.SimpleDoguName]config.Config, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector) map[config.SimpleDoguName]config.Config); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[config.SimpleDoguName]config.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, labels.Selector) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockGeneralConfigRepository_list_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'list'
+type mockGeneralConfigRepository_list_Call struct {
+	*mock.Call
+}
+
+// list is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 labels.Selector
+func (_e *mockGeneralConfigRepository_Expecter) list(_a0 interface{}, _a1 interface{}) *mockGeneralConfigRepository_list_Call {
+	return &mockGeneralConfigRepository_list_Call{Call: _e.mock.On("list", _a0, _a1)}
+}
+
+func (_c *mockGeneralConfigRepository_list_Call) Run(run func(_a0 context.Context, _a1 labels.Selector)) *mockGeneralConfigRepository_list_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(labels.Selector))
+	})
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_list_Call) Return(_a0 map[config.SimpleDoguName]config.Config, _a1 error) *mockGeneralConfigRepository_list_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_list_Call) RunAndReturn(run func(context.Context, labels.Selector) (map[config.SimpleDoguName]config.Config, error)) *mockGeneralConfigRepository_list_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// saveOrMerge provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *mockGeneralConfigRepository) saveOrMerge(_a0 context.Context, _a1 configName, _a2 config.Config, _a3 MergeStrategy, _a4 mergedConfigValidator) (config.Config, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
 
 	if len(ret) == 0 {
 		panic("no return value specified for saveOrMerge")
@@ -196,17 +331,17 @@ func (_m *mockGeneralConfigRepository) saveOrMerge(_a0 context.Context, _a1 conf
 
 	var r0 config.Config
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, configName, config.Config) (config.Config, error)); ok {
-		return rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, configName, config.Config, MergeStrategy, mergedConfigValidator) (config.Config, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, configName, config.Config) config.Config); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, configName, config.Config, MergeStrategy, mergedConfigValidator) config.Config); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r0 = ret.Get(0).(config.Config)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, configName, config.Config) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(context.Context, configName, config.Config, MergeStrategy, mergedConfigValidator) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -223,13 +358,15 @@ type mockGeneralConfigRepository_saveOrMerge_Call struct {
 //   - _a0 context.Context
 //   - _a1 configName
 //   - _a2 config.Config
-func (_e *mockGeneralConfigRepository_Expecter) saveOrMerge(_a0 interface{}, _a1 interface{}, _a2 interface{}) *mockGeneralConfigRepository_saveOrMerge_Call {
-	return &mockGeneralConfigRepository_saveOrMerge_Call{Call: _e.mock.On("saveOrMerge", _a0, _a1, _a2)}
+//   - _a3 MergeStrategy
+//   - _a4 mergedConfigValidator
+func (_e *mockGeneralConfigRepository_Expecter) saveOrMerge(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *mockGeneralConfigRepository_saveOrMerge_Call {
+	return &mockGeneralConfigRepository_saveOrMerge_Call{Call: _e.mock.On("saveOrMerge", _a0, _a1, _a2, _a3, _a4)}
 }
 
-func (_c *mockGeneralConfigRepository_saveOrMerge_Call) Run(run func(_a0 context.Context, _a1 configName, _a2 config.Config)) *mockGeneralConfigRepository_saveOrMerge_Call {
+func (_c *mockGeneralConfigRepository_saveOrMerge_Call) Run(run func(_a0 context.Context, _a1 configName, _a2 config.Config, _a3 MergeStrategy, _a4 mergedConfigValidator)) *mockGeneralConfigRepository_saveOrMerge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(configName), args[2].(config.Config))
+		run(args[0].(context.Context), args[1].(configName), args[2].(config.Config), args[3].(MergeStrategy), args[4].(mergedConfigValidator))
 	})
 	return _c
 }
@@ -239,7 +376,7 @@ func (_c *mockGeneralConfigRepository_saveOrMerge_Call) Return(_a0 config.Config
 	return _c
 }
 
-func (_c *mockGeneralConfigRepository_saveOrMerge_Call) RunAndReturn(run func(context.Context, configName, config.Config) (config.Config, error)) *mockGeneralConfigRepository_saveOrMerge_Call {
+func (_c *mockGeneralConfigRepository_saveOrMerge_Call) RunAndReturn(run func(context.Context, configName, config.Config, MergeStrategy, mergedConfigValidator) (config.Config, error)) *mockGeneralConfigRepository_saveOrMerge_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -377,6 +514,80 @@ func (_c *mockGeneralConfigRepository_watch_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// watchAll provides a mock function with given fields: ctx, selector, filters
+func (_m *mockGeneralConfigRepository) watchAll(ctx context.Context, selector labels.Selector, filters ...config.WatchFilter) (<-chan configWatchResult, error) {
+	_va := make([]interface{}, len(filters))
+	for _i := range filters {
+		_va[_i] = filters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, selector)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for watchAll")
+	}
+
+	var r0 <-chan configWatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector, ...config.WatchFilter) (<-chan configWatchResult, error)); ok {
+		return rf(ctx, selector, filters...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, labels.Selector, ...config.WatchFilter) <-chan configWatchResult); ok {
+		r0 = rf(ctx, selector, filters...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan configWatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, labels.Selector, ...config.WatchFilter) error); ok {
+		r1 = rf(ctx, selector, filters...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockGeneralConfigRepository_watchAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'watchAll'
+type mockGeneralConfigRepository_watchAll_Call struct {
+	*mock.Call
+}
+
+// watchAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selector labels.Selector
+//   - filters ...config.WatchFilter
+func (_e *mockGeneralConfigRepository_Expecter) watchAll(ctx interface{}, selector interface{}, filters ...interface{}) *mockGeneralConfigRepository_watchAll_Call {
+	return &mockGeneralConfigRepository_watchAll_Call{Call: _e.mock.On("watchAll",
+		append([]interface{}{ctx, selector}, filters...)...)}
+}
+
+func (_c *mockGeneralConfigRepository_watchAll_Call) Run(run func(ctx context.Context, selector labels.Selector, filters ...config.WatchFilter)) *mockGeneralConfigRepository_watchAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]config.WatchFilter, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(config.WatchFilter)
+			}
+		}
+		run(args[0].(context.Context), args[1].(labels.Selector), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_watchAll_Call) Return(_a0 <-chan configWatchResult, _a1 error) *mockGeneralConfigRepository_watchAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockGeneralConfigRepository_watchAll_Call) RunAndReturn(run func(context.Context, labels.Selector, ...config.WatchFilter) (<-chan configWatchResult, error)) *mockGeneralConfigRepository_watchAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // newMockGeneralConfigRepository creates a new instance of mockGeneralConfigRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func newMockGeneralConfigRepository(t interface {
@@ -0,0 +1,319 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	watch "k8s.io/apimachinery/pkg/watch"
+)
+
+// mockLockClient is an autogenerated mock type for the lockClient type
+type mockLockClient struct {
+	mock.Mock
+}
+
+type mockLockClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockLockClient) EXPECT() *mockLockClient_Expecter {
+	return &mockLockClient_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, name, record
+func (_m *mockLockClient) Create(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	ret := _m.Called(ctx, name, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 lockRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, lockRecord) (lockRecord, error)); ok {
+		return rf(ctx, name, record)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, lockRecord) lockRecord); ok {
+		r0 = rf(ctx, name, record)
+	} else {
+		r0 = ret.Get(0).(lockRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, lockRecord) error); ok {
+		r1 = rf(ctx, name, record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockLockClient_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockLockClient_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - record lockRecord
+func (_e *mockLockClient_Expecter) Create(ctx interface{}, name interface{}, record interface{}) *mockLockClient_Create_Call {
+	return &mockLockClient_Create_Call{Call: _e.mock.On("Create", ctx, name, record)}
+}
+
+func (_c *mockLockClient_Create_Call) Run(run func(ctx context.Context, name string, record lockRecord)) *mockLockClient_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(lockRecord))
+	})
+	return _c
+}
+
+func (_c *mockLockClient_Create_Call) Return(_a0 lockRecord, _a1 error) *mockLockClient_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockLockClient_Create_Call) RunAndReturn(run func(context.Context, string, lockRecord) (lockRecord, error)) *mockLockClient_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, name, resourceVersion
+func (_m *mockLockClient) Delete(ctx context.Context, name string, resourceVersion string) error {
+	ret := _m.Called(ctx, name, resourceVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, resourceVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockLockClient_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockLockClient_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - resourceVersion string
+func (_e *mockLockClient_Expecter) Delete(ctx interface{}, name interface{}, resourceVersion interface{}) *mockLockClient_Delete_Call {
+	return &mockLockClient_Delete_Call{Call: _e.mock.On("Delete", ctx, name, resourceVersion)}
+}
+
+func (_c *mockLockClient_Delete_Call) Run(run func(ctx context.Context, name string, resourceVersion string)) *mockLockClient_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *mockLockClient_Delete_Call) Return(_a0 error) *mockLockClient_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockLockClient_Delete_Call) RunAndReturn(run func(context.Context, string, string) error) *mockLockClient_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, name
+func (_m *mockLockClient) Get(ctx context.Context, name string) (lockRecord, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 lockRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (lockRecord, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) lockRecord); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(lockRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockLockClient_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockLockClient_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *mockLockClient_Expecter) Get(ctx interface{}, name interface{}) *mockLockClient_Get_Call {
+	return &mockLockClient_Get_Call{Call: _e.mock.On("Get", ctx, name)}
+}
+
+func (_c *mockLockClient_Get_Call) Run(run func(ctx context.Context, name string)) *mockLockClient_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *mockLockClient_Get_Call) Return(_a0 lockRecord, _a1 error) *mockLockClient_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockLockClient_Get_Call) RunAndReturn(run func(context.Context, string) (lockRecord, error)) *mockLockClient_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, name, record
+func (_m *mockLockClient) Update(ctx context.Context, name string, record lockRecord) (lockRecord, error) {
+	ret := _m.Called(ctx, name, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 lockRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, lockRecord) (lockRecord, error)); ok {
+		return rf(ctx, name, record)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, lockRecord) lockRecord); ok {
+		r0 = rf(ctx, name, record)
+	} else {
+		r0 = ret.Get(0).(lockRecord)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, lockRecord) error); ok {
+		r1 = rf(ctx, name, record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockLockClient_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockLockClient_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - record lockRecord
+func (_e *mockLockClient_Expecter) Update(ctx interface{}, name interface{}, record interface{}) *mockLockClient_Update_Call {
+	return &mockLockClient_Update_Call{Call: _e.mock.On("Update", ctx, name, record)}
+}
+
+func (_c *mockLockClient_Update_Call) Run(run func(ctx context.Context, name string, record lockRecord)) *mockLockClient_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(lockRecord))
+	})
+	return _c
+}
+
+func (_c *mockLockClient_Update_Call) Return(_a0 lockRecord, _a1 error) *mockLockClient_Update_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockLockClient_Update_Call) RunAndReturn(run func(context.Context, string, lockRecord) (lockRecord, error)) *mockLockClient_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Watch provides a mock function with given fields: ctx, opts
+func (_m *mockLockClient) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockLockClient_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
+type mockLockClient_Watch_Call struct {
+	*mock.Call
+}
+
+// Watch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts v1.ListOptions
+func (_e *mockLockClient_Expecter) Watch(ctx interface{}, opts interface{}) *mockLockClient_Watch_Call {
+	return &mockLockClient_Watch_Call{Call: _e.mock.On("Watch", ctx, opts)}
+}
+
+func (_c *mockLockClient_Watch_Call) Run(run func(ctx context.Context, opts v1.ListOptions)) *mockLockClient_Watch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *mockLockClient_Watch_Call) Return(_a0 watch.Interface, _a1 error) *mockLockClient_Watch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockLockClient_Watch_Call) RunAndReturn(run func(context.Context, v1.ListOptions) (watch.Interface, error)) *mockLockClient_Watch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockLockClient creates a new instance of mockLockClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockLockClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockLockClient {
+	mock := &mockLockClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,81 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package repository
+
+import (
+	config "github.com/cloudogu/k8s-registry-lib/config"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockMergedConfigValidator is an autogenerated mock type for the mergedConfigValidator type
+type mockMergedConfigValidator struct {
+	mock.Mock
+}
+
+type mockMergedConfigValidator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockMergedConfigValidator) EXPECT() *mockMergedConfigValidator_Expecter {
+	return &mockMergedConfigValidator_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: entries
+func (_m *mockMergedConfigValidator) Execute(entries config.Entries) error {
+	ret := _m.Called(entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(config.Entries) error); ok {
+		r0 = rf(entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockMergedConfigValidator_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type mockMergedConfigValidator_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - entries config.Entries
+func (_e *mockMergedConfigValidator_Expecter) Execute(entries interface{}) *mockMergedConfigValidator_Execute_Call {
+	return &mockMergedConfigValidator_Execute_Call{Call: _e.mock.On("Execute", entries)}
+}
+
+func (_c *mockMergedConfigValidator_Execute_Call) Run(run func(entries config.Entries)) *mockMergedConfigValidator_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(config.Entries))
+	})
+	return _c
+}
+
+func (_c *mockMergedConfigValidator_Execute_Call) Return(_a0 error) *mockMergedConfigValidator_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockMergedConfigValidator_Execute_Call) RunAndReturn(run func(config.Entries) error) *mockMergedConfigValidator_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockMergedConfigValidator creates a new instance of mockMergedConfigValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockMergedConfigValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockMergedConfigValidator {
+	mock := &mockMergedConfigValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
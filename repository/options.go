@@ -0,0 +1,112 @@
+package repository
+
+import "github.com/cloudogu/k8s-registry-lib/config"
+
+type configRepositoryOptions struct {
+	converter config.Converter
+	dataKey   string
+}
+
+// ConfigRepositoryOption configures optional behavior of a config repository. It can be used for all config
+// repositories, including the dogu config repositories.
+type ConfigRepositoryOption func(options *configRepositoryOptions)
+
+func (o ConfigRepositoryOption) applyToDoguConfigRepository(options *doguConfigRepositoryOptions) {
+	o(&options.configRepositoryOptions)
+}
+
+type doguConfigRepositoryOptions struct {
+	configRepositoryOptions
+	descriptorGetter DoguDescriptorGetter
+}
+
+// DoguConfigRepositoryOption configures optional behavior of a dogu config repository. Every ConfigRepositoryOption
+// is a DoguConfigRepositoryOption, too, while options like WithSchemaValidation can only be used for dogu config
+// repositories.
+type DoguConfigRepositoryOption interface {
+	applyToDoguConfigRepository(options *doguConfigRepositoryOptions)
+}
+
+type doguConfigRepositoryOptionFunc func(options *doguConfigRepositoryOptions)
+
+func (f doguConfigRepositoryOptionFunc) applyToDoguConfigRepository(options *doguConfigRepositoryOptions) {
+	f(options)
+}
+
+// WithConverter sets the format in which a config repository stores the config entries, e.g. config.JsonConverter.
+// The entries are stored under the key of the config.DataKeyProvider of the converter, e.g. "config.json", or under
+// "config.yaml" if the converter does not provide a key. The default is config.YamlConverter.
+// Repositories with different converters must not share config objects, because each of them only reads its own key.
+// Use the same converter for all readers and writers of a config.
+func WithConverter(converter config.Converter) ConfigRepositoryOption {
+	return func(options *configRepositoryOptions) {
+		options.converter = converter
+	}
+}
+
+// WithSchemaValidation makes a dogu config repository reject writes of configs that violate the configuration fields
+// declared in the descriptor of the currently installed dogu version. The violations are returned as *config.ValidationError.
+func WithSchemaValidation(descriptorGetter DoguDescriptorGetter) DoguConfigRepositoryOption {
+	return doguConfigRepositoryOptionFunc(func(options *doguConfigRepositoryOptions) {
+		options.descriptorGetter = descriptorGetter
+	})
+}
+
+func applyConfigRepositoryOptions(opts []ConfigRepositoryOption) configRepositoryOptions {
+	options := configRepositoryOptions{
+		converter: &config.YamlConverter{},
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	options.dataKey = getDataKey(options.converter)
+
+	return options
+}
+
+func applyDoguConfigRepositoryOptions(opts []DoguConfigRepositoryOption) doguConfigRepositoryOptions {
+	options := doguConfigRepositoryOptions{
+		configRepositoryOptions: configRepositoryOptions{
+			converter: &config.YamlConverter{},
+		},
+	}
+	for _, o := range opts {
+		o.applyToDoguConfigRepository(&options)
+	}
+
+	options.dataKey = getDataKey(options.converter)
+
+	return options
+}
+
+func getDataKey(converter config.Converter) string {
+	if provider, ok := converter.(config.DataKeyProvider); ok {
+		return provider.DataKey()
+	}
+
+	return dataKeyName
+}
+
+type saveOrMergeOptions struct {
+	strategy MergeStrategy
+}
+
+// SaveOrMergeOption configures a single call of SaveOrMerge.
+type SaveOrMergeOption func(options *saveOrMergeOptions)
+
+// WithMergeStrategy sets the strategy to resolve keys that were changed locally and remotely. Default is LocalWins.
+func WithMergeStrategy(strategy MergeStrategy) SaveOrMergeOption {
+	return func(options *saveOrMergeOptions) {
+		options.strategy = strategy
+	}
+}
+
+func applySaveOrMergeOptions(opts []SaveOrMergeOption) saveOrMergeOptions {
+	options := saveOrMergeOptions{strategy: LocalWins}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return options
+}